@@ -8,24 +8,30 @@ import (
 	"github.com/zuxt268/sales/internal/config"
 	"github.com/zuxt268/sales/internal/infrastructure"
 	"github.com/zuxt268/sales/internal/interfaces/repository"
+	"github.com/zuxt268/sales/internal/model"
 	"github.com/zuxt268/sales/internal/util"
 )
 
 func main() {
 	db := infrastructure.NewDatabase()
-	wixRepo := repository.NewWixRepository(db)
+	domainRepo := repository.NewDomainRepository(db)
 	ctx := context.Background()
-	wixes, err := wixRepo.FindAll(ctx, repository.WixFilter{
+	domains, err := domainRepo.FindAll(ctx, repository.DomainFilter{
+		Platform:   util.Pointer(model.PlatformWix),
+		IsJapan:    util.Pointer(true),
 		HasOwnerID: util.Pointer(true),
 	})
 	if err != nil {
 		panic(err)
 	}
-	data := make([][]interface{}, 0, len(wixes))
-	for _, wix := range wixes {
+	data := make([][]interface{}, 0, len(domains))
+	for _, d := range domains {
+		if d.Status == model.StatusTrash {
+			continue
+		}
 		data = append(data, []interface{}{
-			wix.Name,
-			wix.OwnerID,
+			d.Name,
+			d.OwnerID,
 		})
 	}
 
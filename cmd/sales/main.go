@@ -20,6 +20,7 @@ import (
 
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
+	"github.com/redis/go-redis/v9"
 	echoSwagger "github.com/swaggo/echo-swagger"
 )
 
@@ -50,10 +51,24 @@ func main() {
 	driveClient := infrastructure.NewGoogleDriveClient(credPath)
 
 	googleProjectID := os.Getenv("GOOGLE_PROJECT_ID")
-	pubSubClient := infrastructure.NewPubSubClient(googleProjectID, credPath)
+	// Pub/Subはキューとして使う場合だけ接続する
+	var pubSubClient infrastructure.PubSubClient
+	if config.Env.QueueBackend == config.QueueBackendPubSub {
+		pubSubClient = infrastructure.NewPubSubClient(googleProjectID, credPath)
+	}
+
+	// Redisはキュー・LLMのキャッシュ・スケジューラのロックに使う場合だけ接続する
+	var redisClient *redis.Client
+	if di.RedisRequired(true) {
+		redisClient = infrastructure.NewRedisQueue()
+	}
 
 	// 依存性注入
-	handler := di.Initialize(db, sheetClient, driveClient, pubSubClient)
+	handler, scheduler, err := di.Initialize(db, sheetClient, driveClient, pubSubClient, redisClient)
+	if err != nil {
+		slog.Error("Failed to initialize", "error", err)
+		os.Exit(1)
+	}
 
 	// Swagger hostを環境変数から設定
 	docs.SwaggerInfo.Host = config.Env.SwaggerHost
@@ -81,13 +96,19 @@ func main() {
 	api.GET("/domains/:id", handler.GetDomain)
 	api.PUT("/domains/:id", handler.UpdateDomain)
 	api.DELETE("/domains/:id", handler.DeleteDomain)
+	api.GET("/domains/:id/status-histories", handler.GetDomainStatusHistories)
+	api.GET("/domains/:id/sightings", handler.GetDomainSightings)
+	api.POST("/domains/:id/fingerprint", handler.FingerprintDomain)
 	api.POST("/fetch", handler.FetchDomains)
 	api.POST("/polling", handler.PollingDomains)
 	api.POST("/backup", handler.BackupGoogleDrive)
 	api.POST("/domains/analyze", handler.AnalyzeDomains)
 
 	api.GET("/targets", handler.GetTargets)
+	api.GET("/targets/stats", handler.GetTargetsStats)
+	api.GET("/targets/:id/stats", handler.GetTargetStats)
 	api.POST("/targets", handler.CreateTarget)
+	api.POST("/targets/import", handler.ImportTargets)
 	api.PUT("/targets/:id", handler.UpdateTarget)
 	api.DELETE("/targets/:id", handler.DeleteTarget)
 
@@ -104,17 +125,32 @@ func main() {
 
 	api.POST("/homsta", handler.Homsta)
 
-	webhook := api.Group("/webhook")
+	api.GET("/industries", handler.GetIndustries)
+
+	api.GET("/llm/usage", handler.GetLLMUsage)
+	api.DELETE("/llm/cache", handler.ClearLLMCache)
+
+	webhook := api.Group("/webhook", middleware2.PubSubPushMiddleware())
 	{
 		webhook.POST("/analyze", handler.Analyze)
+		webhook.POST("/analyze-domain", handler.AnalyzeDomain)
 	}
 
 	growth := api.Group("/growth")
 	{
 		growth.POST("/fetch", handler.Fetch)
+		growth.GET("/fetch/plan", handler.GetFetchPlan)
 		growth.POST("/polling", handler.Polling)
+		growth.POST("/reap", handler.Reap)
+		growth.POST("/fingerprint", handler.Fingerprint)
 		growth.POST("/output", handler.Output)
 	}
+	api.POST("/outbox/relay", handler.RelayOutbox)
+
+	api.GET("/schedules", handler.GetSchedules)
+	api.PUT("/schedules/:name", handler.UpdateSchedule)
+	api.POST("/schedules/:name/run", handler.TriggerSchedule)
+	api.GET("/schedules/:name/runs", handler.GetScheduleRuns)
 
 	srv := &http.Server{
 		Addr:    config.Env.Address,
@@ -128,6 +164,19 @@ func main() {
 		}
 	}()
 
+	// 定期実行するジョブ。複数台で動かしても同じジョブを同時に実行しない
+	schedulerCtx, stopScheduler := context.WithCancel(context.Background())
+	schedulerDone := make(chan struct{})
+	go func() {
+		defer close(schedulerDone)
+		if !config.Env.SchedulerEnabled {
+			return
+		}
+		if err := scheduler.Run(schedulerCtx); err != nil {
+			slog.Error("Scheduler error", "error", err)
+		}
+	}()
+
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 
@@ -135,6 +184,10 @@ func main() {
 
 	slog.Info("Shutting down server...")
 
+	// 実行中のジョブにキャンセルを伝えて終わるのを待つ
+	stopScheduler()
+	<-schedulerDone
+
 	conn, err := db.DB()
 	if err == nil {
 		_ = conn.Close()
@@ -148,7 +201,12 @@ func main() {
 		os.Exit(1)
 	}
 
-	_ = pubSubClient.Close()
+	if pubSubClient != nil {
+		_ = pubSubClient.Close()
+	}
+	if redisClient != nil {
+		_ = redisClient.Close()
+	}
 
 	slog.Info("Server exiting")
 }
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/zuxt268/sales/internal/config"
+	"github.com/zuxt268/sales/internal/di"
+	"github.com/zuxt268/sales/internal/infrastructure"
+)
+
+// サブスクリプションからメッセージをpullして解析などの処理を行うワーカー
+// SIGINT/SIGTERMを受けると新しいメッセージの受信をやめ、処理中のメッセージが終わってから終了する
+func main() {
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{
+		Level: slog.LevelInfo,
+	}))
+	slog.SetDefault(logger)
+
+	slog.Info("Starting worker", "backend", config.Env.QueueBackend, "subscription", config.Env.WorkerSubscription, "concurrency", config.Env.WorkerConcurrency)
+
+	db := infrastructure.NewDatabase()
+
+	credPath := config.Env.GoogleServiceAccountPath
+	sheetClient := infrastructure.NewGoogleSheetsClient(credPath)
+	driveClient := infrastructure.NewGoogleDriveClient(credPath)
+
+	googleProjectID := os.Getenv("GOOGLE_PROJECT_ID")
+	// Pub/Subはキューとして使う場合だけ接続する
+	var pubSubClient infrastructure.PubSubClient
+	if config.Env.QueueBackend == config.QueueBackendPubSub {
+		pubSubClient = infrastructure.NewPubSubClient(googleProjectID, credPath)
+	}
+
+	// Redisはキュー・LLMのキャッシュに使う場合だけ接続する
+	var redisClient *redis.Client
+	if di.RedisRequired(false) {
+		redisClient = infrastructure.NewRedisQueue()
+	}
+
+	w, err := di.InitializeWorker(db, sheetClient, driveClient, pubSubClient, redisClient)
+	if err != nil {
+		slog.Error("Failed to initialize worker", "error", err)
+		os.Exit(1)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err := w.Run(ctx); err != nil {
+		slog.Error("Worker stopped with error", "error", err)
+	}
+
+	if pubSubClient != nil {
+		if err := pubSubClient.Close(); err != nil {
+			slog.Error("Failed to close pubsub client", "error", err)
+		}
+	}
+	if redisClient != nil {
+		if err := redisClient.Close(); err != nil {
+			slog.Error("Failed to close redis client", "error", err)
+		}
+	}
+	if sqlDB, err := db.DB(); err == nil {
+		_ = sqlDB.Close()
+	}
+	slog.Info("Worker exited")
+}
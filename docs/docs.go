@@ -103,11 +103,41 @@ const docTemplate = `{
                         "name": "industry",
                         "in": "query"
                     },
+                    {
+                        "type": "string",
+                        "description": "業種コード（日本標準産業分類の大分類A〜Tまたは中分類01〜99）",
+                        "name": "industry_code",
+                        "in": "query"
+                    },
                     {
                         "type": "boolean",
                         "description": "SSL対応可否",
                         "name": "is_ssl",
                         "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "すべてのターゲットで見つからなくなったもの",
+                        "name": "moved_away",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "サイトの基盤（generic, wix）",
+                        "name": "platform",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "CMS・ビルダー（wordpress, wix, jimdo, shopify, studio, ameba_ownd, static, other, unknown）",
+                        "name": "cms",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ホスティング先のIP",
+                        "name": "hosting_ip",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -240,6 +270,120 @@ const docTemplate = `{
                 }
             }
         },
+        "/domains/{id}/fingerprint": {
+            "post": {
+                "description": "ドメインのサイトを作ったCMS・ビルダーとホスティング先のIPを判定し直す",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ドメイン"
+                ],
+                "summary": "Fingerprint domain",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.Domain"
+                        }
+                    }
+                }
+            }
+        },
+        "/domains/{id}/sightings": {
+            "get": {
+                "description": "ドメインを逆引きで見つけたターゲットのIPごとに、最初と最後に見つけた日時、移転したとみなした日時を取得する",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ドメイン"
+                ],
+                "summary": "Get domain sightings",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.DomainSighting"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/domains/{id}/status-histories": {
+            "get": {
+                "description": "ドメインのステータス変更履歴を新しい順に取得する",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ドメイン"
+                ],
+                "summary": "Get domain status histories",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Offset",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/response.DomainStatusHistory"
+                            }
+                        }
+                    }
+                }
+            }
+        },
         "/external/analyze/domains": {
             "post": {
                 "consumes": [
@@ -440,7 +584,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/growth/analyze": {
+        "/growth/fetch": {
             "post": {
                 "consumes": [
                     "application/json"
@@ -458,8 +602,32 @@ const docTemplate = `{
                 }
             }
         },
-        "/growth/fetch": {
+        "/growth/fetch/plan": {
+            "get": {
+                "description": "次のFetchでReverseIPを呼び出すターゲットと回数、失敗後の待機中のターゲット、当日のViewDNSの残り回数を返す",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ドメイン"
+                ],
+                "summary": "次のFetchの計画を取得する",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.FetchPlan"
+                        }
+                    }
+                }
+            }
+        },
+        "/growth/fingerprint": {
             "post": {
+                "description": "まだ判定していないドメインのCMS・ビルダーとホスティング先のIPをまとめて判定する",
                 "consumes": [
                     "application/json"
                 ],
@@ -469,9 +637,13 @@ const docTemplate = `{
                 "tags": [
                     "ドメイン"
                 ],
+                "summary": "Fingerprint domains",
                 "responses": {
-                    "204": {
-                        "description": "No Content"
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.Fingerprint"
+                        }
                     }
                 }
             }
@@ -512,6 +684,29 @@ const docTemplate = `{
                 }
             }
         },
+        "/growth/reap": {
+            "post": {
+                "description": "ワーカーの報告がないまま止まっているドメインを再送し、上限に達したものはunknownにする",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ドメイン"
+                ],
+                "summary": "Reap stuck domains",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.ReapDomains"
+                        }
+                    }
+                }
+            }
+        },
         "/homsta": {
             "post": {
                 "consumes": [
@@ -629,9 +824,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/polling": {
-            "post": {
-                "description": "Polling domain information",
+        "/industries": {
+            "get": {
+                "description": "日本標準産業分類の大分類・中分類ごとに、紐付くドメイン数とHomsta数を返す",
                 "consumes": [
                     "application/json"
                 ],
@@ -639,19 +834,22 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Domains"
+                    "業種"
                 ],
-                "summary": "Polling domains",
+                "summary": "業種一覧を取得する",
                 "responses": {
-                    "202": {
-                        "description": "Accepted"
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.Industries"
+                        }
                     }
                 }
             }
         },
-        "/targets": {
-            "get": {
-                "description": "Get target list",
+        "/llm/cache": {
+            "delete": {
+                "description": "プロンプトや業種リストを変更した後など、同じ入力でも再解析させたい場合に使う",
                 "consumes": [
                     "application/json"
                 ],
@@ -659,37 +857,22 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "ターゲット"
-                ],
-                "summary": "Get targets",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "Limit",
-                        "name": "limit",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Offset",
-                        "name": "offset",
-                        "in": "query"
-                    }
+                    "LLM"
                 ],
+                "summary": "LLMの応答キャッシュを削除する",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/model.Target"
-                            }
+                            "$ref": "#/definitions/response.LLMCacheCleared"
                         }
                     }
                 }
-            },
-            "post": {
-                "description": "Create new target",
+            }
+        },
+        "/llm/usage": {
+            "get": {
+                "description": "日別・機能別に集計した使用量と、当日の予算の消化状況を返す",
                 "consumes": [
                     "application/json"
                 ],
@@ -697,33 +880,42 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "ターゲット"
+                    "LLM"
                 ],
-                "summary": "Create target",
+                "summary": "LLMの使用量を取得する",
                 "parameters": [
                     {
-                        "description": "作成ターゲット情報",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/model.CreateTargetRequest"
-                        }
+                        "type": "string",
+                        "description": "集計開始日(YYYY-MM-DD)。省略時は29日前",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "集計終了日(YYYY-MM-DD)。省略時は当日",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "機能(analyze_domain, analyze_site_industry)",
+                        "name": "feature",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/model.Target"
+                            "$ref": "#/definitions/response.LLMUsage"
                         }
                     }
                 }
             }
         },
-        "/targets/{id}": {
-            "put": {
-                "description": "Update target information",
+        "/outbox/relay": {
+            "post": {
+                "description": "送信待ちのメッセージをPub/Subに送信する。失敗したメッセージは間隔を空けて再送する",
                 "consumes": [
                     "application/json"
                 ],
@@ -731,31 +923,22 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "ターゲット"
-                ],
-                "summary": "Update target",
-                "parameters": [
-                    {
-                        "description": "更新ターゲット情報",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/model.UpdateTargetRequest"
-                        }
-                    }
+                    "ドメイン"
                 ],
+                "summary": "Relay outbox messages",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/model.Target"
+                            "$ref": "#/definitions/response.RelayOutbox"
                         }
                     }
                 }
-            },
-            "delete": {
-                "description": "Delete target by id",
+            }
+        },
+        "/polling": {
+            "post": {
+                "description": "Polling domain information",
                 "consumes": [
                     "application/json"
                 ],
@@ -763,90 +946,996 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "ターゲット"
+                    "Domains"
                 ],
-                "summary": "Delete target",
+                "summary": "Polling domains",
+                "responses": {
+                    "202": {
+                        "description": "Accepted"
+                    }
+                }
+            }
+        },
+        "/schedules": {
+            "get": {
+                "description": "cron式・一時停止中かどうか・次の実行日時・直近の実行結果を返す",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "スケジュール"
+                ],
+                "summary": "定期実行するジョブの一覧を取得する",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/response.Schedule"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/schedules/{name}": {
+            "put": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "スケジュール"
+                ],
+                "summary": "ジョブのcron式を変更、または一時停止・再開する",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "ID",
-                        "name": "id",
+                        "description": "ジョブ名",
+                        "name": "name",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "変更内容",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/request.UpdateSchedule"
+                        }
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content"
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.Schedule"
+                        }
+                    }
+                }
+            }
+        },
+        "/schedules/{name}/run": {
+            "post": {
+                "description": "実行は非同期。同じジョブが実行中の場合は409を返す",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "スケジュール"
+                ],
+                "summary": "ジョブをすぐに実行する",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ジョブ名",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/response.ScheduleRun"
+                        }
+                    }
+                }
+            }
+        },
+        "/schedules/{name}/runs": {
+            "get": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "スケジュール"
+                ],
+                "summary": "ジョブの実行履歴を新しい順に取得する",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ジョブ名",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Offset",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/response.ScheduleRun"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/targets": {
+            "get": {
+                "description": "Get target list",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ターゲット"
+                ],
+                "summary": "Get targets",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Offset",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.Target"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Create new target",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ターゲット"
+                ],
+                "summary": "Create target",
+                "parameters": [
+                    {
+                        "description": "作成ターゲット情報",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.CreateTargetRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/model.Target"
+                        }
+                    }
+                }
+            }
+        },
+        "/targets/import": {
+            "post": {
+                "description": "IPアドレス・ホスト名・CIDRの一覧からターゲットをまとめて登録する。JSON、CSV（text/csv）、またはmultipartのfileでCSVを受け付ける",
+                "consumes": [
+                    "application/json",
+                    "text/csv",
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ターゲット"
+                ],
+                "summary": "Import targets",
+                "parameters": [
+                    {
+                        "description": "取り込むターゲット",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/request.ImportTargets"
+                        }
+                    },
+                    {
+                        "type": "file",
+                        "description": "CSVファイル（1行目は列名。value/ip/host/hostname/cidr/address, name, source, weight）",
+                        "name": "file",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.TargetImport"
+                        }
                     }
                 }
             }
         },
-        "/webhook/analyze": {
-            "post": {
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "ドメイン"
-                ],
-                "summary": "PubSubのwebhookエンドポイント",
-                "responses": {
-                    "204": {
-                        "description": "No Content"
+        "/targets/stats": {
+            "get": {
+                "description": "ターゲットごとの取得の成果の一覧",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ターゲット"
+                ],
+                "summary": "Get stats of targets",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Offset",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ターゲットごとに返す周回の記録の数（既定10、最大100）",
+                        "name": "scans",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/response.TargetStats"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/targets/{id}": {
+            "put": {
+                "description": "Update target information",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ターゲット"
+                ],
+                "summary": "Update target",
+                "parameters": [
+                    {
+                        "description": "更新ターゲット情報",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.UpdateTargetRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Target"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete target by id",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ターゲット"
+                ],
+                "summary": "Delete target",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    }
+                }
+            }
+        },
+        "/targets/{id}/stats": {
+            "get": {
+                "description": "ターゲットの取得の成果（ステータスごとのドメイン数、doneの割合、ページの進み具合、周回ごとの新しいドメイン数）",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ターゲット"
+                ],
+                "summary": "Get target stats",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "返す周回の記録の数（既定10、最大100）",
+                        "name": "scans",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.TargetStats"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhook/analyze": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ドメイン"
+                ],
+                "summary": "PubSubのwebhookエンドポイント",
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    }
+                }
+            }
+        },
+        "/webhook/analyze-domain": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ドメイン"
+                ],
+                "summary": "PubSubのwebhookエンドポイント（GPTによる解析）",
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "entity.Deploy": {
+            "type": "object",
+            "properties": {
+                "domain": {
+                    "type": "string"
+                },
+                "server_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.CreateTargetRequest": {
+            "type": "object",
+            "properties": {
+                "ip": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "platform": {
+                    "description": "generic（省略時）, wix",
+                    "type": "string"
+                },
+                "source": {
+                    "description": "viewdns（省略時）, passive_dns",
+                    "type": "string"
+                },
+                "weight": {
+                    "description": "省略時は1",
+                    "type": "integer"
+                }
+            }
+        },
+        "model.DomainSighting": {
+            "type": "object",
+            "properties": {
+                "domain": {
+                    "type": "string"
+                },
+                "first_seen": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "last_resolved": {
+                    "type": "string"
+                },
+                "last_seen": {
+                    "type": "string"
+                },
+                "moved_away_at": {
+                    "type": "string"
+                },
+                "target_ip": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.Homsta": {
+            "type": "object",
+            "properties": {
+                "blogName": {
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "dbname": {
+                    "type": "string"
+                },
+                "dbusage": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "discUsage": {
+                    "type": "string"
+                },
+                "domain": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "industry": {
+                    "type": "string"
+                },
+                "mailUsage": {
+                    "type": "string"
+                },
+                "path": {
+                    "type": "string"
+                },
+                "server": {
+                    "type": "string"
+                },
+                "siteURL": {
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "type": "string"
+                },
+                "users": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.LLMFeature": {
+            "type": "string",
+            "enum": [
+                "analyze_domain",
+                "analyze_site_industry"
+            ],
+            "x-enum-varnames": [
+                "LLMFeatureAnalyzeDomain",
+                "LLMFeatureAnalyzeSiteIndustry"
+            ]
+        },
+        "model.LLMUsageSummary": {
+            "type": "object",
+            "properties": {
+                "calls": {
+                    "type": "integer"
+                },
+                "completion_tokens": {
+                    "type": "integer"
+                },
+                "cost": {
+                    "type": "number"
+                },
+                "date": {
+                    "type": "string"
+                },
+                "feature": {
+                    "$ref": "#/definitions/model.LLMFeature"
+                },
+                "prompt_tokens": {
+                    "type": "integer"
+                },
+                "total_tokens": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.ScheduleRunStatus": {
+            "type": "string",
+            "enum": [
+                "running",
+                "succeeded",
+                "failed",
+                "skipped"
+            ],
+            "x-enum-comments": {
+                "ScheduleRunSkipped": "前回の実行が終わっていなかった"
+            },
+            "x-enum-descriptions": [
+                "",
+                "",
+                "",
+                "前回の実行が終わっていなかった"
+            ],
+            "x-enum-varnames": [
+                "ScheduleRunRunning",
+                "ScheduleRunSucceeded",
+                "ScheduleRunFailed",
+                "ScheduleRunSkipped"
+            ]
+        },
+        "model.ScheduleTrigger": {
+            "type": "string",
+            "enum": [
+                "cron",
+                "manual"
+            ],
+            "x-enum-varnames": [
+                "ScheduleTriggerCron",
+                "ScheduleTriggerManual"
+            ]
+        },
+        "model.Status": {
+            "type": "string",
+            "enum": [
+                "unknown",
+                "initialize",
+                "check_view",
+                "check_japan",
+                "check_wix",
+                "crawl_comp_info",
+                "pending_output",
+                "needs_review",
+                "done",
+                "trash"
+            ],
+            "x-enum-comments": {
+                "StatusCheckWix": "Wixのサイトが日本語か調べ、オーナーIDを取り出す"
+            },
+            "x-enum-descriptions": [
+                "",
+                "",
+                "",
+                "",
+                "Wixのサイトが日本語か調べ、オーナーIDを取り出す",
+                "",
+                "",
+                "",
+                "",
+                ""
+            ],
+            "x-enum-varnames": [
+                "StatusUnknown",
+                "StatusInitialize",
+                "StatusCheckView",
+                "StatusCheckJapan",
+                "StatusCheckWix",
+                "StatusCrawlCompInfo",
+                "StatusPendingOutput",
+                "StatusNeedsReview",
+                "StatusDone",
+                "StatusTrash"
+            ]
+        },
+        "model.Target": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "currentPage": {
+                    "type": "integer"
+                },
+                "failure_count": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "ip": {
+                    "type": "string"
+                },
+                "lastFetchedAt": {
+                    "type": "string"
+                },
+                "lastFullScanAt": {
+                    "type": "string"
+                },
+                "last_error": {
+                    "type": "string"
+                },
+                "maxPage": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "next_fetch_at": {
+                    "type": "string"
+                },
+                "platform": {
+                    "type": "string"
+                },
+                "scanStartedAt": {
+                    "type": "string"
+                },
+                "source": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/model.TargetStatus"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "weight": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.TargetStatus": {
+            "type": "string",
+            "enum": [
+                "init",
+                "fetched",
+                "disabled"
+            ],
+            "x-enum-varnames": [
+                "TargetStatusInit",
+                "TargetStatusFetched",
+                "TargetStatusDisabled"
+            ]
+        },
+        "model.UpdateTargetRequest": {
+            "type": "object",
+            "properties": {
+                "ip": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "platform": {
+                    "type": "string"
+                },
+                "source": {
+                    "type": "string"
+                },
+                "status": {
+                    "description": "init, fetched, disabled",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.TargetStatus"
+                        }
+                    ]
+                },
+                "weight": {
+                    "type": "integer"
+                }
+            }
+        },
+        "request.DeployRequest": {
+            "type": "object",
+            "properties": {
+                "dst": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.Deploy"
+                    }
+                },
+                "src": {
+                    "$ref": "#/definitions/entity.Deploy"
+                }
+            }
+        },
+        "request.Homsta": {
+            "type": "object",
+            "properties": {
+                "blogName": {
+                    "type": "string"
+                },
+                "dbUsage": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "discUsage": {
+                    "type": "string"
+                },
+                "path": {
+                    "type": "string"
+                },
+                "siteUrl": {
+                    "type": "string"
+                },
+                "users": {
+                    "type": "string"
+                }
+            }
+        },
+        "request.ImportTarget": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "description": "省略時はIPアドレス",
+                    "type": "string"
+                },
+                "platform": {
+                    "description": "generic（省略時）, wix",
+                    "type": "string"
+                },
+                "source": {
+                    "description": "viewdns（省略時）, passive_dns",
+                    "type": "string"
+                },
+                "value": {
+                    "type": "string"
+                },
+                "weight": {
+                    "description": "省略時は1",
+                    "type": "integer"
+                }
+            }
+        },
+        "request.ImportTargets": {
+            "type": "object",
+            "properties": {
+                "targets": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/request.ImportTarget"
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "entity.Deploy": {
+        },
+        "request.UpdateDomain": {
             "type": "object",
             "properties": {
-                "domain": {
+                "address": {
                     "type": "string"
                 },
-                "server_id": {
+                "can_view": {
+                    "type": "boolean"
+                },
+                "company": {
+                    "type": "string"
+                },
+                "industry": {
+                    "type": "string"
+                },
+                "is_japan": {
+                    "type": "boolean"
+                },
+                "is_send": {
+                    "type": "boolean"
+                },
+                "is_ssl": {
+                    "type": "boolean"
+                },
+                "landline_phone": {
+                    "type": "string"
+                },
+                "mobile_phone": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "owner_id": {
+                    "type": "string"
+                },
+                "page_num": {
+                    "type": "integer"
+                },
+                "phone": {
+                    "type": "string"
+                },
+                "prefecture": {
+                    "type": "string"
+                },
+                "president": {
+                    "type": "string"
+                },
+                "raw_page": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/model.Status"
+                },
+                "target": {
+                    "type": "string"
+                },
+                "title": {
                     "type": "string"
                 }
             }
         },
-        "model.CreateTargetRequest": {
+        "request.UpdateSchedule": {
+            "type": "object",
+            "properties": {
+                "cron_expr": {
+                    "type": "string"
+                },
+                "paused": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "response.BackingOffTarget": {
             "type": "object",
             "properties": {
+                "failure_count": {
+                    "type": "integer"
+                },
                 "ip": {
                     "type": "string"
                 },
+                "last_error": {
+                    "type": "string"
+                },
                 "name": {
                     "type": "string"
+                },
+                "next_fetch_at": {
+                    "type": "string"
+                },
+                "target_id": {
+                    "type": "integer"
                 }
             }
         },
-        "model.Homsta": {
+        "response.Domain": {
             "type": "object",
             "properties": {
-                "blogName": {
+                "address": {
                     "type": "string"
                 },
-                "createdAt": {
+                "can_view": {
+                    "type": "boolean"
+                },
+                "cms": {
                     "type": "string"
                 },
-                "dbname": {
+                "cms_version": {
                     "type": "string"
                 },
-                "dbusage": {
+                "company": {
                     "type": "string"
                 },
-                "description": {
+                "crawled_urls": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "created_at": {
                     "type": "string"
                 },
-                "discUsage": {
+                "fingerprinted_at": {
+                    "description": "CMSを判定した日時",
                     "type": "string"
                 },
-                "domain": {
+                "health_checked_at": {
+                    "description": "サイトの死活・SSLを確認した日時",
+                    "type": "string"
+                },
+                "hosting_ip": {
                     "type": "string"
                 },
                 "id": {
@@ -855,291 +1944,482 @@ const docTemplate = `{
                 "industry": {
                     "type": "string"
                 },
-                "mailUsage": {
+                "industry_codes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "is_japan": {
+                    "type": "boolean"
+                },
+                "is_parked": {
+                    "type": "boolean"
+                },
+                "is_send": {
+                    "type": "boolean"
+                },
+                "is_ssl": {
+                    "type": "boolean"
+                },
+                "landline_phone": {
                     "type": "string"
                 },
-                "path": {
+                "mobile_phone": {
                     "type": "string"
                 },
-                "server": {
+                "moved_away_at": {
+                    "description": "すべてのターゲットで見つからなくなった日時",
                     "type": "string"
                 },
-                "siteURL": {
+                "name": {
                     "type": "string"
                 },
-                "updatedAt": {
+                "owner_id": {
                     "type": "string"
                 },
-                "users": {
+                "page_num": {
+                    "type": "integer"
+                },
+                "phone": {
+                    "type": "string"
+                },
+                "platform": {
+                    "type": "string"
+                },
+                "prefecture": {
+                    "type": "string"
+                },
+                "president": {
+                    "type": "string"
+                },
+                "raw_page": {
+                    "type": "string"
+                },
+                "redirect_url": {
+                    "description": "ほかのサイトにリダイレクトしている場合の転送先",
+                    "type": "string"
+                },
+                "review_reason": {
+                    "type": "string"
+                },
+                "source": {
+                    "type": "string"
+                },
+                "ssl_expires_at": {
+                    "type": "string"
+                },
+                "ssl_issuer": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/model.Status"
+                },
+                "target": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "updated_at": {
                     "type": "string"
                 }
             }
         },
-        "model.Status": {
-            "type": "string",
-            "enum": [
-                "unknown",
-                "initialize",
-                "check_view",
-                "check_japan",
-                "crawl_comp_info",
-                "pending_output",
-                "done",
-                "trash"
-            ],
-            "x-enum-varnames": [
-                "StatusUnknown",
-                "StatusInitialize",
-                "StatusCheckView",
-                "StatusCheckJapan",
-                "StatusCrawlCompInfo",
-                "StatusPendingOutput",
-                "StatusDone",
-                "StatusTrash"
-            ]
+        "response.DomainStatusHistory": {
+            "type": "object",
+            "properties": {
+                "actor": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "from_status": {
+                    "$ref": "#/definitions/model.Status"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "to_status": {
+                    "$ref": "#/definitions/model.Status"
+                }
+            }
+        },
+        "response.Domains": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "domains": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.Domain"
+                    }
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
         },
-        "model.Target": {
+        "response.FetchPlan": {
             "type": "object",
             "properties": {
-                "created_at": {
-                    "type": "string"
+                "backing_off": {
+                    "description": "失敗後の待機中で今回は取得しないもの",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.BackingOffTarget"
+                    }
                 },
-                "currentPage": {
-                    "type": "integer"
+                "calls": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.FetchPlanTarget"
+                    }
                 },
-                "id": {
+                "calls_per_run": {
                     "type": "integer"
                 },
-                "ip": {
-                    "type": "string"
-                },
-                "lastFetchedAt": {
-                    "type": "string"
+                "daily_limit": {
+                    "description": "0は無制限",
+                    "type": "integer"
                 },
-                "lastFullScanAt": {
+                "date": {
                     "type": "string"
                 },
-                "name": {
-                    "type": "string"
+                "non_wix_ratio": {
+                    "type": "number"
                 },
-                "status": {
-                    "$ref": "#/definitions/model.TargetStatus"
+                "remaining": {
+                    "description": "-1は無制限",
+                    "type": "integer"
                 },
-                "updated_at": {
-                    "type": "string"
+                "used": {
+                    "type": "integer"
                 }
             }
         },
-        "model.TargetStatus": {
-            "type": "string",
-            "enum": [
-                "init",
-                "fetched",
-                "disabled"
-            ],
-            "x-enum-varnames": [
-                "TargetStatusInit",
-                "TargetStatusFetched",
-                "TargetStatusDisabled"
-            ]
-        },
-        "model.UpdateTargetRequest": {
+        "response.FetchPlanTarget": {
             "type": "object",
             "properties": {
+                "calls": {
+                    "description": "今回進めるページ数",
+                    "type": "integer"
+                },
                 "ip": {
                     "type": "string"
                 },
                 "name": {
                     "type": "string"
+                },
+                "target_id": {
+                    "type": "integer"
                 }
             }
         },
-        "request.DeployRequest": {
+        "response.Fingerprint": {
             "type": "object",
             "properties": {
-                "dst": {
+                "counts": {
+                    "description": "CMSごとのドメイン数",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "failed": {
+                    "description": "保存できなかったドメイン数。次の実行でまた判定する",
+                    "type": "integer"
+                },
+                "processed": {
+                    "description": "判定したドメイン数",
+                    "type": "integer"
+                }
+            }
+        },
+        "response.Industries": {
+            "type": "object",
+            "properties": {
+                "industries": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/entity.Deploy"
+                        "$ref": "#/definitions/response.Industry"
                     }
-                },
-                "src": {
-                    "$ref": "#/definitions/entity.Deploy"
                 }
             }
         },
-        "request.Homsta": {
+        "response.Industry": {
             "type": "object",
             "properties": {
-                "blogName": {
-                    "type": "string"
-                },
-                "dbUsage": {
-                    "type": "string"
-                },
-                "description": {
-                    "type": "string"
+                "children": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.Industry"
+                    }
                 },
-                "discUsage": {
+                "code": {
                     "type": "string"
                 },
-                "path": {
-                    "type": "string"
+                "domain_count": {
+                    "type": "integer"
                 },
-                "siteUrl": {
-                    "type": "string"
+                "homsta_count": {
+                    "type": "integer"
                 },
-                "users": {
+                "name": {
                     "type": "string"
                 }
             }
         },
-        "request.UpdateDomain": {
+        "response.LLMBudget": {
             "type": "object",
             "properties": {
-                "address": {
+                "cost": {
+                    "type": "number"
+                },
+                "cost_budget": {
+                    "type": "number"
+                },
+                "date": {
                     "type": "string"
                 },
-                "can_view": {
+                "exceeded": {
                     "type": "boolean"
                 },
-                "company": {
-                    "type": "string"
+                "token_budget": {
+                    "type": "integer"
                 },
-                "industry": {
-                    "type": "string"
+                "total_tokens": {
+                    "type": "integer"
+                }
+            }
+        },
+        "response.LLMCacheCleared": {
+            "type": "object",
+            "properties": {
+                "deleted": {
+                    "type": "integer"
+                }
+            }
+        },
+        "response.LLMUsage": {
+            "type": "object",
+            "properties": {
+                "today": {
+                    "$ref": "#/definitions/response.LLMBudget"
                 },
-                "is_japan": {
-                    "type": "boolean"
+                "usages": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.LLMUsageSummary"
+                    }
+                }
+            }
+        },
+        "response.ReapDomains": {
+            "type": "object",
+            "properties": {
+                "abandoned": {
+                    "description": "再送回数の上限に達してunknownにしたドメイン数",
+                    "type": "integer"
                 },
-                "is_send": {
-                    "type": "boolean"
+                "retried": {
+                    "description": "再送したドメイン数",
+                    "type": "integer"
+                }
+            }
+        },
+        "response.RelayOutbox": {
+            "type": "object",
+            "properties": {
+                "failed": {
+                    "description": "再送回数の上限に達したメッセージ数",
+                    "type": "integer"
                 },
-                "is_ssl": {
-                    "type": "boolean"
+                "retrying": {
+                    "description": "送信に失敗して再送を待つメッセージ数",
+                    "type": "integer"
                 },
-                "landline_phone": {
+                "sent": {
+                    "description": "送信したメッセージ数",
+                    "type": "integer"
+                }
+            }
+        },
+        "response.Schedule": {
+            "type": "object",
+            "properties": {
+                "cron_expr": {
                     "type": "string"
                 },
-                "mobile_phone": {
-                    "type": "string"
+                "last_run": {
+                    "description": "直近の実行結果",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/response.ScheduleRun"
+                        }
+                    ]
                 },
                 "name": {
                     "type": "string"
                 },
-                "owner_id": {
+                "next_run_at": {
                     "type": "string"
                 },
-                "page_num": {
-                    "type": "integer"
+                "paused": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "response.ScheduleRun": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
                 },
-                "phone": {
+                "finished_at": {
                     "type": "string"
                 },
-                "prefecture": {
+                "id": {
+                    "type": "integer"
+                },
+                "instance": {
                     "type": "string"
                 },
-                "president": {
+                "schedule_name": {
                     "type": "string"
                 },
-                "raw_page": {
+                "started_at": {
                     "type": "string"
                 },
                 "status": {
-                    "$ref": "#/definitions/model.Status"
-                },
-                "target": {
-                    "type": "string"
+                    "$ref": "#/definitions/model.ScheduleRunStatus"
                 },
-                "title": {
-                    "type": "string"
+                "trigger": {
+                    "$ref": "#/definitions/model.ScheduleTrigger"
                 }
             }
         },
-        "response.Domain": {
+        "response.TargetImport": {
             "type": "object",
             "properties": {
-                "address": {
-                    "type": "string"
+                "created": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.TargetImportRow"
+                    }
                 },
-                "can_view": {
-                    "type": "boolean"
+                "duplicates": {
+                    "description": "登録済み、または同じ取り込みの中で重複したアドレス",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.TargetImportRow"
+                    }
                 },
-                "company": {
+                "invalid": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.TargetImportRow"
+                    }
+                }
+            }
+        },
+        "response.TargetImportRow": {
+            "type": "object",
+            "properties": {
+                "error": {
                     "type": "string"
                 },
-                "created_at": {
+                "ip": {
                     "type": "string"
                 },
-                "id": {
+                "row": {
                     "type": "integer"
                 },
-                "industry": {
+                "value": {
                     "type": "string"
+                }
+            }
+        },
+        "response.TargetScan": {
+            "type": "object",
+            "properties": {
+                "domains_found": {
+                    "type": "integer"
                 },
-                "is_japan": {
-                    "type": "boolean"
+                "finished_at": {
+                    "type": "string"
                 },
-                "is_send": {
-                    "type": "boolean"
+                "new_domains": {
+                    "description": "初めて登録したドメイン数",
+                    "type": "integer"
                 },
-                "is_ssl": {
-                    "type": "boolean"
+                "pages": {
+                    "type": "integer"
                 },
-                "landline_phone": {
+                "started_at": {
                     "type": "string"
+                }
+            }
+        },
+        "response.TargetStats": {
+            "type": "object",
+            "properties": {
+                "current_page": {
+                    "type": "integer"
                 },
-                "mobile_phone": {
+                "done_ratio": {
+                    "description": "done まで進んだドメインの割合",
+                    "type": "number"
+                },
+                "ip": {
                     "type": "string"
                 },
-                "name": {
+                "last_fetched_at": {
                     "type": "string"
                 },
-                "owner_id": {
+                "last_full_scan_at": {
                     "type": "string"
                 },
-                "page_num": {
+                "max_page": {
+                    "description": "最後に取得したときの総ページ数（未取得は0）",
                     "type": "integer"
                 },
-                "phone": {
-                    "type": "string"
-                },
-                "prefecture": {
+                "name": {
                     "type": "string"
                 },
-                "president": {
-                    "type": "string"
+                "scans": {
+                    "description": "新しい順",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.TargetScan"
+                    }
                 },
-                "raw_page": {
+                "source": {
                     "type": "string"
                 },
                 "status": {
-                    "$ref": "#/definitions/model.Status"
-                },
-                "target": {
-                    "type": "string"
+                    "$ref": "#/definitions/model.TargetStatus"
                 },
-                "title": {
-                    "type": "string"
+                "status_counts": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer",
+                        "format": "int64"
+                    }
                 },
-                "updated_at": {
-                    "type": "string"
-                }
-            }
-        },
-        "response.Domains": {
-            "type": "object",
-            "properties": {
-                "count": {
+                "target_id": {
                     "type": "integer"
                 },
-                "domains": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/response.Domain"
-                    }
+                "total_domains": {
+                    "type": "integer"
                 },
-                "total": {
+                "weight": {
                     "type": "integer"
                 }
             }
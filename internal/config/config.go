@@ -2,41 +2,92 @@ package config
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/joho/godotenv"
 	"github.com/kelseyhightower/envconfig"
 )
 
 type Environment struct {
-	ApiKey                    string `envconfig:"API_KEY"`
-	ViewDnsApiUrl             string `envconfig:"VIEW_DNS_API_URL"`
-	DBHost                    string `envconfig:"DB_HOST" default:"localhost"`
-	DBPort                    int    `envconfig:"DB_PORT" default:"3306"`
-	DBDatabase                string `envconfig:"DB_NAME"`
-	DBUsername                string `envconfig:"DB_USER"`
-	DBPassword                string `envconfig:"DB_PASSWORD"`
-	RedisHost                 string `envconfig:"REDIS_HOST" default:"localhost"`
-	RedisPort                 int    `envconfig:"REDIS_PORT" default:"6379"`
-	Address                   string `envconfig:"ADDRESS" default:"localhost"`
-	Password                  string `envconfig:"PASSWORD"`
-	JWTSecret                 string `envconfig:"JWT_SECRET"`
-	OpenaiApiKey              string `envconfig:"OPENAI_API_KEY"`
-	SwaggerHost               string `envconfig:"SWAGGER_HOST" default:"localhost:8091"`
-	NoticeWebAppChannelUrl    string `envconfig:"NOTICE_WEB_APP_CHANNEL_URL"`
-	GoogleServiceAccountPath  string `envconfig:"GOOGLE_SERVICE_ACCOUNT_PATH"`
-	DatabasePassword1         string `envconfig:"DATABASE_PASSWORD_1"`
-	DatabasePassword2         string `envconfig:"DATABASE_PASSWORD_2"`
-	DatabaseHost1             string `envconfig:"DATABASE_HOST_1" default:"localhost"`
-	DatabaseHost2             string `envconfig:"DATABASE_HOST_2" default:"localhost"`
-	HashPhrase                string `envconfig:"HASH_PHRASE"`
-	RodutSecretPhrase         string `envconfig:"RODUT_SECRET_PHRASE"`
-	SheetID                   string `envconfig:"SHEET_ID"`
-	SiteSheetID               string `envconfig:"SITE_SHEET_ID"`
-	ServerIDs                 string `envconfig:"SERVER_IDS"`
-	GoogleDriveBackupFolderID string `envconfig:"GOOGLE_DRIVE_BACKUP_FOLDER_ID"`
-	GoogleDriveShareEmail     string `envconfig:"GOOGLE_DRIVE_SHARE_EMAIL"`
+	ApiKey                    string        `envconfig:"API_KEY"`
+	ViewDnsApiUrl             string        `envconfig:"VIEW_DNS_API_URL"`
+	ViewDnsTimeout            time.Duration `envconfig:"VIEW_DNS_TIMEOUT" default:"30s"`
+	ViewDnsMaxRetries         int           `envconfig:"VIEW_DNS_MAX_RETRIES" default:"3"`
+	ReverseIPImportDir        string        `envconfig:"REVERSE_IP_IMPORT_DIR"`
+	DBHost                    string        `envconfig:"DB_HOST" default:"localhost"`
+	DBPort                    int           `envconfig:"DB_PORT" default:"3306"`
+	DBDatabase                string        `envconfig:"DB_NAME"`
+	DBUsername                string        `envconfig:"DB_USER"`
+	DBPassword                string        `envconfig:"DB_PASSWORD"`
+	RedisHost                 string        `envconfig:"REDIS_HOST" default:"localhost"`
+	RedisPort                 int           `envconfig:"REDIS_PORT" default:"6379"`
+	Address                   string        `envconfig:"ADDRESS" default:"localhost"`
+	Password                  string        `envconfig:"PASSWORD"`
+	JWTSecret                 string        `envconfig:"JWT_SECRET"`
+	OpenaiApiKey              string        `envconfig:"OPENAI_API_KEY"`
+	SwaggerHost               string        `envconfig:"SWAGGER_HOST" default:"localhost:8091"`
+	NoticeWebAppChannelUrl    string        `envconfig:"NOTICE_WEB_APP_CHANNEL_URL"`
+	GoogleServiceAccountPath  string        `envconfig:"GOOGLE_SERVICE_ACCOUNT_PATH"`
+	DatabasePassword1         string        `envconfig:"DATABASE_PASSWORD_1"`
+	DatabasePassword2         string        `envconfig:"DATABASE_PASSWORD_2"`
+	DatabaseHost1             string        `envconfig:"DATABASE_HOST_1" default:"localhost"`
+	DatabaseHost2             string        `envconfig:"DATABASE_HOST_2" default:"localhost"`
+	HashPhrase                string        `envconfig:"HASH_PHRASE"`
+	RodutSecretPhrase         string        `envconfig:"RODUT_SECRET_PHRASE"`
+	SheetID                   string        `envconfig:"SHEET_ID"`
+	SiteSheetID               string        `envconfig:"SITE_SHEET_ID"`
+	ServerIDs                 string        `envconfig:"SERVER_IDS"`
+	GoogleDriveBackupFolderID string        `envconfig:"GOOGLE_DRIVE_BACKUP_FOLDER_ID"`
+	GoogleDriveShareEmail     string        `envconfig:"GOOGLE_DRIVE_SHARE_EMAIL"`
+	CrawlMaxPages             int           `envconfig:"CRAWL_MAX_PAGES" default:"5"`
+	GptMinConfidence          float64       `envconfig:"GPT_MIN_CONFIDENCE" default:"0.6"`
+	LLMProvider               string        `envconfig:"LLM_PROVIDER" default:"openai"`
+	LLMModel                  string        `envconfig:"LLM_MODEL" default:"gpt-5-nano"`
+	LLMBaseURL                string        `envconfig:"LLM_BASE_URL"`
+	LLMTimeout                time.Duration `envconfig:"LLM_TIMEOUT" default:"10s"`
+	LLMTemperature            float32       `envconfig:"LLM_TEMPERATURE"`
+	LLMMaxTokens              int           `envconfig:"LLM_MAX_TOKENS"`
+	LLMInputPricePerMTok      float64       `envconfig:"LLM_INPUT_PRICE_PER_MTOK" default:"0.05"`
+	LLMOutputPricePerMTok     float64       `envconfig:"LLM_OUTPUT_PRICE_PER_MTOK" default:"0.4"`
+	LLMDailyTokenBudget       int           `envconfig:"LLM_DAILY_TOKEN_BUDGET"`
+	LLMDailyCostBudget        float64       `envconfig:"LLM_DAILY_COST_BUDGET"`
+	LLMCacheTTL               time.Duration `envconfig:"LLM_CACHE_TTL" default:"0"`
+	DomainStuckAfter          time.Duration `envconfig:"DOMAIN_STUCK_AFTER" default:"1h"`
+	DomainMaxAttempts         int           `envconfig:"DOMAIN_MAX_ATTEMPTS" default:"3"`
+	OutboxMaxAttempts         int           `envconfig:"OUTBOX_MAX_ATTEMPTS" default:"10"`
+	WorkerSubscription        string        `envconfig:"WORKER_SUBSCRIPTION" default:"domain-analyze"`
+	WorkerConcurrency         int           `envconfig:"WORKER_CONCURRENCY" default:"5"`
+	WorkerMaxAttempts         int           `envconfig:"WORKER_MAX_ATTEMPTS" default:"5"`
+	WorkerHandlerTimeout      time.Duration `envconfig:"WORKER_HANDLER_TIMEOUT" default:"5m"`
+	WorkerDeadLetterTopic     string        `envconfig:"WORKER_DEAD_LETTER_TOPIC" default:"domain-pipeline-dead-letter"`
+	PubSubPushAudience        string        `envconfig:"PUBSUB_PUSH_AUDIENCE"`
+	PubSubPushServiceAccount  string        `envconfig:"PUBSUB_PUSH_SERVICE_ACCOUNT"`
+	PubSubPushSecret          string        `envconfig:"PUBSUB_PUSH_SECRET"`
+	QueueBackend              string        `envconfig:"QUEUE_BACKEND" default:"pubsub"`
+	QueueVisibilityTimeout    time.Duration `envconfig:"QUEUE_VISIBILITY_TIMEOUT" default:"10m"`
+	QueueStreamMaxLen         int64         `envconfig:"QUEUE_STREAM_MAX_LEN" default:"100000"`
+	SchedulerEnabled          bool          `envconfig:"SCHEDULER_ENABLED" default:"true"`
+	SchedulerJobTimeout       time.Duration `envconfig:"SCHEDULER_JOB_TIMEOUT" default:"1h"`
+	ScheduleFetch             string        `envconfig:"SCHEDULE_FETCH" default:"0 1 * * *"`
+	SchedulePolling           string        `envconfig:"SCHEDULE_POLLING" default:"*/10 * * * *"`
+	ScheduleOutput            string        `envconfig:"SCHEDULE_OUTPUT" default:"0 7 * * *"`
+	ScheduleHomsta            string        `envconfig:"SCHEDULE_HOMSTA" default:"0 3 * * *"`
+	ScheduleReap              string        `envconfig:"SCHEDULE_REAP" default:"*/30 * * * *"`
+	ScheduleRelay             string        `envconfig:"SCHEDULE_RELAY" default:"* * * * *"`
+	ScheduleFingerprint       string        `envconfig:"SCHEDULE_FINGERPRINT" default:"*/15 * * * *"`
+	FingerprintBatchSize      int           `envconfig:"FINGERPRINT_BATCH_SIZE" default:"200"`
+	ViewDnsDailyLimit         int           `envconfig:"VIEWDNS_DAILY_LIMIT"`
+	FetchCallsPerRun          int           `envconfig:"FETCH_CALLS_PER_RUN" default:"100"`
+	FetchNonWixRatio          float64       `envconfig:"FETCH_NON_WIX_RATIO" default:"0.8"`
+	FetchWixCallsPerRun       int           `envconfig:"FETCH_WIX_CALLS_PER_RUN" default:"500"`
 }
 
+// QUEUE_BACKEND に指定できる値
+const (
+	QueueBackendPubSub = "pubsub"
+	QueueBackendRedis  = "redis"
+)
+
 var Env Environment
 
 func init() {
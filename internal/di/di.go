@@ -1,6 +1,7 @@
 package di
 
 import (
+	"github.com/redis/go-redis/v9"
 	"github.com/zuxt268/sales/internal/config"
 	"github.com/zuxt268/sales/internal/infrastructure"
 	"github.com/zuxt268/sales/internal/interfaces/adapter"
@@ -16,34 +17,70 @@ func Initialize(
 	sheetClient infrastructure.GoogleSheetsClient,
 	driveClient infrastructure.GoogleDriveClient,
 	pubSubClient infrastructure.PubSubClient,
-) handler.ApiHandler {
+	redisClient *redis.Client,
+) (handler.ApiHandler, usecase.ScheduleUsecase, error) {
 	domainRepo := repository.NewDomainRepository(db)
 	homstaRepo := repository.NewHomstaRepository(db)
-	viewDnsAdapter := adapter.NewViewDNSAdapter(config.Env.ViewDnsApiUrl)
+	reverseIPSources := newReverseIPSources()
 	baseRepo := repository.NewBaseRepository(db)
 	targetRepo := repository.NewTargetRepository(db)
-	gptAdapter := adapter.NewGptAdapter()
+	llmCacheAdapter := newLLMCache(redisClient)
+	gptAdapter, err := adapter.NewGptAdapter(llmCacheAdapter)
+	if err != nil {
+		return nil, nil, err
+	}
 	slackAdapter := adapter.NewSlackAdapter()
-	pubSubAdapter := adapter.NewPubSubAdapter(pubSubClient)
+	queue := newQueue(pubSubClient, redisClient)
+	crawlerAdapter := adapter.NewCrawlerAdapter(config.Env.CrawlMaxPages)
+	llmUsageRepo := repository.NewLLMUsageRepository(db)
+	industryRepo := repository.NewIndustryRepository(db)
+	historyRepo := repository.NewDomainStatusHistoryRepository(db)
+	outboxRepo := repository.NewOutboxRepository(db)
+	processedRepo := repository.NewProcessedMessageRepository(db)
+	ledgerRepo := repository.NewAPICallLedgerRepository(db)
+	sightingRepo := repository.NewDomainSightingRepository(db)
+	scanRepo := repository.NewTargetScanRepository(db)
 
-	fetchUsecase := usecase.NewFetchUsecase(viewDnsAdapter, slackAdapter, pubSubAdapter, domainRepo, targetRepo)
-	domainUsecase := usecase.NewDomainUsecase(baseRepo, domainRepo)
-	targetUsecase := usecase.NewTargetUsecase(baseRepo, targetRepo)
-	gptUsecase := usecase.NewGptUsecase(baseRepo, domainRepo, slackAdapter, gptAdapter)
+	fetchUsecase := usecase.NewFetchUsecase(reverseIPSources, slackAdapter, baseRepo, domainRepo, targetRepo, historyRepo, outboxRepo, ledgerRepo, sightingRepo, scanRepo)
+	domainUsecase := usecase.NewDomainUsecase(baseRepo, domainRepo, industryRepo, historyRepo, sightingRepo)
+	resolverAdapter := adapter.NewResolverAdapter()
+	targetUsecase := usecase.NewTargetUsecase(baseRepo, targetRepo, sightingRepo, scanRepo, resolverAdapter)
+	gptUsecase := usecase.NewGptUsecase(baseRepo, domainRepo, slackAdapter, gptAdapter, crawlerAdapter, llmUsageRepo, industryRepo, historyRepo, processedRepo)
 	sshAdapter := adapter.NewSSHAdapter()
 	sheetAdapter := adapter.NewSheetAdapter(sheetClient, driveClient)
-	homstaUsecase := usecase.NewHomstaUsecase(baseRepo, homstaRepo, sshAdapter, gptAdapter, sheetAdapter, slackAdapter)
+	homstaUsecase := usecase.NewHomstaUsecase(baseRepo, homstaRepo, sshAdapter, gptAdapter, sheetAdapter, slackAdapter, crawlerAdapter, llmUsageRepo, industryRepo)
 	deployUsecase := usecase.NewDeployUsecase(sshAdapter)
-	sheetUsecase := usecase.NewSheetUsecase(baseRepo, domainRepo, sheetAdapter, sshAdapter)
+	sheetUsecase := usecase.NewSheetUsecase(baseRepo, domainRepo, historyRepo, sheetAdapter, sshAdapter)
 	growthUsecase := usecase.NewGrowthUsecase(
 		baseRepo,
 		domainRepo,
 		targetRepo,
-		pubSubAdapter,
-		viewDnsAdapter,
+		reverseIPSources,
 		sheetAdapter,
 		gptAdapter,
+		crawlerAdapter,
+		adapter.NewSiteHealthAdapter(),
+		llmUsageRepo,
+		industryRepo,
+		historyRepo,
+		outboxRepo,
+		processedRepo,
+		ledgerRepo,
+		sightingRepo,
+		scanRepo,
 	)
+	outboxUsecase := usecase.NewOutboxUsecase(outboxRepo, queue)
+	llmUsecase := usecase.NewLLMUsecase(llmUsageRepo, llmCacheAdapter)
+	industryUsecase := usecase.NewIndustryUsecase(industryRepo)
+	fingerprintUsecase := usecase.NewFingerprintUsecase(domainRepo, industryRepo, crawlerAdapter, resolverAdapter)
+	scheduleUsecase := usecase.NewScheduleUsecase(
+		repository.NewScheduleRepository(db),
+		repository.NewScheduleRunRepository(db),
+		newLockAdapter(redisClient),
+		slackAdapter,
+		config.Env.SchedulerJobTimeout,
+	)
+	registerJobs(scheduleUsecase, growthUsecase, outboxUsecase, sheetUsecase, homstaUsecase, fingerprintUsecase)
 
 	return handler.NewApiHandler(
 		fetchUsecase,
@@ -54,6 +91,11 @@ func Initialize(
 		sheetUsecase,
 		growthUsecase,
 		homstaUsecase,
+		llmUsecase,
+		industryUsecase,
+		outboxUsecase,
+		scheduleUsecase,
+		fingerprintUsecase,
 		slackAdapter,
-	)
+	), scheduleUsecase, nil
 }
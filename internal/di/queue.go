@@ -0,0 +1,61 @@
+package di
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/zuxt268/sales/internal/config"
+	"github.com/zuxt268/sales/internal/infrastructure"
+	"github.com/zuxt268/sales/internal/interfaces/adapter"
+	"github.com/zuxt268/sales/internal/interfaces/dto/external"
+	"github.com/zuxt268/sales/internal/interfaces/worker"
+)
+
+// newQueue QUEUE_BACKEND に応じてメッセージの送信先を作る
+func newQueue(pubSubClient infrastructure.PubSubClient, redisClient *redis.Client) adapter.Queue {
+	switch config.Env.QueueBackend {
+	case config.QueueBackendPubSub:
+		return adapter.NewPubSubAdapter(pubSubClient)
+	case config.QueueBackendRedis:
+		return adapter.NewRedisQueueAdapter(redisClient, config.Env.QueueStreamMaxLen)
+	}
+	panic(fmt.Sprintf("unknown QUEUE_BACKEND: %s", config.Env.QueueBackend))
+}
+
+// validateDeliveryAttempts WORKER_MAX_ATTEMPTS を使う場合、Pub/Subのサブスクリプションにデッドレターの設定があるか確かめる
+// 設定がないと配信回数が常に0になり、失敗し続けるメッセージがデッドレターに送られず再配信され続けるため起動しない
+func validateDeliveryAttempts(ctx context.Context, pubSubClient infrastructure.PubSubClient) error {
+	if config.Env.QueueBackend != config.QueueBackendPubSub || config.Env.WorkerMaxAttempts <= 0 {
+		return nil
+	}
+	ok, err := pubSubClient.HasDeadLetterPolicy(ctx, config.Env.WorkerSubscription)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("subscription %s has no dead letter policy; set one or WORKER_MAX_ATTEMPTS=0", config.Env.WorkerSubscription)
+	}
+	return nil
+}
+
+// newReceiver QUEUE_BACKEND に応じてワーカーの受信元を作る
+// Redis Streamsの場合は WORKER_SUBSCRIPTION をコンシューマーグループの名前に使う
+func newReceiver(pubSubClient infrastructure.PubSubClient, redisClient *redis.Client) worker.Receiver {
+	switch config.Env.QueueBackend {
+	case config.QueueBackendPubSub:
+		return worker.NewPubSubReceiver(pubSubClient, config.Env.WorkerSubscription, config.Env.WorkerConcurrency)
+	case config.QueueBackendRedis:
+		hostname, _ := os.Hostname()
+		return worker.NewRedisStreamReceiver(
+			redisClient,
+			external.DomainPipelineTopic,
+			config.Env.WorkerSubscription,
+			fmt.Sprintf("%s-%d", hostname, os.Getpid()),
+			config.Env.WorkerConcurrency,
+			config.Env.QueueVisibilityTimeout,
+		)
+	}
+	panic(fmt.Sprintf("unknown QUEUE_BACKEND: %s", config.Env.QueueBackend))
+}
@@ -0,0 +1,31 @@
+package di
+
+import (
+	"github.com/redis/go-redis/v9"
+	"github.com/zuxt268/sales/internal/config"
+	"github.com/zuxt268/sales/internal/interfaces/adapter"
+)
+
+// RedisRequired Redisのキュー・LLMのキャッシュ・スケジューラのロックのいずれかを使う設定か
+// withSchedulerはスケジューラを動かすプロセス（API）の場合にtrueにする
+func RedisRequired(withScheduler bool) bool {
+	return config.Env.QueueBackend == config.QueueBackendRedis ||
+		config.Env.LLMCacheTTL > 0 ||
+		(withScheduler && config.Env.SchedulerEnabled)
+}
+
+// newLLMCache Redisに接続していない場合はキャッシュしない
+func newLLMCache(redisClient *redis.Client) adapter.LLMCacheAdapter {
+	if redisClient == nil {
+		return adapter.NewNoopLLMCacheAdapter()
+	}
+	return adapter.NewLLMCacheAdapter(redisClient, config.Env.LLMCacheTTL)
+}
+
+// newLockAdapter Redisに接続していない場合はプロセスの中でだけ排他する
+func newLockAdapter(redisClient *redis.Client) adapter.LockAdapter {
+	if redisClient == nil {
+		return adapter.NewLocalLockAdapter()
+	}
+	return adapter.NewRedisLockAdapter(redisClient)
+}
@@ -0,0 +1,20 @@
+package di
+
+import (
+	"github.com/zuxt268/sales/internal/config"
+	"github.com/zuxt268/sales/internal/interfaces/adapter"
+	"github.com/zuxt268/sales/internal/model"
+)
+
+// newReverseIPSources ターゲットが選べる逆引きの取得元
+// パッシブDNSの取り込みは REVERSE_IP_IMPORT_DIR を設定した場合だけ使える
+func newReverseIPSources() adapter.ReverseIPSources {
+	viewDnsAdapter := adapter.NewViewDNSAdapter(config.Env.ViewDnsApiUrl, config.Env.ViewDnsTimeout, config.Env.ViewDnsMaxRetries)
+	sources := adapter.ReverseIPSources{
+		model.ReverseIPSourceViewDNS: adapter.NewViewDNSSource(viewDnsAdapter, config.Env.ApiKey),
+	}
+	if config.Env.ReverseIPImportDir != "" {
+		sources[model.ReverseIPSourcePassiveDNS] = adapter.NewPassiveDNSFileSource(config.Env.ReverseIPImportDir)
+	}
+	return sources
+}
@@ -0,0 +1,53 @@
+package di
+
+import (
+	"context"
+	"errors"
+
+	"github.com/zuxt268/sales/internal/config"
+	"github.com/zuxt268/sales/internal/model"
+	"github.com/zuxt268/sales/internal/usecase"
+)
+
+// registerJobs 以前はcronからcurlで呼んでいたバッチをスケジューラに登録する
+func registerJobs(
+	scheduleUsecase usecase.ScheduleUsecase,
+	growthUsecase usecase.GrowthUsecase,
+	outboxUsecase usecase.OutboxUsecase,
+	sheetUsecase usecase.SheetUsecase,
+	homstaUsecase usecase.HomstaUsecase,
+	fingerprintUsecase usecase.FingerprintUsecase,
+) {
+	scheduleUsecase.Register(model.JobFetch, config.Env.ScheduleFetch, growthUsecase.Fetch)
+	scheduleUsecase.Register(model.JobPolling, config.Env.SchedulePolling, func(ctx context.Context) error {
+		if err := growthUsecase.Polling(ctx); err != nil {
+			return err
+		}
+		// 書き込んだメッセージをすぐに送る。送れなかった分は relay で再送する
+		_, err := outboxUsecase.Relay(ctx)
+		return err
+	})
+	scheduleUsecase.Register(model.JobOutput, config.Env.ScheduleOutput, sheetUsecase.BackupDomainsDirectly)
+	scheduleUsecase.Register(model.JobHomsta, config.Env.ScheduleHomsta, func(ctx context.Context) error {
+		if err := homstaUsecase.FetchDomainDetails(ctx); err != nil {
+			return err
+		}
+		// 業種の解析に失敗しても、解析済みのものは出力する
+		return errors.Join(homstaUsecase.AnalyzeIndustry(ctx), homstaUsecase.Output(ctx))
+	})
+	scheduleUsecase.Register(model.JobReap, config.Env.ScheduleReap, func(ctx context.Context) error {
+		if _, err := growthUsecase.Reap(ctx); err != nil {
+			return err
+		}
+		_, err := outboxUsecase.Relay(ctx)
+		return err
+	})
+	scheduleUsecase.Register(model.JobRelay, config.Env.ScheduleRelay, func(ctx context.Context) error {
+		_, err := outboxUsecase.Relay(ctx)
+		return err
+	})
+	scheduleUsecase.Register(model.JobFingerprint, config.Env.ScheduleFingerprint, func(ctx context.Context) error {
+		_, err := fingerprintUsecase.Fingerprint(ctx)
+		return err
+	})
+}
@@ -0,0 +1,85 @@
+package di
+
+import (
+	"context"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/zuxt268/sales/internal/config"
+	"github.com/zuxt268/sales/internal/infrastructure"
+	"github.com/zuxt268/sales/internal/interfaces/adapter"
+	"github.com/zuxt268/sales/internal/interfaces/dto/external"
+	"github.com/zuxt268/sales/internal/interfaces/repository"
+	"github.com/zuxt268/sales/internal/interfaces/worker"
+	"github.com/zuxt268/sales/internal/usecase"
+
+	"gorm.io/gorm"
+)
+
+// InitializeWorker サブスクリプションから受信したメッセージを種類ごとのユースケースに振り分けるワーカーを作る
+func InitializeWorker(
+	db *gorm.DB,
+	sheetClient infrastructure.GoogleSheetsClient,
+	driveClient infrastructure.GoogleDriveClient,
+	pubSubClient infrastructure.PubSubClient,
+	redisClient *redis.Client,
+) (worker.Worker, error) {
+	if err := validateDeliveryAttempts(context.Background(), pubSubClient); err != nil {
+		return nil, err
+	}
+
+	baseRepo := repository.NewBaseRepository(db)
+	domainRepo := repository.NewDomainRepository(db)
+	targetRepo := repository.NewTargetRepository(db)
+	llmUsageRepo := repository.NewLLMUsageRepository(db)
+	industryRepo := repository.NewIndustryRepository(db)
+	historyRepo := repository.NewDomainStatusHistoryRepository(db)
+	outboxRepo := repository.NewOutboxRepository(db)
+	processedRepo := repository.NewProcessedMessageRepository(db)
+	ledgerRepo := repository.NewAPICallLedgerRepository(db)
+	sightingRepo := repository.NewDomainSightingRepository(db)
+	scanRepo := repository.NewTargetScanRepository(db)
+	reverseIPSources := newReverseIPSources()
+	llmCacheAdapter := newLLMCache(redisClient)
+	gptAdapter, err := adapter.NewGptAdapter(llmCacheAdapter)
+	if err != nil {
+		return nil, err
+	}
+	queue := newQueue(pubSubClient, redisClient)
+	crawlerAdapter := adapter.NewCrawlerAdapter(config.Env.CrawlMaxPages)
+	sheetAdapter := adapter.NewSheetAdapter(sheetClient, driveClient)
+
+	growthUsecase := usecase.NewGrowthUsecase(
+		baseRepo,
+		domainRepo,
+		targetRepo,
+		reverseIPSources,
+		sheetAdapter,
+		gptAdapter,
+		crawlerAdapter,
+		adapter.NewSiteHealthAdapter(),
+		llmUsageRepo,
+		industryRepo,
+		historyRepo,
+		outboxRepo,
+		processedRepo,
+		ledgerRepo,
+		sightingRepo,
+		scanRepo,
+	)
+
+	w := worker.NewWorker(
+		newReceiver(pubSubClient, redisClient),
+		queue,
+		worker.Config{
+			Concurrency:     config.Env.WorkerConcurrency,
+			MaxAttempts:     config.Env.WorkerMaxAttempts,
+			HandlerTimeout:  config.Env.WorkerHandlerTimeout,
+			DeadLetterTopic: config.Env.WorkerDeadLetterTopic,
+		},
+	)
+	w.Handle(external.PipelineAnalyze, growthUsecase.Analyze)
+	w.Handle(external.PipelineCheckWix, growthUsecase.CheckWix)
+	w.Handle(external.PipelineCheckView, growthUsecase.CheckView)
+	w.Handle(external.PipelineCheckJapan, growthUsecase.CheckJapan)
+	return w, nil
+}
@@ -0,0 +1,211 @@
+package entity
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Prefectures 47都道府県
+var Prefectures = []string{
+	"北海道", "青森県", "岩手県", "宮城県", "秋田県", "山形県", "福島県",
+	"茨城県", "栃木県", "群馬県", "埼玉県", "千葉県", "東京都", "神奈川県",
+	"新潟県", "富山県", "石川県", "福井県", "山梨県", "長野県", "岐阜県",
+	"静岡県", "愛知県", "三重県", "滋賀県", "京都府", "大阪府", "兵庫県",
+	"奈良県", "和歌山県", "鳥取県", "島根県", "岡山県", "広島県", "山口県",
+	"徳島県", "香川県", "愛媛県", "高知県", "福岡県", "佐賀県", "長崎県",
+	"熊本県", "大分県", "宮崎県", "鹿児島県", "沖縄県",
+}
+
+// IsPrefecture 47都道府県のいずれかかどうか
+func IsPrefecture(s string) bool {
+	for _, p := range Prefectures {
+		if s == p {
+			return true
+		}
+	}
+	return false
+}
+
+// postalCodeRanges 郵便番号上3桁の範囲と都道府県の対応（おおよその区分）
+var postalCodeRanges = []struct {
+	from, to   int
+	prefecture string
+}{
+	{1, 9, "北海道"},
+	{10, 19, "秋田県"},
+	{20, 29, "岩手県"},
+	{30, 39, "青森県"},
+	{40, 99, "北海道"},
+	{100, 209, "東京都"},
+	{210, 259, "神奈川県"},
+	{260, 299, "千葉県"},
+	{300, 319, "茨城県"},
+	{320, 329, "栃木県"},
+	{330, 369, "埼玉県"},
+	{370, 379, "群馬県"},
+	{380, 399, "長野県"},
+	{400, 409, "山梨県"},
+	{410, 439, "静岡県"},
+	{440, 499, "愛知県"},
+	{500, 509, "岐阜県"},
+	{510, 519, "三重県"},
+	{520, 529, "滋賀県"},
+	{530, 599, "大阪府"},
+	{600, 629, "京都府"},
+	{630, 639, "奈良県"},
+	{640, 649, "和歌山県"},
+	{650, 679, "兵庫県"},
+	{680, 689, "鳥取県"},
+	{690, 699, "島根県"},
+	{700, 719, "岡山県"},
+	{720, 739, "広島県"},
+	{740, 759, "山口県"},
+	{760, 769, "香川県"},
+	{770, 779, "徳島県"},
+	{780, 789, "高知県"},
+	{790, 799, "愛媛県"},
+	{800, 839, "福岡県"},
+	{840, 849, "佐賀県"},
+	{850, 859, "長崎県"},
+	{860, 869, "熊本県"},
+	{870, 879, "大分県"},
+	{880, 889, "宮崎県"},
+	{890, 899, "鹿児島県"},
+	{900, 909, "沖縄県"},
+	{910, 919, "福井県"},
+	{920, 929, "石川県"},
+	{930, 939, "富山県"},
+	{940, 959, "新潟県"},
+	{960, 979, "福島県"},
+	{980, 989, "宮城県"},
+	{990, 999, "山形県"},
+}
+
+// PrefectureFromPostalCode 郵便番号から都道府県を判定する。判定できない場合は空文字
+func PrefectureFromPostalCode(code string) string {
+	digits := onlyDigits(NormalizeDigits(code))
+	if len(digits) != 7 {
+		return ""
+	}
+	head, err := strconv.Atoi(digits[:3])
+	if err != nil {
+		return ""
+	}
+	for _, r := range postalCodeRanges {
+		if head >= r.from && head <= r.to {
+			return r.prefecture
+		}
+	}
+	return ""
+}
+
+// PrefectureFromAddress 住所に含まれる最初の都道府県名を返す。見つからない場合は空文字
+func PrefectureFromAddress(address string) string {
+	found := ""
+	pos := -1
+	for _, p := range Prefectures {
+		if i := strings.Index(address, p); i >= 0 && (pos < 0 || i < pos) {
+			found = p
+			pos = i
+		}
+	}
+	return found
+}
+
+var (
+	postalCodePattern = regexp.MustCompile(`(〒\s*)?(\d{3})-(\d{4})|〒\s*(\d{3})(\d{4})`)
+	addressPattern    = regexp.MustCompile(`(` + strings.Join(Prefectures, "|") + `)[^\s　、。,:：|｜/]{2,60}`)
+)
+
+// ExtractPostalCodes テキストから郵便番号（123-4567形式）を抽出する
+// ハイフンなしの7桁は〒が付いている場合のみ対象にする
+func ExtractPostalCodes(text string) []string {
+	text = NormalizeDigits(text)
+	codes := make([]string, 0)
+	seen := make(map[string]struct{})
+	for _, loc := range postalCodePattern.FindAllStringSubmatchIndex(text, -1) {
+		if loc[0] > 0 && (isDigit(text[loc[0]-1]) || text[loc[0]-1] == '-') {
+			continue
+		}
+		if loc[1] < len(text) && (isDigit(text[loc[1]]) || text[loc[1]] == '-') {
+			continue
+		}
+		var code string
+		if loc[4] >= 0 {
+			code = text[loc[4]:loc[5]] + "-" + text[loc[6]:loc[7]]
+		} else {
+			code = text[loc[8]:loc[9]] + "-" + text[loc[10]:loc[11]]
+		}
+		if _, ok := seen[code]; ok {
+			continue
+		}
+		seen[code] = struct{}{}
+		codes = append(codes, code)
+	}
+	return codes
+}
+
+// ExtractAddresses テキストから都道府県名で始まる住所らしき文字列を抽出する
+func ExtractAddresses(text string) []string {
+	text = NormalizeDigits(text)
+	addresses := make([]string, 0)
+	seen := make(map[string]struct{})
+	for _, m := range addressPattern.FindAllString(text, -1) {
+		address := strings.TrimRight(m, "-")
+		if !containsDigit(address) {
+			continue
+		}
+		if _, ok := seen[address]; ok {
+			continue
+		}
+		seen[address] = struct{}{}
+		addresses = append(addresses, address)
+	}
+	return addresses
+}
+
+// ContactInfo ページから機械的に抽出した連絡先情報
+type ContactInfo struct {
+	Phones      []string
+	PostalCodes []string
+	Addresses   []string
+	Prefecture  string
+}
+
+// ExtractContactInfo テキストから電話番号・郵便番号・住所・都道府県を抽出する
+// 都道府県は住所の表記を優先し、なければ郵便番号から判定する
+func ExtractContactInfo(text string) ContactInfo {
+	info := ContactInfo{
+		Phones:      ExtractPhones(text),
+		PostalCodes: ExtractPostalCodes(text),
+		Addresses:   ExtractAddresses(text),
+	}
+	for _, a := range info.Addresses {
+		if p := PrefectureFromAddress(a); p != "" {
+			info.Prefecture = p
+			return info
+		}
+	}
+	for _, c := range info.PostalCodes {
+		if p := PrefectureFromPostalCode(c); p != "" {
+			info.Prefecture = p
+			return info
+		}
+	}
+	return info
+}
+
+// AnalysisHints 抽出した連絡先のうちGPTに尋ねなくてよい項目
+func (c ContactInfo) AnalysisHints() AnalysisHints {
+	return AnalysisHints{Prefecture: c.Prefecture}
+}
+
+func containsDigit(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if isDigit(s[i]) {
+			return true
+		}
+	}
+	return false
+}
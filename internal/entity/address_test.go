@@ -0,0 +1,74 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrefectureFromPostalCode(t *testing.T) {
+	tests := []struct {
+		code string
+		want string
+	}{
+		{"100-0001", "東京都"},
+		{"〒060-0001", "北海道"},
+		{"0300801", "青森県"},
+		{"530-0001", "大阪府"},
+		{"６００－８２１６", "京都府"},
+		{"900-0001", "沖縄県"},
+		{"980-0811", "宮城県"},
+		{"12-345", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.code, func(t *testing.T) {
+			assert.Equal(t, tt.want, PrefectureFromPostalCode(tt.code))
+		})
+	}
+}
+
+func TestPrefectureFromAddress(t *testing.T) {
+	assert.Equal(t, "京都府", PrefectureFromAddress("京都府京都市下京区"))
+	assert.Equal(t, "東京都", PrefectureFromAddress("本社：東京都港区 / 支社：大阪府大阪市"))
+	assert.Equal(t, "神奈川県", PrefectureFromAddress("神奈川県横浜市"))
+	assert.Equal(t, "", PrefectureFromAddress("横浜市中区"))
+}
+
+func TestExtractPostalCodes(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{"〒付き", "〒100-0001 東京都千代田区", []string{"100-0001"}},
+		{"〒付きハイフンなし", "〒1000001", []string{"100-0001"}},
+		{"全角", "〒５３０－０００１", []string{"530-0001"}},
+		{"〒なしハイフンあり", "住所 530-0001 大阪府", []string{"530-0001"}},
+		{"電話番号は対象外", "03-1234-5678 / 0120-123-4567", []string{}},
+		{"〒なしハイフンなしは対象外", "1000001", []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ExtractPostalCodes(tt.text))
+		})
+	}
+}
+
+func TestExtractAddresses(t *testing.T) {
+	assert.Equal(t,
+		[]string{"東京都千代田区丸の内1-1-1"},
+		ExtractAddresses("所在地：〒100-0001 東京都千代田区丸の内１－１－１ 〇〇ビル"),
+	)
+	assert.Equal(t, []string{}, ExtractAddresses("東京都の美味しいお店"))
+}
+
+func TestExtractContactInfo(t *testing.T) {
+	info := ExtractContactInfo("株式会社タロウ 〒530-0001 大阪府大阪市北区梅田1-2-3 TEL 06-1234-5678 携帯 090-1234-5678")
+	assert.Equal(t, []string{"06-1234-5678", "090-1234-5678"}, info.Phones)
+	assert.Equal(t, []string{"530-0001"}, info.PostalCodes)
+	assert.Equal(t, []string{"大阪府大阪市北区梅田1-2-3"}, info.Addresses)
+	assert.Equal(t, "大阪府", info.Prefecture)
+
+	info = ExtractContactInfo("〒060-0001 札幌市中央区北1条西")
+	assert.Equal(t, "北海道", info.Prefecture)
+}
@@ -0,0 +1,70 @@
+package entity
+
+import "fmt"
+
+// CompanyAnalysis GPTによる企業情報の解析結果
+// 各項目のConfidenceは0〜1の確信度。見つからなかった項目は空文字
+type CompanyAnalysis struct {
+	Industry             string  `json:"industry"`
+	IndustryConfidence   float64 `json:"industry_confidence"`
+	President            string  `json:"president"`
+	PresidentConfidence  float64 `json:"president_confidence"`
+	Company              string  `json:"company"`
+	CompanyConfidence    float64 `json:"company_confidence"`
+	Prefecture           string  `json:"prefecture"`
+	PrefectureConfidence float64 `json:"prefecture_confidence"`
+}
+
+// AnalysisHints GPTに尋ねる前にページから機械的に判定できた項目。判定できた項目はGPTに尋ねない
+type AnalysisHints struct {
+	Prefecture string
+}
+
+// ApplyHints 機械的に判定できた項目を確信度1として解析結果に反映する
+func (a *CompanyAnalysis) ApplyHints(h AnalysisHints) {
+	if h.Prefecture != "" {
+		a.Prefecture = h.Prefecture
+		a.PrefectureConfidence = 1
+	}
+}
+
+// NotFoundAnswer GPTが項目を見つけられなかった場合の回答
+const NotFoundAnswer = "なし"
+
+// Normalize 「なし」と回答された項目を空文字にする
+func (a *CompanyAnalysis) Normalize() {
+	for _, s := range []*string{&a.Industry, &a.President, &a.Company, &a.Prefecture} {
+		if *s == NotFoundAnswer {
+			*s = ""
+		}
+	}
+}
+
+// Validate 解析結果を検証し、要確認とすべき理由を返す。問題がなければ空
+// 業種は必須で業種リストに含まれること、都道府県は47都道府県のいずれかであること、
+// 回答のある項目の確信度がminConfidence以上であることを確認する
+func (a CompanyAnalysis) Validate(minConfidence float64) []string {
+	reasons := make([]string, 0)
+	switch {
+	case a.Industry == "":
+		reasons = append(reasons, "業種が判定できません")
+	case !IsIndustry(a.Industry):
+		reasons = append(reasons, fmt.Sprintf("業種がリストにありません(%s)", a.Industry))
+	case a.IndustryConfidence < minConfidence:
+		reasons = append(reasons, fmt.Sprintf("業種の確信度が低いです(%.2f)", a.IndustryConfidence))
+	}
+	if a.Prefecture != "" {
+		if !IsPrefecture(a.Prefecture) {
+			reasons = append(reasons, fmt.Sprintf("都道府県が不正です(%s)", a.Prefecture))
+		} else if a.PrefectureConfidence < minConfidence {
+			reasons = append(reasons, fmt.Sprintf("都道府県の確信度が低いです(%.2f)", a.PrefectureConfidence))
+		}
+	}
+	if a.Company != "" && a.CompanyConfidence < minConfidence {
+		reasons = append(reasons, fmt.Sprintf("会社名の確信度が低いです(%.2f)", a.CompanyConfidence))
+	}
+	if a.President != "" && a.PresidentConfidence < minConfidence {
+		reasons = append(reasons, fmt.Sprintf("代表者名の確信度が低いです(%.2f)", a.PresidentConfidence))
+	}
+	return reasons
+}
@@ -0,0 +1,80 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompanyAnalysis_Normalize(t *testing.T) {
+	a := CompanyAnalysis{
+		Industry:   "広告業",
+		President:  "なし",
+		Company:    "株式会社タロウ",
+		Prefecture: "なし",
+	}
+	a.Normalize()
+	assert.Equal(t, "広告業", a.Industry)
+	assert.Equal(t, "", a.President)
+	assert.Equal(t, "株式会社タロウ", a.Company)
+	assert.Equal(t, "", a.Prefecture)
+}
+
+func TestCompanyAnalysis_Validate(t *testing.T) {
+	valid := CompanyAnalysis{
+		Industry:             "自動車整備業",
+		IndustryConfidence:   0.9,
+		President:            "山田太郎",
+		PresidentConfidence:  0.8,
+		Company:              "株式会社タロウ",
+		CompanyConfidence:    0.95,
+		Prefecture:           "東京都",
+		PrefectureConfidence: 0.7,
+	}
+
+	tests := []struct {
+		name   string
+		modify func(a *CompanyAnalysis)
+		want   int
+	}{
+		{"valid", func(a *CompanyAnalysis) {}, 0},
+		{"not found fields are allowed", func(a *CompanyAnalysis) {
+			a.President, a.PresidentConfidence = "", 0
+			a.Company, a.CompanyConfidence = "", 0
+			a.Prefecture, a.PrefectureConfidence = "", 0
+		}, 0},
+		{"empty industry", func(a *CompanyAnalysis) { a.Industry = "" }, 1},
+		{"unknown industry", func(a *CompanyAnalysis) { a.Industry = "自動車修理" }, 1},
+		{"low industry confidence", func(a *CompanyAnalysis) { a.IndustryConfidence = 0.3 }, 1},
+		{"invalid prefecture", func(a *CompanyAnalysis) { a.Prefecture = "東京" }, 1},
+		{"low prefecture confidence", func(a *CompanyAnalysis) { a.PrefectureConfidence = 0.1 }, 1},
+		{"low company and president confidence", func(a *CompanyAnalysis) {
+			a.CompanyConfidence = 0.2
+			a.PresidentConfidence = 0.2
+		}, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := valid
+			tt.modify(&a)
+			assert.Len(t, a.Validate(0.6), tt.want)
+		})
+	}
+}
+
+func TestIsIndustry(t *testing.T) {
+	assert.True(t, IsIndustry("自動車整備業"))
+	assert.True(t, IsIndustry("分類不能の産業"))
+	assert.False(t, IsIndustry("自動車"))
+	assert.False(t, IsIndustry(""))
+}
+
+func TestCompanyAnalysis_ApplyHints(t *testing.T) {
+	a := CompanyAnalysis{Industry: "自動車整備業", Prefecture: "大阪府", PrefectureConfidence: 0.3}
+	a.ApplyHints(AnalysisHints{})
+	assert.Equal(t, "大阪府", a.Prefecture)
+
+	a.ApplyHints(ExtractContactInfo("〒100-0001 東京都千代田区千代田1-1").AnalysisHints())
+	assert.Equal(t, "東京都", a.Prefecture)
+	assert.Equal(t, 1.0, a.PrefectureConfidence)
+}
@@ -0,0 +1,105 @@
+package entity
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// companyInfoKeywords 会社情報が載っていそうなリンクのキーワードと重み
+var companyInfoKeywords = []struct {
+	word  string
+	score int
+}{
+	{"会社概要", 10},
+	{"企業概要", 10},
+	{"会社案内", 9},
+	{"企業情報", 9},
+	{"会社情報", 9},
+	{"特定商取引", 8},
+	{"特商法", 8},
+	{"運営会社", 8},
+	{"運営者", 6},
+	{"代表挨拶", 6},
+	{"ごあいさつ", 4},
+	{"アクセス", 5},
+	{"所在地", 5},
+	{"店舗情報", 4},
+	{"お問い合わせ", 2},
+	{"company", 8},
+	{"corporate", 7},
+	{"about", 7},
+	{"profile", 6},
+	{"outline", 6},
+	{"overview", 5},
+	{"tokusho", 8},
+	{"tokutei", 8},
+	{"law", 4},
+	{"access", 5},
+	{"greeting", 4},
+	{"message", 3},
+	{"contact", 2},
+}
+
+// CompanyInfoLinkScore リンクのhrefとテキストから会社情報ページらしさを点数化する
+// 0の場合は対象外
+func CompanyInfoLinkScore(href, text string) int {
+	href = strings.ToLower(href)
+	text = strings.ToLower(strings.TrimSpace(text))
+	if strings.HasPrefix(href, "mailto:") || strings.HasPrefix(href, "tel:") || strings.HasPrefix(href, "javascript:") {
+		return 0
+	}
+	score := 0
+	for _, k := range companyInfoKeywords {
+		if strings.Contains(text, k.word) {
+			score += k.score
+		}
+		if strings.Contains(href, k.word) {
+			score += k.score
+		}
+	}
+	return score
+}
+
+// BuildCorpus ページごとのテキスト断片を重複排除して結合し、maxRunes文字以内に収める
+// ヘッダーやフッターなど各ページで繰り返される断片は最初の1回だけ残す
+func BuildCorpus(pages [][]string, maxRunes int) string {
+	seen := make(map[string]struct{})
+	var b strings.Builder
+	size := 0
+	for _, segments := range pages {
+		for _, s := range segments {
+			s = strings.Join(strings.Fields(s), " ")
+			if s == "" {
+				continue
+			}
+			if _, ok := seen[s]; ok {
+				continue
+			}
+			seen[s] = struct{}{}
+
+			n := utf8.RuneCountInString(s)
+			if size > 0 {
+				n++
+			}
+			if maxRunes > 0 && size+n > maxRunes {
+				rest := maxRunes - size
+				if size > 0 {
+					rest--
+				}
+				if rest > 0 {
+					if size > 0 {
+						b.WriteString("\n")
+					}
+					b.WriteString(string([]rune(s)[:rest]))
+				}
+				return b.String()
+			}
+			if size > 0 {
+				b.WriteString("\n")
+			}
+			b.WriteString(s)
+			size += n
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,25 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompanyInfoLinkScore(t *testing.T) {
+	assert.Greater(t, CompanyInfoLinkScore("/company/", "会社概要"), CompanyInfoLinkScore("/access/", "アクセス"))
+	assert.Greater(t, CompanyInfoLinkScore("/law.html", "特定商取引法に基づく表記"), 0)
+	assert.Greater(t, CompanyInfoLinkScore("/about-us", "About"), 0)
+	assert.Equal(t, 0, CompanyInfoLinkScore("/blog/2024/01", "ブログ"))
+	assert.Equal(t, 0, CompanyInfoLinkScore("mailto:info@example.com", "company"))
+}
+
+func TestBuildCorpus(t *testing.T) {
+	pages := [][]string{
+		{"ホーム", "会社概要", "株式会社タロウ", "Copyright"},
+		{"ホーム", "アクセス", "東京都  千代田区", "Copyright"},
+	}
+	assert.Equal(t, "ホーム\n会社概要\n株式会社タロウ\nCopyright\nアクセス\n東京都 千代田区", BuildCorpus(pages, 0))
+	assert.Equal(t, "ホーム\n会社", BuildCorpus(pages, 6))
+	assert.Equal(t, "", BuildCorpus(nil, 10))
+}
@@ -0,0 +1,155 @@
+package entity
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CronSchedule 「分 時 日 月 曜日」の5つのフィールドからなるcron式
+// 各フィールドは * / 数値 / 範囲(a-b) / 間隔(*/n, a-b/n) / それらのカンマ区切りで書ける
+type CronSchedule struct {
+	minute, hour, dom, month, dow uint64
+	// 日と曜日の両方を指定した場合はどちらかに一致すれば実行する（cronと同じ）
+	domRestricted, dowRestricted bool
+}
+
+var cronDescriptors = map[string]string{
+	"@hourly":  "0 * * * *",
+	"@daily":   "0 0 * * *",
+	"@weekly":  "0 0 * * 0",
+	"@monthly": "0 0 1 * *",
+}
+
+type cronField struct {
+	name     string
+	min, max int
+}
+
+var cronFields = []cronField{
+	{"minute", 0, 59},
+	{"hour", 0, 23},
+	{"day of month", 1, 31},
+	{"month", 1, 12},
+	{"day of week", 0, 7},
+}
+
+// cronCheckFrom 実行日が来るかを確かめる基準の日時
+var cronCheckFrom = time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// ParseCron cron式を解析する。@hourly / @daily / @weekly / @monthly も使える
+func ParseCron(expr string) (CronSchedule, error) {
+	expr = strings.TrimSpace(expr)
+	if v, ok := cronDescriptors[expr]; ok {
+		expr = v
+	}
+	parts := strings.Fields(expr)
+	if len(parts) != len(cronFields) {
+		return CronSchedule{}, fmt.Errorf("cron expression must have 5 fields: %q: %w", expr, ErrValidation)
+	}
+
+	bits := make([]uint64, len(cronFields))
+	for i, f := range cronFields {
+		b, err := parseCronField(parts[i], f)
+		if err != nil {
+			return CronSchedule{}, err
+		}
+		bits[i] = b
+	}
+	// 曜日の7は日曜日として扱う
+	if bits[4]&(1<<7) != 0 {
+		bits[4] = bits[4]&^(1<<7) | 1
+	}
+	s := CronSchedule{
+		minute:        bits[0],
+		hour:          bits[1],
+		dom:           bits[2],
+		month:         bits[3],
+		dow:           bits[4],
+		domRestricted: !strings.HasPrefix(parts[2], "*"),
+		dowRestricted: !strings.HasPrefix(parts[4], "*"),
+	}
+	// 2月30日のように実行日が来ない式は、Nextが見つからず実行されなくなるため受け付けない
+	// Nextはうるう年を含む4年先まで探すため、基準の日時はいつでもよい
+	if s.Next(cronCheckFrom).IsZero() {
+		return CronSchedule{}, fmt.Errorf("cron expression never fires: %q: %w", expr, ErrValidation)
+	}
+	return s, nil
+}
+
+func parseCronField(s string, f cronField) (uint64, error) {
+	var bits uint64
+	for _, item := range strings.Split(s, ",") {
+		rng, step := item, 1
+		if i := strings.Index(item, "/"); i >= 0 {
+			n, err := strconv.Atoi(item[i+1:])
+			if err != nil || n < 1 {
+				return 0, fmt.Errorf("invalid step in %s field: %q: %w", f.name, item, ErrValidation)
+			}
+			rng, step = item[:i], n
+		}
+
+		lo, hi := f.min, f.max
+		if rng != "*" {
+			var err error
+			if i := strings.Index(rng, "-"); i >= 0 {
+				lo, err = strconv.Atoi(rng[:i])
+				if err == nil {
+					hi, err = strconv.Atoi(rng[i+1:])
+				}
+			} else {
+				lo, err = strconv.Atoi(rng)
+				hi = lo
+				if step > 1 {
+					// 5/15 のように開始だけ指定した場合は最大値まで
+					hi = f.max
+				}
+			}
+			if err != nil || lo < f.min || hi > f.max || lo > hi {
+				return 0, fmt.Errorf("invalid %s field: %q: %w", f.name, item, ErrValidation)
+			}
+		}
+		for v := lo; v <= hi; v += step {
+			bits |= 1 << v
+		}
+	}
+	return bits, nil
+}
+
+// Next tより後で最初に実行する日時。分未満は切り捨てる
+// 4年以内に実行日時がない場合（2月30日など）はゼロ値を返す
+func (s CronSchedule) Next(t time.Time) time.Time {
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(4, 0, 0)
+
+	for t.Before(limit) {
+		if s.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if !s.matchDay(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if s.hour&(1<<uint(t.Hour())) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+			continue
+		}
+		if s.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+func (s CronSchedule) matchDay(t time.Time) bool {
+	dom := s.dom&(1<<uint(t.Day())) != 0
+	dow := s.dow&(1<<uint(t.Weekday())) != 0
+	if s.domRestricted && s.dowRestricted {
+		return dom || dow
+	}
+	return dom && dow
+}
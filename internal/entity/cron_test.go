@@ -0,0 +1,62 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCronSchedule_Next(t *testing.T) {
+	jst := time.FixedZone("JST", 9*60*60)
+	// 2026-10-18 は日曜日
+	base := time.Date(2026, 10, 18, 10, 7, 30, 0, jst)
+	tests := []struct {
+		expr string
+		want time.Time
+	}{
+		{"* * * * *", time.Date(2026, 10, 18, 10, 8, 0, 0, jst)},
+		{"*/10 * * * *", time.Date(2026, 10, 18, 10, 10, 0, 0, jst)},
+		{"5 * * * *", time.Date(2026, 10, 18, 11, 5, 0, 0, jst)},
+		{"0 3 * * *", time.Date(2026, 10, 19, 3, 0, 0, 0, jst)},
+		{"30 9-18/3 * * *", time.Date(2026, 10, 18, 12, 30, 0, 0, jst)},
+		{"0 0 1 * *", time.Date(2026, 11, 1, 0, 0, 0, 0, jst)},
+		{"0 9 * * 1-5", time.Date(2026, 10, 19, 9, 0, 0, 0, jst)},
+		{"0 9 * * 7", time.Date(2026, 10, 25, 9, 0, 0, 0, jst)},
+		{"0 0 1 1 *", time.Date(2027, 1, 1, 0, 0, 0, 0, jst)},
+		// 日と曜日の両方を指定した場合はどちらかに一致すればよい
+		{"0 0 20 * 2", time.Date(2026, 10, 20, 0, 0, 0, 0, jst)},
+		{"0 0 29 2 *", time.Date(2028, 2, 29, 0, 0, 0, 0, jst)},
+		{"@daily", time.Date(2026, 10, 19, 0, 0, 0, 0, jst)},
+		{"@hourly", time.Date(2026, 10, 18, 11, 0, 0, 0, jst)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			s, err := ParseCron(tt.expr)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, s.Next(base))
+		})
+	}
+}
+
+func TestParseCron_Invalid(t *testing.T) {
+	for _, expr := range []string{
+		"",
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"5-1 * * * *",
+		"*/0 * * * *",
+		"a * * * *",
+		"@yearly",
+		// 実行日が来ない
+		"0 0 30 2 *",
+		"0 0 31 4,6,9,11 *",
+	} {
+		_, err := ParseCron(expr)
+		assert.ErrorIs(t, err, ErrValidation, expr)
+	}
+}
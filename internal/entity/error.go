@@ -22,6 +22,9 @@ var (
 var (
 	ErrExternalAPI = errors.New("external API error")
 	ErrTimeout     = errors.New("timeout")
+
+	// ErrViewDNSQuotaExceeded ViewDNSの1日の呼び出し上限に達した
+	ErrViewDNSQuotaExceeded = errors.New("viewdns daily quota exceeded")
 )
 
 // データベース関連エラー
@@ -37,6 +40,11 @@ var (
 	ErrForbidden    = errors.New("forbidden")
 )
 
+// LLM関連エラー
+var (
+	ErrLLMBudgetExceeded = errors.New("llm budget exceeded")
+)
+
 // その他
 var (
 	ErrInternal = errors.New("internal error")
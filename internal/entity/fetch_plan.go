@@ -0,0 +1,96 @@
+package entity
+
+import (
+	"sort"
+	"time"
+
+	"github.com/zuxt268/sales/internal/model"
+)
+
+const (
+	targetBackoffBase = time.Hour
+	targetBackoffMax  = 24 * time.Hour
+)
+
+// IsTargetReady 今回のFetchで取得してよいか
+// 重みが0のもの、無効にしたもの、失敗後の待機中のものは取得しない
+func IsTargetReady(t *model.Target, now time.Time) bool {
+	if t.Weight <= 0 || t.Status == model.TargetStatusDisabled {
+		return false
+	}
+	return t.NextFetchAt == nil || !t.NextFetchAt.After(now)
+}
+
+// TargetBackoff 連続してfailures回失敗したターゲットを次に取得するまでの時間
+// 1時間から倍々に延ばし、24時間で頭打ちにする
+func TargetBackoff(failures int) time.Duration {
+	d := targetBackoffBase
+	for i := 1; i < failures && d < targetBackoffMax; i++ {
+		d *= 2
+	}
+	return min(d, targetBackoffMax)
+}
+
+// SortTargetsByLastFetched 最近取得していないものから並べる（LastFetchedAt が NULL のものを先）
+func SortTargetsByLastFetched(targets []*model.Target) {
+	sort.SliceStable(targets, func(i, j int) bool {
+		ti, tj := targets[i], targets[j]
+		if ti.LastFetchedAt == nil && tj.LastFetchedAt != nil {
+			return true
+		}
+		if ti.LastFetchedAt != nil && tj.LastFetchedAt == nil {
+			return false
+		}
+		if ti.LastFetchedAt == nil && tj.LastFetchedAt == nil {
+			return ti.ID < tj.ID
+		}
+		return ti.LastFetchedAt.Before(*tj.LastFetchedAt)
+	})
+}
+
+// PlanFetch 1回のFetchで呼び出すReverseIPの順番を決める。1要素が1ページ分の呼び出し
+// budgetのうちnonWixRatioの割合をWix以外のターゲットに、残りをWixのターゲットに割り当て、片方で余った枠はもう片方に回す
+// 各ターゲットは最近取得していないものから1ページずつ順に回し、重みの回数まで繰り返す
+func PlanFetch(targets []*model.Target, now time.Time, budget int, nonWixRatio float64) []*model.Target {
+	var nonWix, wix []*model.Target
+	for _, t := range targets {
+		if !IsTargetReady(t, now) {
+			continue
+		}
+		if t.Platform == model.PlatformWix {
+			wix = append(wix, t)
+		} else {
+			nonWix = append(nonWix, t)
+		}
+	}
+	SortTargetsByLastFetched(nonWix)
+	SortTargetsByLastFetched(wix)
+
+	budget = max(budget, 0)
+	nonWixCalls := weightedRounds(nonWix, int(float64(budget)*nonWixRatio))
+	wixCalls := weightedRounds(wix, budget-len(nonWixCalls))
+	nonWixCalls = weightedRounds(nonWix, budget-len(wixCalls))
+	return append(nonWixCalls, wixCalls...)
+}
+
+// weightedRounds 重みの範囲でターゲットを1周ずつ回し、limit件まで並べる
+func weightedRounds(targets []*model.Target, limit int) []*model.Target {
+	var calls []*model.Target
+	for round := 0; len(calls) < limit; round++ {
+		added := false
+		for _, t := range targets {
+			if t.Weight <= round {
+				continue
+			}
+			calls = append(calls, t)
+			added = true
+			if len(calls) >= limit {
+				break
+			}
+		}
+		if !added {
+			break
+		}
+	}
+	return calls
+}
@@ -0,0 +1,75 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zuxt268/sales/internal/model"
+)
+
+func planIDs(calls []*model.Target) []int {
+	ids := make([]int, 0, len(calls))
+	for _, t := range calls {
+		ids = append(ids, t.ID)
+	}
+	return ids
+}
+
+func TestIsTargetReady(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	past, future := now.Add(-time.Minute), now.Add(time.Minute)
+
+	assert.True(t, IsTargetReady(&model.Target{Weight: 1}, now))
+	assert.True(t, IsTargetReady(&model.Target{Weight: 1, NextFetchAt: &past}, now))
+	assert.True(t, IsTargetReady(&model.Target{Weight: 1, NextFetchAt: &now}, now))
+	assert.False(t, IsTargetReady(&model.Target{Weight: 1, NextFetchAt: &future}, now))
+	assert.False(t, IsTargetReady(&model.Target{Weight: 0}, now))
+	assert.False(t, IsTargetReady(&model.Target{Weight: 1, Status: model.TargetStatusDisabled}, now))
+}
+
+func TestTargetBackoff(t *testing.T) {
+	assert.Equal(t, time.Hour, TargetBackoff(0))
+	assert.Equal(t, time.Hour, TargetBackoff(1))
+	assert.Equal(t, 2*time.Hour, TargetBackoff(2))
+	assert.Equal(t, 16*time.Hour, TargetBackoff(5))
+	assert.Equal(t, 24*time.Hour, TargetBackoff(6))
+	assert.Equal(t, 24*time.Hour, TargetBackoff(100))
+}
+
+func TestPlanFetch(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	old, recent, future := now.Add(-48*time.Hour), now.Add(-time.Hour), now.Add(time.Hour)
+
+	targets := func() []*model.Target {
+		return []*model.Target{
+			{ID: 1, Name: "a", Weight: 1, LastFetchedAt: &recent},
+			{ID: 2, Name: "b", Weight: 3, LastFetchedAt: &old},
+			{ID: 3, Name: "c", Weight: 1},
+			{ID: 4, Name: "d", Weight: 1, NextFetchAt: &future},
+			{ID: 5, Name: "e", Weight: 0},
+			{ID: 6, Name: "WIX", Platform: model.PlatformWix, Weight: 2, LastFetchedAt: &recent},
+			{ID: 7, Name: "WIX", Platform: model.PlatformWix, Weight: 1, LastFetchedAt: &old},
+		}
+	}
+
+	tests := []struct {
+		name   string
+		budget int
+		ratio  float64
+		want   []int
+	}{
+		{"split by ratio", 5, 0.6, []int{3, 2, 1, 7, 6}},
+		{"weight repeats targets in rounds", 10, 0.5, []int{3, 2, 1, 2, 2, 7, 6, 6}},
+		{"unused wix budget goes to non wix", 8, 0.25, []int{3, 2, 1, 2, 2, 7, 6, 6}},
+		{"wix gets the rest of the budget", 6, 0.5, []int{3, 2, 1, 7, 6, 6}},
+		{"unused non wix budget goes to wix", 10, 0.9, []int{3, 2, 1, 2, 2, 7, 6, 6}},
+		{"all wix", 2, 0, []int{7, 6}},
+		{"no budget", 0, 0.8, []int{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, planIDs(PlanFetch(targets(), now, tt.budget, tt.ratio)))
+		})
+	}
+}
@@ -0,0 +1,138 @@
+package entity
+
+import (
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/zuxt268/sales/internal/model"
+)
+
+// SiteFingerprint サイトを作ったCMS・ビルダーと、バージョンの手がかり
+type SiteFingerprint struct {
+	CMS     string
+	Version string
+}
+
+var (
+	metaGenerator = regexp.MustCompile(`(?i)<meta\s[^>]*name=["']generator["'][^>]*>`)
+	metaContent   = regexp.MustCompile(`(?i)content=["']([^"']*)["']`)
+	versionNumber = regexp.MustCompile(`\d+(?:\.\d+)+`)
+)
+
+// cmsRule CMSごとの判定の手がかり。ヘッダー名・generatorの接頭辞・HTMLに含まれる文字列のいずれかに合えばそのCMSとみなす
+type cmsRule struct {
+	cms        string
+	headers    []string
+	generators []string
+	markers    []string
+}
+
+// cmsRules 判定する順。ほかのCMSの資産を読み込むサイトもあるため、手がかりの強いものから並べる
+var cmsRules = []cmsRule{
+	{
+		cms:        model.CMSWix,
+		headers:    []string{"X-Wix-Request-Id"},
+		generators: []string{"wix.com"},
+		markers:    []string{"static.wixstatic.com", "static.parastorage.com"},
+	},
+	{
+		cms:        model.CMSShopify,
+		headers:    []string{"X-Shopid", "X-Shopify-Stage"},
+		generators: []string{"shopify"},
+		markers:    []string{"cdn.shopify.com", "shopify.theme"},
+	},
+	{
+		cms:        model.CMSJimdo,
+		generators: []string{"jimdo"},
+		markers:    []string{"jimdocdn.com", "jimstatic.com"},
+	},
+	{
+		cms:        model.CMSStudio,
+		generators: []string{"studio"},
+		markers:    []string{"studio.design", "studio-design-asset"},
+	},
+	{
+		cms:        model.CMSAmebaOwnd,
+		generators: []string{"ameba ownd"},
+		markers:    []string{"amebaownd.com", "amebaowndme.com"},
+	},
+	{
+		cms:        model.CMSWordPress,
+		generators: []string{"wordpress"},
+		markers:    []string{"/wp-content/", "/wp-includes/", "wp-json"},
+	},
+}
+
+// FingerprintSite トップページのヘッダーとHTMLからCMS・ビルダーを判定する
+// どのCMSの手がかりもない場合は static、generatorはあるが知らないものは other とし、generatorをバージョンの手がかりにする
+func FingerprintSite(header http.Header, html string) SiteFingerprint {
+	generator := pageGenerator(html)
+	lowerGenerator := strings.ToLower(generator)
+	lowerHTML := strings.ToLower(html)
+
+	for _, rule := range cmsRules {
+		if matchCMSRule(rule, header, lowerGenerator, lowerHTML) {
+			return SiteFingerprint{CMS: rule.cms, Version: cmsVersion(rule, generator)}
+		}
+	}
+	// WordPressはHTMLを書き換えていてもヘッダーに手がかりが残ることがある
+	if strings.Contains(header.Get("Link"), "api.w.org") || header.Get("X-Pingback") != "" {
+		return SiteFingerprint{CMS: model.CMSWordPress}
+	}
+	if generator != "" {
+		return SiteFingerprint{CMS: model.CMSOther, Version: truncateRunes(generator, 64)}
+	}
+	return SiteFingerprint{CMS: model.CMSStatic}
+}
+
+func matchCMSRule(rule cmsRule, header http.Header, lowerGenerator, lowerHTML string) bool {
+	for _, h := range rule.headers {
+		if header.Get(h) != "" {
+			return true
+		}
+	}
+	for _, g := range rule.generators {
+		if strings.HasPrefix(lowerGenerator, g) {
+			return true
+		}
+	}
+	for _, m := range rule.markers {
+		if strings.Contains(lowerHTML, m) {
+			return true
+		}
+	}
+	return false
+}
+
+// pageGenerator <meta name="generator"> の内容。ない場合は空文字
+func pageGenerator(html string) string {
+	tag := metaGenerator.FindString(html)
+	if tag == "" {
+		return ""
+	}
+	m := metaContent.FindStringSubmatch(tag)
+	if len(m) < 2 {
+		return ""
+	}
+	return strings.TrimSpace(m[1])
+}
+
+// cmsVersion generatorに含まれるバージョン番号。generatorが別のCMSのものや番号がない場合は空文字
+func cmsVersion(rule cmsRule, generator string) string {
+	lower := strings.ToLower(generator)
+	for _, g := range rule.generators {
+		if strings.HasPrefix(lower, g) {
+			return versionNumber.FindString(generator)
+		}
+	}
+	return ""
+}
+
+func truncateRunes(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n])
+}
@@ -0,0 +1,75 @@
+package entity
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zuxt268/sales/internal/model"
+)
+
+func TestFingerprintSite(t *testing.T) {
+	tests := []struct {
+		name   string
+		header http.Header
+		html   string
+		want   SiteFingerprint
+	}{
+		{
+			name: "wordpress with generator",
+			html: `<head><meta name="generator" content="WordPress 6.4.2" /><link rel="stylesheet" href="/wp-content/themes/a/style.css"></head>`,
+			want: SiteFingerprint{CMS: model.CMSWordPress, Version: "6.4.2"},
+		},
+		{
+			name:   "wordpress from headers only",
+			header: http.Header{"Link": {`<https://example.jp/wp-json/>; rel="https://api.w.org/"`}},
+			html:   `<html><body>hello</body></html>`,
+			want:   SiteFingerprint{CMS: model.CMSWordPress},
+		},
+		{
+			name: "wix",
+			html: `<meta name="generator" content="Wix.com Website Builder"/><img src="https://static.wixstatic.com/media/a.jpg">`,
+			want: SiteFingerprint{CMS: model.CMSWix},
+		},
+		{
+			name:   "shopify by header",
+			header: http.Header{"X-Shopid": {"12345"}},
+			html:   `<html></html>`,
+			want:   SiteFingerprint{CMS: model.CMSShopify},
+		},
+		{
+			name: "jimdo",
+			html: `<link href="https://assets.jimstatic.com/web.css" rel="stylesheet">`,
+			want: SiteFingerprint{CMS: model.CMSJimdo},
+		},
+		{
+			name: "studio",
+			html: `<meta name="generator" content="STUDIO"><img src="https://storage.googleapis.com/studio-design-asset-files/a.png">`,
+			want: SiteFingerprint{CMS: model.CMSStudio},
+		},
+		{
+			name: "ameba ownd",
+			html: `<script src="https://cdn.amebaowndme.com/madrid-prd/js/app.js"></script>`,
+			want: SiteFingerprint{CMS: model.CMSAmebaOwnd},
+		},
+		{
+			name: "unknown generator",
+			html: `<meta content="Hugo 0.120.4" name="generator">`,
+			want: SiteFingerprint{CMS: model.CMSOther, Version: "Hugo 0.120.4"},
+		},
+		{
+			name: "static",
+			html: `<html><head><title>会社概要</title></head><body>ようこそ</body></html>`,
+			want: SiteFingerprint{CMS: model.CMSStatic},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := tt.header
+			if header == nil {
+				header = http.Header{}
+			}
+			assert.Equal(t, tt.want, FingerprintSite(header, tt.html))
+		})
+	}
+}
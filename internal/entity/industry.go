@@ -0,0 +1,246 @@
+package entity
+
+import (
+	"fmt"
+	"strings"
+)
+
+// IndustryMajor 日本標準産業分類の大分類と、含まれる中分類コードの範囲
+type IndustryMajor struct {
+	Code string
+	Name string
+	From int
+	To   int
+}
+
+// IndustryMajors 日本標準産業分類の大分類
+var IndustryMajors = []IndustryMajor{
+	{"A", "農業，林業", 1, 2},
+	{"B", "漁業", 3, 4},
+	{"C", "鉱業，採石業，砂利採取業", 5, 5},
+	{"D", "建設業", 6, 8},
+	{"E", "製造業", 9, 32},
+	{"F", "電気・ガス・熱供給・水道業", 33, 36},
+	{"G", "情報通信業", 37, 41},
+	{"H", "運輸業，郵便業", 42, 49},
+	{"I", "卸売業，小売業", 50, 61},
+	{"J", "金融業，保険業", 62, 67},
+	{"K", "不動産業，物品賃貸業", 68, 70},
+	{"L", "学術研究，専門・技術サービス業", 71, 74},
+	{"M", "宿泊業，飲食サービス業", 75, 77},
+	{"N", "生活関連サービス業，娯楽業", 78, 80},
+	{"O", "教育，学習支援業", 81, 82},
+	{"P", "医療，福祉", 83, 85},
+	{"Q", "複合サービス事業", 86, 87},
+	{"R", "サービス業（他に分類されないもの）", 88, 96},
+	{"S", "公務（他に分類されるものを除く）", 97, 98},
+	{"T", "分類不能の産業", 99, 99},
+}
+
+// Industries 業種リスト（日本標準産業分類の中分類）
+// 並び順は中分類コードの順で、i番目の業種のコードはi+1を2桁にしたもの
+var Industries = []string{
+	"農業",
+	"林業",
+	"漁業（水産養殖業を除く）",
+	"水産養殖業",
+	"鉱業，採石業，砂利採取業",
+	"総合工事業",
+	"職別工事業(設備工事業を除く)",
+	"設備工事業",
+	"食料品製造業",
+	"飲料・たばこ・飼料製造業",
+	"繊維工業",
+	"木材・木製品製造業（家具を除く）",
+	"家具・装備品製造業",
+	"パルプ・紙・紙加工品製造業",
+	"印刷・同関連業",
+	"化学工業",
+	"石油製品・石炭製品製造業",
+	"プラスチック製品製造業（別掲を除く）",
+	"ゴム製品製造業",
+	"なめし革・同製品・毛皮製造業",
+	"窯業・土石製品製造業",
+	"鉄鋼業",
+	"非鉄金属製造業",
+	"金属製品製造業",
+	"はん用機械器具製造業",
+	"生産用機械器具製造業",
+	"業務用機械器具製造業",
+	"電子部品・デバイス・電子回路製造業",
+	"電気機械器具製造業",
+	"情報通信機械器具製造業",
+	"輸送用機械器具製造業",
+	"その他の製造業",
+	"電気業",
+	"ガス業",
+	"熱供給業",
+	"水道業",
+	"通信業",
+	"放送業",
+	"情報サービス業",
+	"インターネット附随サービス業",
+	"映像・音声・文字情報制作業",
+	"鉄道業",
+	"道路旅客運送業",
+	"道路貨物運送業",
+	"水運業",
+	"航空運輸業",
+	"倉庫業",
+	"運輸に附帯するサービス業",
+	"郵便業（信書便事業を含む）",
+	"各種商品卸売業",
+	"繊維・衣服等卸売業",
+	"飲食料品卸売業",
+	"建築材料，鉱物・金属材料等卸売業",
+	"機械器具卸売業",
+	"その他の卸売業",
+	"各種商品小売業",
+	"織物・衣服・身の回り品小売業",
+	"飲食料品小売業",
+	"機械器具小売業",
+	"その他の小売業",
+	"無店舗小売業",
+	"銀行業",
+	"協同組織金融業",
+	"貸金業，クレジットカード業等非預金信用機関",
+	"金融商品取引業，商品先物取引業",
+	"補助的金融業等",
+	"保険業（保険媒介代理業，保険サービス業を含む）",
+	"不動産取引業",
+	"不動産賃貸業・管理業",
+	"物品賃貸業",
+	"学術・開発研究機関",
+	"専門サービス業（他に分類されないもの）",
+	"広告業",
+	"技術サービス業（他に分類されないもの）",
+	"宿泊業",
+	"飲食店",
+	"持ち帰り・配達飲食サービス業",
+	"洗濯・理容・美容・浴場業",
+	"その他の生活関連サービス業",
+	"娯楽業",
+	"学校教育",
+	"その他の教育，学習支援業",
+	"医療業",
+	"保健衛生",
+	"社会保険・社会福祉・介護事業",
+	"郵便局",
+	"協同組合（他に分類されないもの）",
+	"廃棄物処理業",
+	"自動車整備業",
+	"機械等修理業（別掲を除く）",
+	"職業紹介・労働者派遣業",
+	"その他の事業サービス業",
+	"政治・経済・文化団体",
+	"宗教",
+	"その他のサービス業",
+	"外国公務",
+	"国家公務",
+	"地方公務",
+	"分類不能の産業",
+}
+
+// IsIndustry 業種リストのいずれかかどうか
+func IsIndustry(s string) bool {
+	return IndustryCode(s) != ""
+}
+
+// IndustryCode 業種名から中分類コードを返す。見つからない場合は空文字
+func IndustryCode(name string) string {
+	for i, n := range Industries {
+		if n == name {
+			return fmt.Sprintf("%02d", i+1)
+		}
+	}
+	return ""
+}
+
+// IndustryName 中分類コードから業種名を返す。見つからない場合は空文字
+func IndustryName(code string) string {
+	var n int
+	if _, err := fmt.Sscanf(code, "%02d", &n); err != nil || len(code) != 2 || n < 1 || n > len(Industries) {
+		return ""
+	}
+	return Industries[n-1]
+}
+
+// IndustryMajorName 大分類コードから名称を返す。見つからない場合は空文字
+func IndustryMajorName(code string) string {
+	for _, m := range IndustryMajors {
+		if m.Code == code {
+			return m.Name
+		}
+	}
+	return ""
+}
+
+// IndustryMajorCode 中分類コードが属する大分類コードを返す。見つからない場合は空文字
+func IndustryMajorCode(code string) string {
+	if IndustryName(code) == "" {
+		return ""
+	}
+	var n int
+	_, _ = fmt.Sscanf(code, "%02d", &n)
+	for _, m := range IndustryMajors {
+		if n >= m.From && n <= m.To {
+			return m.Code
+		}
+	}
+	return ""
+}
+
+// 業種名を比較するときに無視する記号
+var industryNameReplacer = strings.NewReplacer(
+	" ", "", "　", "", "(", "（", ")", "）", ",", "，", "「", "", "」", "",
+)
+
+// NormalizeIndustries GPTが返した業種（カンマ・読点・改行区切り）を中分類コードに変換する
+// 業種リストにないものは除外し、重複を除いて出現順に返す
+func NormalizeIndustries(s string) []string {
+	fields := strings.FieldsFunc(s, func(r rune) bool {
+		return r == ',' || r == '、' || r == '\n' || r == '/' || r == '／'
+	})
+	codes := make([]string, 0, len(fields))
+	seen := make(map[string]struct{})
+	for _, f := range fields {
+		code := matchIndustry(strings.TrimSpace(f))
+		if code == "" {
+			continue
+		}
+		if _, ok := seen[code]; ok {
+			continue
+		}
+		seen[code] = struct{}{}
+		codes = append(codes, code)
+	}
+	return codes
+}
+
+// matchIndustry 完全一致を優先し、なければ空白や括弧の表記揺れを無視して比較する
+func matchIndustry(name string) string {
+	if code := IndustryCode(name); code != "" {
+		return code
+	}
+	key := industryNameReplacer.Replace(name)
+	if key == "" {
+		return ""
+	}
+	for i, n := range Industries {
+		if industryNameReplacer.Replace(n) == key {
+			return fmt.Sprintf("%02d", i+1)
+		}
+	}
+	return ""
+}
+
+// IndustryNames 中分類コードを業種名に変換してカンマ区切りにする
+func IndustryNames(codes []string) string {
+	names := make([]string, 0, len(codes))
+	for _, c := range codes {
+		if n := IndustryName(c); n != "" {
+			names = append(names, n)
+		}
+	}
+	return strings.Join(names, ",")
+}
@@ -0,0 +1,73 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIndustryCode(t *testing.T) {
+	assert.Len(t, Industries, 99)
+	assert.Equal(t, "01", IndustryCode("農業"))
+	assert.Equal(t, "73", IndustryCode("広告業"))
+	assert.Equal(t, "89", IndustryCode("自動車整備業"))
+	assert.Equal(t, "99", IndustryCode("分類不能の産業"))
+	assert.Equal(t, "", IndustryCode("自動車"))
+}
+
+func TestIndustryName(t *testing.T) {
+	assert.Equal(t, "農業", IndustryName("01"))
+	assert.Equal(t, "飲食店", IndustryName("76"))
+	assert.Equal(t, "", IndustryName("00"))
+	assert.Equal(t, "", IndustryName("100"))
+	assert.Equal(t, "", IndustryName("1"))
+	assert.Equal(t, "", IndustryName("A"))
+}
+
+func TestIndustryMajorName(t *testing.T) {
+	assert.Equal(t, "宿泊業，飲食サービス業", IndustryMajorName("M"))
+	assert.Equal(t, "", IndustryMajorName("U"))
+	assert.Equal(t, "", IndustryMajorName("01"))
+}
+
+func TestIndustryMajorCode(t *testing.T) {
+	assert.Equal(t, "A", IndustryMajorCode("01"))
+	assert.Equal(t, "E", IndustryMajorCode("09"))
+	assert.Equal(t, "E", IndustryMajorCode("32"))
+	assert.Equal(t, "M", IndustryMajorCode("76"))
+	assert.Equal(t, "R", IndustryMajorCode("89"))
+	assert.Equal(t, "T", IndustryMajorCode("99"))
+	assert.Equal(t, "", IndustryMajorCode("00"))
+
+	// 大分類の範囲が中分類を漏れなく覆っていること
+	for i := range Industries {
+		code := IndustryCode(Industries[i])
+		assert.NotEmpty(t, IndustryMajorCode(code), code)
+	}
+}
+
+func TestNormalizeIndustries(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want []string
+	}{
+		{"single", "広告業", []string{"73"}},
+		{"comma separated", "自動車整備業,広告業", []string{"89", "73"}},
+		{"japanese comma and spaces", "飲食店、 宿泊業 ", []string{"76", "75"}},
+		{"half width parentheses", "漁業(水産養殖業を除く)", []string{"03"}},
+		{"duplicates", "広告業,広告業", []string{"73"}},
+		{"unknown is dropped", "広告業,宇宙旅行業", []string{"73"}},
+		{"empty", "", []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, NormalizeIndustries(tt.in))
+		})
+	}
+}
+
+func TestIndustryNames(t *testing.T) {
+	assert.Equal(t, "自動車整備業,広告業", IndustryNames([]string{"89", "73"}))
+	assert.Equal(t, "", IndustryNames([]string{"00"}))
+}
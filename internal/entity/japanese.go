@@ -0,0 +1,94 @@
+package entity
+
+import (
+	"html"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// japaneseScoreThreshold この点数以上のページを日本のサイトとみなす
+const japaneseScoreThreshold = 3
+
+var (
+	langJa          = regexp.MustCompile(`(?i)<html[^>]*\slang=["']?ja\b`)
+	contentLangJa   = regexp.MustCompile(`(?i)<meta[^>]*(?:http-equiv=["']content-language["'][^>]*content=["']ja\b|property=["']og:locale["'][^>]*content=["']ja_JP\b)`)
+	invisibleBlocks = regexp.MustCompile(`(?is)<script\b.*?</script>|<style\b.*?</style>|<noscript\b.*?</noscript>`)
+	htmlTag         = regexp.MustCompile(`(?s)<[^>]*>`)
+)
+
+// JapaneseScore ページが日本のサイトかの判定の内訳
+type JapaneseScore struct {
+	ScriptRatio float64 // 本文の文字のうち、かな・漢字の割合（かながない場合は0）
+	Lang        bool    // html の lang や og:locale が日本語
+	JPDomain    bool    // ドメインが .jp
+	Phone       bool    // 日本の電話番号がある
+	PostalCode  bool    // 郵便番号がある
+	Address     bool    // 都道府県名で始まる住所がある
+	Score       int
+}
+
+// IsJapanese 日本のサイトとみなせるか
+func (s JapaneseScore) IsJapanese() bool {
+	return s.Score >= japaneseScoreThreshold
+}
+
+// ScoreJapanesePage ページのHTMLとドメイン名から日本のサイトらしさを点数にする
+// 本文が日本語（かなを含み、かな・漢字が3割以上）か lang が ja なら3点、
+// 本文に少しでも日本語があれば1点、.jp・電話番号・郵便番号・住所はそれぞれ1点
+func ScoreJapanesePage(domain, page string) JapaneseScore {
+	text := pageText(page)
+	s := JapaneseScore{
+		ScriptRatio: japaneseScriptRatio(text),
+		Lang:        langJa.MatchString(page) || contentLangJa.MatchString(page),
+		JPDomain:    strings.HasSuffix(strings.ToLower(strings.TrimSuffix(domain, ".")), ".jp"),
+		Phone:       len(ExtractPhones(text)) > 0,
+		PostalCode:  len(ExtractPostalCodes(text)) > 0,
+		Address:     len(ExtractAddresses(text)) > 0,
+	}
+	switch {
+	case s.Lang || s.ScriptRatio >= 0.3:
+		s.Score += 3
+	case s.ScriptRatio >= 0.05:
+		s.Score++
+	}
+	for _, ok := range []bool{s.JPDomain, s.Phone, s.PostalCode, s.Address} {
+		if ok {
+			s.Score++
+		}
+	}
+	return s
+}
+
+// IsJapanesePage ページのHTMLが日本語のサイトのものか。ドメイン名がわかる場合はScoreJapanesePageを使う
+func IsJapanesePage(page string) bool {
+	return ScoreJapanesePage("", page).IsJapanese()
+}
+
+// pageText HTMLから画面に表示されるテキストを取り出す
+func pageText(page string) string {
+	page = invisibleBlocks.ReplaceAllString(page, " ")
+	page = htmlTag.ReplaceAllString(page, " ")
+	return html.UnescapeString(page)
+}
+
+// japaneseScriptRatio 文字のうち、かな・漢字の割合
+// 漢字だけの中国語のページを除くため、かなが漢字とかなの合計の1割未満の場合は0
+func japaneseScriptRatio(text string) float64 {
+	var letters, kana, han int
+	for _, r := range text {
+		switch {
+		case unicode.In(r, unicode.Hiragana, unicode.Katakana):
+			kana++
+		case unicode.Is(unicode.Han, r):
+			han++
+		}
+		if unicode.IsLetter(r) {
+			letters++
+		}
+	}
+	if letters == 0 || kana == 0 || kana*10 < kana+han {
+		return 0
+	}
+	return float64(kana+han) / float64(letters)
+}
@@ -0,0 +1,52 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsJapanesePage(t *testing.T) {
+	assert.True(t, IsJapanesePage(`<html><body>東京のカフェです</body></html>`))
+	assert.True(t, IsJapanesePage(`<html lang="ja-JP"><body>Cafe</body></html>`))
+	assert.True(t, IsJapanesePage(`<html><head><meta property="og:locale" content="ja_JP"></head><body>Cafe</body></html>`))
+	assert.False(t, IsJapanesePage(`<html lang="en"><body>Welcome to our cafe</body></html>`))
+	// 漢字だけでは日本語とみなさない
+	assert.False(t, IsJapanesePage(`<html><body>欢迎光临</body></html>`))
+	// scriptの中の日本語は本文として数えない
+	assert.False(t, IsJapanesePage(`<html><body>Welcome<script>var msg = "ようこそ日本へ";</script></body></html>`))
+}
+
+func TestScoreJapanesePage(t *testing.T) {
+	page := `<html lang="en"><body>
+<h1>Tanaka Trading Co., Ltd.</h1>
+<p>〒150-0001 東京都渋谷区神宮前1-2-3</p>
+<p>TEL 03-1234-5678</p>
+</body></html>`
+	s := ScoreJapanesePage("tanaka-trading.co.jp", page)
+	assert.False(t, s.Lang)
+	assert.True(t, s.JPDomain)
+	assert.True(t, s.Phone)
+	assert.True(t, s.PostalCode)
+	assert.True(t, s.Address)
+	assert.Equal(t, 4, s.Score)
+	// 英語のページでも日本の連絡先があれば日本のサイトとみなす
+	assert.True(t, s.IsJapanese())
+
+	s = ScoreJapanesePage("example.jp", `<html lang="en"><body>Welcome to our shop</body></html>`)
+	assert.Equal(t, 1, s.Score)
+	assert.False(t, s.IsJapanese())
+
+	// 英語が中心で日本語が少しだけのページは本文の点数が低い
+	s = ScoreJapanesePage("example.com", `<html><body>`+
+		`We are a design studio working with clients around the world on branding and websites. `+
+		`こんにちは</body></html>`)
+	assert.Greater(t, s.ScriptRatio, 0.05)
+	assert.Less(t, s.ScriptRatio, 0.3)
+	assert.Equal(t, 1, s.Score)
+	assert.False(t, s.IsJapanese())
+
+	s = ScoreJapanesePage("example.com", `<html><body><p>私たちは大阪の小さなパン屋です。毎朝焼きたてのパンをお届けします。</p></body></html>`)
+	assert.GreaterOrEqual(t, s.ScriptRatio, 0.3)
+	assert.True(t, s.IsJapanese())
+}
@@ -0,0 +1,17 @@
+package entity
+
+// LLMCost トークン数と100万トークンあたりの単価から費用を計算する
+func LLMCost(promptTokens, completionTokens int, inputPricePerMTok, outputPricePerMTok float64) float64 {
+	return (float64(promptTokens)*inputPricePerMTok + float64(completionTokens)*outputPricePerMTok) / 1_000_000
+}
+
+// ExceedsLLMBudget 当日の使用量が予算に達しているかどうか。予算が0以下の項目は無制限とみなす
+func ExceedsLLMBudget(tokens int, cost float64, tokenBudget int, costBudget float64) bool {
+	if tokenBudget > 0 && tokens >= tokenBudget {
+		return true
+	}
+	if costBudget > 0 && cost >= costBudget {
+		return true
+	}
+	return false
+}
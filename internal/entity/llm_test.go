@@ -0,0 +1,36 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLLMCost(t *testing.T) {
+	assert.InDelta(t, 0.45, LLMCost(1_000_000, 1_000_000, 0.05, 0.4), 1e-9)
+	assert.InDelta(t, 0.00009, LLMCost(1000, 100, 0.05, 0.4), 1e-12)
+	assert.Equal(t, 0.0, LLMCost(0, 0, 0.05, 0.4))
+}
+
+func TestExceedsLLMBudget(t *testing.T) {
+	tests := []struct {
+		name        string
+		tokens      int
+		cost        float64
+		tokenBudget int
+		costBudget  float64
+		want        bool
+	}{
+		{"unlimited", 1_000_000, 100, 0, 0, false},
+		{"under token budget", 999, 0, 1000, 0, false},
+		{"reached token budget", 1000, 0, 1000, 0, true},
+		{"under cost budget", 0, 0.9, 0, 1, false},
+		{"reached cost budget", 0, 1, 0, 1, true},
+		{"cost exceeded with token budget left", 10, 2, 1000, 1, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ExceedsLLMBudget(tt.tokens, tt.cost, tt.tokenBudget, tt.costBudget))
+		})
+	}
+}
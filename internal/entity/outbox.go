@@ -0,0 +1,24 @@
+package entity
+
+import "time"
+
+const (
+	outboxBaseBackoff = 30 * time.Second
+	outboxMaxBackoff  = time.Hour
+)
+
+// OutboxBackoff attempts回目の送信に失敗した後、次に送信を試みるまでの待ち時間
+// 30秒から倍々に延ばし、1時間で頭打ちにする
+func OutboxBackoff(attempts int) time.Duration {
+	if attempts < 1 {
+		return 0
+	}
+	d := outboxBaseBackoff
+	for i := 1; i < attempts; i++ {
+		d *= 2
+		if d >= outboxMaxBackoff {
+			return outboxMaxBackoff
+		}
+	}
+	return d
+}
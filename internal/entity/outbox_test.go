@@ -0,0 +1,17 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOutboxBackoff(t *testing.T) {
+	assert.Equal(t, time.Duration(0), OutboxBackoff(0))
+	assert.Equal(t, 30*time.Second, OutboxBackoff(1))
+	assert.Equal(t, time.Minute, OutboxBackoff(2))
+	assert.Equal(t, 4*time.Minute, OutboxBackoff(4))
+	assert.Equal(t, time.Hour, OutboxBackoff(8))
+	assert.Equal(t, time.Hour, OutboxBackoff(100))
+}
@@ -0,0 +1,165 @@
+package entity
+
+import (
+	"bufio"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sort"
+	"strings"
+)
+
+// パッシブDNSのCSVで、ドメイン名・IPアドレス・レコード種別として読む列名
+var (
+	passiveDNSNameColumns  = []string{"rrname", "name", "domain", "query", "hostname"}
+	passiveDNSValueColumns = []string{"rdata", "ip", "value", "answer", "address"}
+	passiveDNSTypeColumns  = []string{"rrtype", "type", "record_type"}
+)
+
+// ParsePassiveDNSCSV パッシブDNSのCSVから、ipを指すドメインを取り出す
+// 1行目は列名で、ドメイン名とIPアドレスの列が必要。レコード種別の列があればA・AAAAだけを読む
+func ParsePassiveDNSCSV(r io.Reader, ip string) ([]string, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+
+	header, err := cr.Read()
+	if errors.Is(err, io.EOF) {
+		return []string{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read csv header: %v: %w", err, ErrValidation)
+	}
+	nameCol := csvColumn(header, passiveDNSNameColumns)
+	valueCol := csvColumn(header, passiveDNSValueColumns)
+	typeCol := csvColumn(header, passiveDNSTypeColumns)
+	if nameCol < 0 || valueCol < 0 {
+		return nil, fmt.Errorf("csv must have domain name and ip columns: %v: %w", header, ErrValidation)
+	}
+
+	names := newDomainSet()
+	for {
+		rec, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read csv: %v: %w", err, ErrValidation)
+		}
+		if nameCol >= len(rec) || valueCol >= len(rec) {
+			continue
+		}
+		if typeCol >= 0 && typeCol < len(rec) && !isAddressRecord(rec[typeCol]) {
+			continue
+		}
+		if sameIP(rec[valueCol], ip) {
+			names.add(rec[nameCol])
+		}
+	}
+	return names.sorted(), nil
+}
+
+// ParseZoneFile ゾーンファイルのA・AAAAレコードから、ipを指すドメインを取り出す
+// $ORIGIN と @、相対名、所有者名の省略（直前のレコードと同じ）に対応する
+func ParseZoneFile(r io.Reader, ip string) ([]string, error) {
+	names := newDomainSet()
+	origin, owner := "", ""
+
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 64*1024), 1024*1024)
+	for sc.Scan() {
+		line := sc.Text()
+		if i := strings.Index(line, ";"); i >= 0 {
+			line = line[:i]
+		}
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		fields := strings.Fields(line)
+		if strings.EqualFold(fields[0], "$ORIGIN") && len(fields) > 1 {
+			origin = strings.TrimSuffix(fields[1], ".")
+			continue
+		}
+		if strings.HasPrefix(fields[0], "$") {
+			continue
+		}
+		// 行頭が空白の場合は直前のレコードと同じ所有者名
+		if line[0] != ' ' && line[0] != '\t' {
+			owner = absoluteName(fields[0], origin)
+			fields = fields[1:]
+		}
+
+		// TTLとクラスは省略・順不同のため、A・AAAAの位置を探す
+		for i, f := range fields {
+			if isAddressRecord(f) {
+				if i+1 < len(fields) && sameIP(fields[i+1], ip) {
+					names.add(owner)
+				}
+				break
+			}
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read zone file: %v: %w", err, ErrValidation)
+	}
+	return names.sorted(), nil
+}
+
+func csvColumn(header []string, candidates []string) int {
+	for i, h := range header {
+		// Excelで保存したCSVの先頭にはBOMが付く
+		h = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(h, "\ufeff")))
+		for _, c := range candidates {
+			if h == c {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+func isAddressRecord(t string) bool {
+	t = strings.ToUpper(strings.TrimSpace(t))
+	return t == "A" || t == "AAAA"
+}
+
+// sameIP IPv6の省略表記の違いも同じアドレスとみなす
+func sameIP(a, b string) bool {
+	ipA, ipB := net.ParseIP(strings.TrimSpace(a)), net.ParseIP(strings.TrimSpace(b))
+	return ipA != nil && ipB != nil && ipA.Equal(ipB)
+}
+
+func absoluteName(name, origin string) string {
+	if name == "@" {
+		return origin
+	}
+	if strings.HasSuffix(name, ".") || origin == "" {
+		return name
+	}
+	return name + "." + origin
+}
+
+// domainSet 小文字にして末尾のドットを取り、重複を除いたドメイン名
+type domainSet map[string]struct{}
+
+func newDomainSet() domainSet {
+	return make(domainSet)
+}
+
+func (s domainSet) add(name string) {
+	name = strings.ToLower(strings.TrimSuffix(strings.TrimSpace(name), "."))
+	if name != "" {
+		s[name] = struct{}{}
+	}
+}
+
+func (s domainSet) sorted() []string {
+	names := make([]string, 0, len(s))
+	for n := range s {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
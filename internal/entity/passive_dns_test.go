@@ -0,0 +1,87 @@
+package entity
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParsePassiveDNSCSV(t *testing.T) {
+	tests := []struct {
+		name    string
+		csv     string
+		ip      string
+		want    []string
+		wantErr bool
+	}{
+		{
+			name: "filters by ip and record type",
+			csv: "rrname,rrtype,rdata,time_first\n" +
+				"Example.com.,A,192.0.2.1,2026-01-01\n" +
+				"www.example.com,A,192.0.2.1,2026-01-01\n" +
+				"other.com,A,192.0.2.2,2026-01-01\n" +
+				"mail.example.com,MX,192.0.2.1,2026-01-01\n" +
+				"example.com,A,192.0.2.1,2026-02-01\n",
+			ip:   "192.0.2.1",
+			want: []string{"example.com", "www.example.com"},
+		},
+		{
+			name: "alternative column names without type",
+			csv:  "\ufeffDomain,IP\nexample.jp,2001:db8::1\nexample.net,2001:db8:0:0::2\n",
+			ip:   "2001:db8:0::1",
+			want: []string{"example.jp"},
+		},
+		{
+			name: "short rows are skipped",
+			csv:  "name,ip\nexample.com\nexample.org,192.0.2.1\n",
+			ip:   "192.0.2.1",
+			want: []string{"example.org"},
+		},
+		{
+			name: "empty",
+			csv:  "",
+			ip:   "192.0.2.1",
+			want: []string{},
+		},
+		{
+			name:    "missing columns",
+			csv:     "foo,bar\nexample.com,192.0.2.1\n",
+			ip:      "192.0.2.1",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParsePassiveDNSCSV(strings.NewReader(tt.csv), tt.ip)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrValidation)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParseZoneFile(t *testing.T) {
+	zone := `$ORIGIN example.com.
+$TTL 3600
+@        IN SOA ns1.example.com. admin.example.com. ( 1 7200 3600 1209600 3600 )
+@        IN A     192.0.2.1 ; apex
+www      3600 IN A 192.0.2.1
+         IN AAAA  2001:db8::1
+mail     IN A     192.0.2.2
+shop.example.net. A 192.0.2.1
+api      IN CNAME www
+$ORIGIN example.org.
+blog     IN A     192.0.2.1
+`
+	got, err := ParseZoneFile(strings.NewReader(zone), "192.0.2.1")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"blog.example.org", "example.com", "shop.example.net", "www.example.com"}, got)
+
+	got, err = ParseZoneFile(strings.NewReader(zone), "2001:db8::1")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"www.example.com"}, got)
+}
@@ -1,7 +1,155 @@
 package entity
 
-import "strings"
+import (
+	"regexp"
+	"strings"
+)
 
+type PhoneType string
+
+const (
+	PhoneTypeUnknown  PhoneType = "unknown"
+	PhoneTypeMobile   PhoneType = "mobile"
+	PhoneTypeIP       PhoneType = "ip"
+	PhoneTypeTollFree PhoneType = "toll_free"
+	PhoneTypeLandline PhoneType = "landline"
+)
+
+// 全角数字・全角記号・各種ハイフンを半角に揃える
+var digitReplacer = strings.NewReplacer(
+	"０", "0", "１", "1", "２", "2", "３", "3", "４", "4",
+	"５", "5", "６", "6", "７", "7", "８", "8", "９", "9",
+	"－", "-", "‐", "-", "‑", "-", "‒", "-", "–", "-", "—", "-", "―", "-", "−", "-", "─", "-",
+	"（", "(", "）", ")", "＋", "+", "　", " ",
+)
+
+// 長音記号は数字に挟まれている場合だけハイフンとみなす
+var longVowelBetweenDigits = regexp.MustCompile(`(\d)[ーｰ](\d)`)
+
+// NormalizeDigits 全角数字やハイフンの揺れを半角に揃える
+func NormalizeDigits(s string) string {
+	s = digitReplacer.Replace(s)
+	// 1ー2ー3 のように連続する場合に備えて2回置換する
+	s = longVowelBetweenDigits.ReplaceAllString(s, "$1-$2")
+	return longVowelBetweenDigits.ReplaceAllString(s, "$1-$2")
+}
+
+var phonePattern = regexp.MustCompile(`(?:\+81[\s-]?\(?0?\)?|\(?0)\d{1,4}(?:[\s.-]|\)\s?)?\d{1,4}(?:[\s.-]|\)\s?)?\d{3,4}`)
+
+// ExtractPhones テキストから日本の電話番号を抽出する
+// +81表記は0始まりに直し、区切りはハイフンに揃えて重複を除いて返す
+func ExtractPhones(text string) []string {
+	text = NormalizeDigits(text)
+	phones := make([]string, 0)
+	seen := make(map[string]struct{})
+	for _, loc := range phonePattern.FindAllStringIndex(text, -1) {
+		// 前後が数字に続いている場合は別の数字列の一部なので除外する
+		if loc[0] > 0 && isDigit(text[loc[0]-1]) {
+			continue
+		}
+		if loc[1] < len(text) && isDigit(text[loc[1]]) {
+			continue
+		}
+		// FAX番号は電話番号として扱わない
+		if strings.Contains(strings.ToUpper(text[max(0, loc[0]-8):loc[0]]), "FAX") {
+			continue
+		}
+		phone, ok := normalizePhone(text[loc[0]:loc[1]])
+		if !ok {
+			continue
+		}
+		if _, ok := seen[phone]; ok {
+			continue
+		}
+		seen[phone] = struct{}{}
+		phones = append(phones, phone)
+	}
+	return phones
+}
+
+func normalizePhone(raw string) (string, bool) {
+	raw = strings.TrimSpace(raw)
+	if strings.HasPrefix(raw, "+81") {
+		raw = strings.TrimPrefix(raw, "+81")
+		raw = strings.TrimLeft(raw, " -")
+		raw = strings.TrimPrefix(raw, "(0)")
+		raw = strings.TrimLeft(raw, " -")
+		raw = "0" + strings.TrimPrefix(raw, "0")
+	}
+	raw = strings.TrimPrefix(raw, "(")
+
+	var b strings.Builder
+	digits := 0
+	lastSep := false
+	for _, c := range raw {
+		switch {
+		case c >= '0' && c <= '9':
+			b.WriteRune(c)
+			digits++
+			lastSep = false
+		case c == '-' || c == ' ' || c == '.' || c == '(' || c == ')':
+			if !lastSep && b.Len() > 0 {
+				b.WriteRune('-')
+				lastSep = true
+			}
+		}
+	}
+	phone := strings.Trim(b.String(), "-")
+	if !strings.HasPrefix(phone, "0") || strings.HasPrefix(phone, "00") {
+		return "", false
+	}
+	switch ClassifyPhone(phone) {
+	case PhoneTypeMobile, PhoneTypeIP:
+		return phone, digits == 11
+	case PhoneTypeTollFree:
+		if strings.HasPrefix(phone, "0800") {
+			return phone, digits == 11
+		}
+		return phone, digits == 10
+	case PhoneTypeLandline:
+		return phone, digits == 10
+	}
+	return "", false
+}
+
+// ClassifyPhone 電話番号の種類を判定する
+func ClassifyPhone(phone string) PhoneType {
+	digits := onlyDigits(NormalizeDigits(phone))
+	if strings.HasPrefix(digits, "81") && len(digits) >= 11 {
+		digits = "0" + strings.TrimPrefix(digits[2:], "0")
+	}
+	switch {
+	case strings.HasPrefix(digits, "0120"), strings.HasPrefix(digits, "0800"):
+		return PhoneTypeTollFree
+	case strings.HasPrefix(digits, "070"), strings.HasPrefix(digits, "080"), strings.HasPrefix(digits, "090"):
+		return PhoneTypeMobile
+	case strings.HasPrefix(digits, "050"):
+		return PhoneTypeIP
+	case strings.HasPrefix(digits, "0") && !strings.HasPrefix(digits, "00"):
+		return PhoneTypeLandline
+	default:
+		return PhoneTypeUnknown
+	}
+}
+
+// JoinPhones カンマ区切りで結合する。maxLenを超える番号は含めない
+func JoinPhones(phones []string, maxLen int) string {
+	result := ""
+	for _, p := range phones {
+		next := p
+		if result != "" {
+			next = result + "," + p
+		}
+		if maxLen > 0 && len(next) > maxLen {
+			break
+		}
+		result = next
+	}
+	return result
+}
+
+// SplitPhone カンマ区切りの電話番号を携帯電話と固定電話に分ける
+// IP電話・フリーダイヤル・判定できない番号はどちらにも含めない（元の電話番号には残る）
 func SplitPhone(phoneNum string) (string, string) {
 	mobile := make([]string, 0)
 	landline := make([]string, 0)
@@ -10,9 +158,10 @@ func SplitPhone(phoneNum string) (string, string) {
 		if phone == "" {
 			continue
 		}
-		if strings.HasPrefix(phone, "080") || strings.HasPrefix(phone, "090") || strings.HasPrefix(phone, "070") {
+		switch ClassifyPhone(phone) {
+		case PhoneTypeMobile:
 			mobile = append(mobile, phone)
-		} else {
+		case PhoneTypeLandline:
 			landline = append(landline, phone)
 		}
 	}
@@ -20,3 +169,17 @@ func SplitPhone(phoneNum string) (string, string) {
 	landlinePhone := strings.Join(landline, ",")
 	return mobilePhone, landlinePhone
 }
+
+func onlyDigits(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if isDigit(s[i]) {
+			b.WriteByte(s[i])
+		}
+	}
+	return b.String()
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
@@ -0,0 +1,74 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExtractPhones(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{"ハイフン区切り", "TEL: 03-1234-5678", []string{"03-1234-5678"}},
+		{"全角数字", "ＴＥＬ　０３－１２３４－５６７８", []string{"03-1234-5678"}},
+		{"長音記号の区切り", "電話 06ー1234ー5678", []string{"06-1234-5678"}},
+		{"括弧区切り", "(03)1234-5678", []string{"03-1234-5678"}},
+		{"区切りなし", "0312345678", []string{"0312345678"}},
+		{"ドット区切り", "tel.092.123.4567", []string{"092-123-4567"}},
+		{"携帯", "携帯 090-1234-5678", []string{"090-1234-5678"}},
+		{"国際表記", "+81-3-1234-5678", []string{"03-1234-5678"}},
+		{"国際表記(0)", "+81 (0)90 1234 5678", []string{"090-1234-5678"}},
+		{"IP電話", "050-1234-5678", []string{"050-1234-5678"}},
+		{"フリーダイヤル", "0120-123-456", []string{"0120-123-456"}},
+		{"フリーダイヤル0800", "0800-123-4567", []string{"0800-123-4567"}},
+		{"FAXは除外", "TEL 03-1234-5678 FAX 03-1234-5679", []string{"03-1234-5678"}},
+		{"重複は除外", "03-1234-5678 / 03-1234-5678", []string{"03-1234-5678"}},
+		{"郵便番号は対象外", "〒100-0001 東京都千代田区", []string{}},
+		{"桁数が合わない", "03-1234-567", []string{}},
+		{"長い数字列の一部", "注文番号 1203-1234-5678", []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ExtractPhones(tt.text))
+		})
+	}
+}
+
+func TestClassifyPhone(t *testing.T) {
+	tests := []struct {
+		phone string
+		want  PhoneType
+	}{
+		{"090-1234-5678", PhoneTypeMobile},
+		{"080-1234-5678", PhoneTypeMobile},
+		{"070-1234-5678", PhoneTypeMobile},
+		{"+81-90-1234-5678", PhoneTypeMobile},
+		{"050-1234-5678", PhoneTypeIP},
+		{"0120-123-456", PhoneTypeTollFree},
+		{"0800-123-4567", PhoneTypeTollFree},
+		{"03-1234-5678", PhoneTypeLandline},
+		{"１２３", PhoneTypeUnknown},
+	}
+	for _, tt := range tests {
+		t.Run(tt.phone, func(t *testing.T) {
+			assert.Equal(t, tt.want, ClassifyPhone(tt.phone))
+		})
+	}
+}
+
+func TestSplitPhone(t *testing.T) {
+	mobile, landline := SplitPhone("090-1234-5678, 03-1234-5678,０８０－１２３４－５６７８,0120-123-456,0800-123-4567,050-1234-5678,123")
+	assert.Equal(t, "090-1234-5678,０８０－１２３４－５６７８", mobile)
+	// IP電話・フリーダイヤル・判定できない番号は固定電話に含めない
+	assert.Equal(t, "03-1234-5678", landline)
+}
+
+func TestJoinPhones(t *testing.T) {
+	phones := []string{"03-1234-5678", "090-1234-5678", "0120-123-456"}
+	assert.Equal(t, "03-1234-5678,090-1234-5678,0120-123-456", JoinPhones(phones, 0))
+	assert.Equal(t, "03-1234-5678,090-1234-5678", JoinPhones(phones, 30))
+	assert.Equal(t, "", JoinPhones(nil, 50))
+}
@@ -0,0 +1,124 @@
+package entity
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// parkedMarkers ドメインパーキング・売り出し中のページに含まれる文字列（小文字で比較する）
+var parkedMarkers = []string{
+	"this domain is for sale",
+	"this domain may be for sale",
+	"buy this domain",
+	"domain is parked",
+	"このドメインは購入できます",
+	"このドメインは売り出し中",
+	"このドメインはお名前.comで取得されています",
+	"このドメインはムームードメインで取得されています",
+	"このドメインはバリュードメインで取得されています",
+	"このドメインはエックスサーバーで取得されています",
+}
+
+// parkedHosts ドメインパーキング・売買サービスのホスト。サブドメインも含む
+// jordan.com などに一致しないよう、本文ではなくリンク先と転送先のホストと比べる
+var parkedHosts = []string{
+	"parkingcrew.net",
+	"sedoparking.com",
+	"bodis.com",
+	"dan.com",
+	"hugedomains.com",
+}
+
+var (
+	whitespace = regexp.MustCompile(`\s+`)
+	linkAttr   = regexp.MustCompile(`(?i)\s(?:href|src|action)\s*=\s*["']?([^"'\s>]+)`)
+)
+
+// IsParkedPage 取得したページがドメインパーキングや売り出し中のものか
+// finalURLはリダイレクトを辿った後のURLで、パーキングのサービスに転送された場合もパーキングとみなす
+func IsParkedPage(finalURL, html string) bool {
+	if isParkedHost(finalURL) {
+		return true
+	}
+	for _, m := range linkAttr.FindAllStringSubmatch(html, -1) {
+		if isParkedHost(m[1]) {
+			return true
+		}
+	}
+	text := strings.ToLower(whitespace.ReplaceAllString(html, ""))
+	for _, m := range parkedMarkers {
+		if strings.Contains(text, strings.ReplaceAll(m, " ", "")) {
+			return true
+		}
+	}
+	return false
+}
+
+// isParkedHost URLのホストがパーキングのサービスのものか
+func isParkedHost(rawURL string) bool {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return false
+	}
+	host := strings.ToLower(strings.TrimSuffix(u.Hostname(), "."))
+	if host == "" {
+		return false
+	}
+	for _, h := range parkedHosts {
+		if host == h || strings.HasSuffix(host, "."+h) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsRedirectedAway 取得したページのURLがドメインとは別のサイトか。wwwの有無は同じサイトとみなす
+func IsRedirectedAway(domain, finalURL string) bool {
+	u, err := url.Parse(finalURL)
+	if err != nil || u.Hostname() == "" {
+		return false
+	}
+	return trimWWW(u.Hostname()) != trimWWW(domain)
+}
+
+func trimWWW(host string) string {
+	return strings.TrimPrefix(strings.ToLower(strings.TrimSuffix(host, ".")), "www.")
+}
+
+// Sitemap sitemap.xmlの内容。URLsはページの数、Sitemapsはサイトマップインデックスが指す子のサイトマップ
+type Sitemap struct {
+	URLs     int
+	Sitemaps []string
+}
+
+type sitemapXML struct {
+	XMLName  xml.Name `xml:""`
+	URLs     []string `xml:"url>loc"`
+	Sitemaps []string `xml:"sitemap>loc"`
+}
+
+// ParseSitemap urlset・sitemapindexのどちらかのXMLを読む
+func ParseSitemap(data []byte) (*Sitemap, error) {
+	var s sitemapXML
+	if err := xml.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("invalid sitemap: %w", err)
+	}
+	switch s.XMLName.Local {
+	case "urlset", "sitemapindex":
+	default:
+		return nil, fmt.Errorf("invalid sitemap: unexpected root element %q", s.XMLName.Local)
+	}
+	sitemaps := make([]string, 0, len(s.Sitemaps))
+	for _, loc := range s.Sitemaps {
+		if loc = strings.TrimSpace(loc); loc != "" {
+			sitemaps = append(sitemaps, loc)
+		}
+	}
+	return &Sitemap{
+		URLs:     len(s.URLs),
+		Sitemaps: sitemaps,
+	}, nil
+}
@@ -0,0 +1,54 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsParkedPage(t *testing.T) {
+	const top = "https://example.jp/"
+	assert.True(t, IsParkedPage(top, `<html><body><h1>This Domain Is  For Sale</h1></body></html>`))
+	assert.True(t, IsParkedPage(top, `<html><body>このドメインは お名前.com で取得されています。</body></html>`))
+	assert.True(t, IsParkedPage(top, `<script src="//www.parkingcrew.net/js/park.js"></script>`))
+	assert.True(t, IsParkedPage(top, `<a href="https://dan.com/buy-domain/example.jp">Make an offer</a>`))
+	assert.True(t, IsParkedPage("https://www.hugedomains.com/domain_profile.cfm?d=example.jp", `<html></html>`))
+	assert.False(t, IsParkedPage(top, `<html><body>東京のカフェです</body></html>`))
+	// パーキングのサービスと末尾が同じだけのホストや、本文に出てくるだけのものは含めない
+	assert.False(t, IsParkedPage(top, `<a href="https://www.jordan.com">Jordan</a><a href="https://sedan.com/">Sedan</a>`))
+	assert.False(t, IsParkedPage("https://www.jordan.com/", `<html><body>Welcome</body></html>`))
+	assert.False(t, IsParkedPage(top, `<p>お問い合わせは info@dan.com.example.jp まで</p>`))
+}
+
+func TestIsRedirectedAway(t *testing.T) {
+	assert.False(t, IsRedirectedAway("example.jp", "https://example.jp/"))
+	assert.False(t, IsRedirectedAway("example.jp", "https://www.example.jp/top"))
+	assert.False(t, IsRedirectedAway("www.example.jp", "http://Example.jp/"))
+	assert.True(t, IsRedirectedAway("example.jp", "https://example.co.jp/"))
+	// 解釈できないURLはリダイレクトとみなさない
+	assert.False(t, IsRedirectedAway("example.jp", "://"))
+}
+
+func TestParseSitemap(t *testing.T) {
+	s, err := ParseSitemap([]byte(`<?xml version="1.0" encoding="UTF-8"?>
+<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+  <url><loc>https://example.jp/</loc></url>
+  <url><loc>https://example.jp/about</loc></url>
+</urlset>`))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, s.URLs)
+	assert.Empty(t, s.Sitemaps)
+
+	s, err = ParseSitemap([]byte(`<sitemapindex xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+  <sitemap><loc> https://example.jp/post-sitemap.xml </loc></sitemap>
+  <sitemap><loc>https://example.jp/page-sitemap.xml</loc></sitemap>
+</sitemapindex>`))
+	assert.NoError(t, err)
+	assert.Equal(t, 0, s.URLs)
+	assert.Equal(t, []string{"https://example.jp/post-sitemap.xml", "https://example.jp/page-sitemap.xml"}, s.Sitemaps)
+
+	_, err = ParseSitemap([]byte(`<html><body>Not Found</body></html>`))
+	assert.Error(t, err)
+	_, err = ParseSitemap([]byte(`not xml`))
+	assert.Error(t, err)
+}
@@ -0,0 +1,80 @@
+package entity
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/zuxt268/sales/internal/model"
+)
+
+// ErrInvalidStatusTransition 許可されていないステータス遷移
+var ErrInvalidStatusTransition = fmt.Errorf("invalid status transition: %w", ErrConflict)
+
+// domainTransitions ドメインのステータスごとに遷移できる先
+// 通常は initialize → check_view → check_japan → crawl_comp_info → pending_output → done の順に進む
+// Wixのドメインは check_view・check_japan の代わりに check_wix を通る
+// trash と unknown へはどのステータスからでも遷移でき、戻す場合は initialize からやり直す
+var domainTransitions = map[model.Status][]model.Status{
+	model.StatusUnknown:       {model.StatusInitialize},
+	model.StatusInitialize:    {model.StatusCheckView, model.StatusCheckWix},
+	model.StatusCheckView:     {model.StatusCheckJapan},
+	model.StatusCheckJapan:    {model.StatusCrawlCompInfo},
+	model.StatusCheckWix:      {model.StatusCrawlCompInfo},
+	model.StatusCrawlCompInfo: {model.StatusPendingOutput, model.StatusNeedsReview},
+	model.StatusNeedsReview:   {model.StatusPendingOutput, model.StatusCrawlCompInfo},
+	model.StatusPendingOutput: {model.StatusDone, model.StatusNeedsReview},
+	model.StatusDone:          {},
+	model.StatusTrash:         {model.StatusInitialize},
+}
+
+// CanTransition fromからtoへ遷移できるかどうか。同じステータスへの更新は遷移とみなさず許可する
+func CanTransition(from, to model.Status) bool {
+	if !model.IsValidStatus(from) || !model.IsValidStatus(to) {
+		return false
+	}
+	if from == to || to == model.StatusTrash || to == model.StatusUnknown {
+		return true
+	}
+	for _, next := range domainTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateTransition 遷移できない場合はErrInvalidStatusTransitionを返す
+func ValidateTransition(from, to model.Status) error {
+	if !model.IsValidStatus(to) {
+		return fmt.Errorf("unknown status %q: %w", to, ErrValidation)
+	}
+	if !CanTransition(from, to) {
+		return fmt.Errorf("%s -> %s: %w", from, to, ErrInvalidStatusTransition)
+	}
+	return nil
+}
+
+// StuckStatuses ワーカーの報告を待つステータス。報告がないまま時間が経つと再送の対象になる
+var StuckStatuses = []model.Status{
+	model.StatusCheckView,
+	model.StatusCheckJapan,
+	model.StatusCheckWix,
+}
+
+// FirstCheckStatus initialize の次に進むステータス。Wixのドメインは check_wix、それ以外は check_view
+func FirstCheckStatus(d *model.Domain) model.Status {
+	if d.Platform == model.PlatformWix {
+		return model.StatusCheckWix
+	}
+	return model.StatusCheckView
+}
+
+// IsStuck ワーカーの報告待ちのままafter以上経過しているかどうか
+func IsStuck(d *model.Domain, now time.Time, after time.Duration) bool {
+	for _, s := range StuckStatuses {
+		if d.Status == s {
+			return !d.StatusUpdatedAt.After(now.Add(-after))
+		}
+	}
+	return false
+}
@@ -0,0 +1,87 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zuxt268/sales/internal/model"
+)
+
+func TestCanTransition(t *testing.T) {
+	tests := []struct {
+		from model.Status
+		to   model.Status
+		want bool
+	}{
+		{model.StatusInitialize, model.StatusCheckView, true},
+		{model.StatusCheckView, model.StatusCheckJapan, true},
+		{model.StatusCheckJapan, model.StatusCrawlCompInfo, true},
+		{model.StatusInitialize, model.StatusCheckWix, true},
+		{model.StatusCheckWix, model.StatusCrawlCompInfo, true},
+		{model.StatusCrawlCompInfo, model.StatusPendingOutput, true},
+		{model.StatusCrawlCompInfo, model.StatusNeedsReview, true},
+		{model.StatusNeedsReview, model.StatusPendingOutput, true},
+		{model.StatusPendingOutput, model.StatusDone, true},
+		{model.StatusDone, model.StatusTrash, true},
+		{model.StatusCheckJapan, model.StatusUnknown, true},
+		{model.StatusTrash, model.StatusInitialize, true},
+		{model.StatusDone, model.StatusDone, true},
+
+		{model.StatusInitialize, model.StatusDone, false},
+		{model.StatusCheckView, model.StatusCrawlCompInfo, false},
+		{model.StatusCheckWix, model.StatusCheckJapan, false},
+		{model.StatusDone, model.StatusPendingOutput, false},
+		{model.StatusTrash, model.StatusDone, false},
+		{model.StatusInitialize, model.Status("foo"), false},
+		{model.Status("foo"), model.StatusTrash, false},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.from)+"->"+string(tt.to), func(t *testing.T) {
+			assert.Equal(t, tt.want, CanTransition(tt.from, tt.to))
+		})
+	}
+}
+
+func TestValidateTransition(t *testing.T) {
+	assert.NoError(t, ValidateTransition(model.StatusInitialize, model.StatusCheckView))
+	assert.ErrorIs(t, ValidateTransition(model.StatusInitialize, model.StatusDone), ErrInvalidStatusTransition)
+	assert.ErrorIs(t, ValidateTransition(model.StatusInitialize, model.StatusDone), ErrConflict)
+	assert.ErrorIs(t, ValidateTransition(model.StatusInitialize, model.Status("foo")), ErrValidation)
+}
+
+func TestDomainTransitionsCoverAllStatuses(t *testing.T) {
+	for _, s := range model.ValidStatuses {
+		_, ok := domainTransitions[s]
+		assert.True(t, ok, s)
+	}
+}
+
+func TestIsStuck(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.Local)
+	tests := []struct {
+		name   string
+		status model.Status
+		since  time.Duration
+		want   bool
+	}{
+		{"check_view over limit", model.StatusCheckView, 2 * time.Hour, true},
+		{"check_japan just at limit", model.StatusCheckJapan, time.Hour, true},
+		{"check_view within limit", model.StatusCheckView, 30 * time.Minute, false},
+		{"check_wix over limit", model.StatusCheckWix, 2 * time.Hour, true},
+		{"not waiting for worker", model.StatusCrawlCompInfo, 2 * time.Hour, false},
+		{"done", model.StatusDone, 48 * time.Hour, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := &model.Domain{Status: tt.status, StatusUpdatedAt: now.Add(-tt.since)}
+			assert.Equal(t, tt.want, IsStuck(d, now, time.Hour))
+		})
+	}
+}
+
+func TestFirstCheckStatus(t *testing.T) {
+	assert.Equal(t, model.StatusCheckWix, FirstCheckStatus(&model.Domain{Platform: model.PlatformWix}))
+	assert.Equal(t, model.StatusCheckView, FirstCheckStatus(&model.Domain{Platform: model.PlatformGeneric}))
+	assert.Equal(t, model.StatusCheckView, FirstCheckStatus(&model.Domain{}))
+}
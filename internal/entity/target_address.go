@@ -0,0 +1,69 @@
+package entity
+
+import (
+	"fmt"
+	"net/netip"
+	"strings"
+)
+
+// NormalizeIP IPv4・IPv6のアドレスを正規の表記にする。アドレスでなければfalse
+// IPv4射影アドレス（::ffff:192.0.2.1）はIPv4として扱う
+func NormalizeIP(s string) (string, bool) {
+	addr, err := netip.ParseAddr(strings.TrimSpace(s))
+	if err != nil || addr.Zone() != "" {
+		return "", false
+	}
+	return addr.Unmap().String(), true
+}
+
+// IsHostname ドット区切りのホスト名として正しいか。末尾のドットは許す
+func IsHostname(s string) bool {
+	s = strings.TrimSuffix(strings.TrimSpace(s), ".")
+	if len(s) == 0 || len(s) > 253 || !strings.Contains(s, ".") {
+		return false
+	}
+	for _, label := range strings.Split(s, ".") {
+		if len(label) == 0 || len(label) > 63 || label[0] == '-' || label[len(label)-1] == '-' {
+			return false
+		}
+		for _, c := range label {
+			if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-') {
+				return false
+			}
+		}
+	}
+	// 数字だけのラベルで終わるものは不正なIPアドレスとみなす
+	last := s[strings.LastIndex(s, ".")+1:]
+	return strings.Trim(last, "0123456789") != ""
+}
+
+// ExpandCIDR CIDRの範囲のアドレス
+// IPv4のプレフィックス長が30以下の範囲ではネットワークアドレスとブロードキャストアドレスを除く
+// アドレスがlimit個を超える場合はErrValidation
+func ExpandCIDR(cidr string, limit int) ([]string, error) {
+	prefix, err := netip.ParsePrefix(strings.TrimSpace(cidr))
+	if err != nil {
+		return nil, fmt.Errorf("invalid cidr %q: %w", cidr, ErrValidation)
+	}
+	prefix = prefix.Masked()
+	addr := prefix.Addr()
+
+	hostBits := addr.BitLen() - prefix.Bits()
+	skipEdges := addr.Is4() && hostBits >= 2
+	count := 1 << min(hostBits, 62)
+	if skipEdges {
+		count -= 2
+	}
+	if hostBits >= 62 || count > limit {
+		return nil, fmt.Errorf("cidr %q has more than %d addresses: %w", cidr, limit, ErrValidation)
+	}
+
+	ips := make([]string, 0, count)
+	for a := addr; a.IsValid() && prefix.Contains(a); a = a.Next() {
+		ips = append(ips, a.String())
+	}
+	if skipEdges {
+		ips = ips[1 : len(ips)-1]
+	}
+	return ips, nil
+}
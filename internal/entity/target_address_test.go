@@ -0,0 +1,77 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeIP(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+		ok   bool
+	}{
+		{"192.0.2.1", "192.0.2.1", true},
+		{" 192.0.2.1 ", "192.0.2.1", true},
+		{"2001:DB8:0:0::1", "2001:db8::1", true},
+		{"::ffff:192.0.2.1", "192.0.2.1", true},
+		{"192.0.2.256", "", false},
+		{"192.0.2", "", false},
+		{"fe80::1%eth0", "", false},
+		{"example.com", "", false},
+		{"", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, ok := NormalizeIP(tt.in)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestIsHostname(t *testing.T) {
+	assert.True(t, IsHostname("example.com"))
+	assert.True(t, IsHostname("www.example.co.jp."))
+	assert.True(t, IsHostname("xn--eckwd4c7c.xn--zckzah"))
+	assert.True(t, IsHostname("1.example.com"))
+	assert.False(t, IsHostname("localhost"))
+	assert.False(t, IsHostname("-bad.example.com"))
+	assert.False(t, IsHostname("bad..example.com"))
+	assert.False(t, IsHostname("under_score.example.com"))
+	assert.False(t, IsHostname("192.0.2.256"))
+	assert.False(t, IsHostname("192.0.2.1"))
+	assert.False(t, IsHostname(""))
+}
+
+func TestExpandCIDR(t *testing.T) {
+	tests := []struct {
+		name    string
+		cidr    string
+		limit   int
+		want    []string
+		wantErr bool
+	}{
+		{"single address", "192.0.2.1/32", 10, []string{"192.0.2.1"}, false},
+		{"point to point keeps both", "192.0.2.0/31", 10, []string{"192.0.2.0", "192.0.2.1"}, false},
+		{"skip network and broadcast", "192.0.2.0/30", 10, []string{"192.0.2.1", "192.0.2.2"}, false},
+		{"host bits are masked", "192.0.2.5/29", 10, []string{"192.0.2.1", "192.0.2.2", "192.0.2.3", "192.0.2.4", "192.0.2.5", "192.0.2.6"}, false},
+		{"ipv6", "2001:db8::/126", 10, []string{"2001:db8::", "2001:db8::1", "2001:db8::2", "2001:db8::3"}, false},
+		{"exactly limit", "192.0.2.0/29", 6, []string{"192.0.2.1", "192.0.2.2", "192.0.2.3", "192.0.2.4", "192.0.2.5", "192.0.2.6"}, false},
+		{"over limit", "192.0.2.0/24", 100, nil, true},
+		{"huge ipv6", "2001:db8::/32", 4096, nil, true},
+		{"invalid", "192.0.2.0/33", 10, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ExpandCIDR(tt.cidr, tt.limit)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrValidation)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
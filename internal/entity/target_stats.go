@@ -0,0 +1,15 @@
+package entity
+
+import "github.com/zuxt268/sales/internal/model"
+
+// DomainDoneRatio ステータスごとのドメイン数の合計と、そのうち done まで進んだ割合。0件の場合の割合は0
+func DomainDoneRatio(counts map[model.Status]int64) (int64, float64) {
+	var total int64
+	for _, c := range counts {
+		total += c
+	}
+	if total == 0 {
+		return 0, 0
+	}
+	return total, float64(counts[model.StatusDone]) / float64(total)
+}
@@ -0,0 +1,22 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zuxt268/sales/internal/model"
+)
+
+func TestDomainDoneRatio(t *testing.T) {
+	total, ratio := DomainDoneRatio(map[model.Status]int64{
+		model.StatusDone:      3,
+		model.StatusTrash:     4,
+		model.StatusCheckView: 1,
+	})
+	assert.Equal(t, int64(8), total)
+	assert.InDelta(t, 0.375, ratio, 1e-9)
+
+	total, ratio = DomainDoneRatio(nil)
+	assert.Equal(t, int64(0), total)
+	assert.Equal(t, 0.0, ratio)
+}
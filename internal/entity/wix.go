@@ -0,0 +1,25 @@
+package entity
+
+import (
+	"regexp"
+	"slices"
+)
+
+var wixOwnerID = regexp.MustCompile(`"ownerId":"([\w-]{36})"`)
+
+// EmptyWixOwnerIDs オーナーIDがないとみなす値。"-"・"−" は cmd/crawl が見つからなかったときに入れていたもの
+var EmptyWixOwnerIDs = []string{"", "-", "−"}
+
+// ExtractWixOwnerID WixのサイトのHTMLに埋め込まれたオーナーID。見つからない場合は空文字
+func ExtractWixOwnerID(html string) string {
+	match := wixOwnerID.FindStringSubmatch(html)
+	if len(match) < 2 {
+		return ""
+	}
+	return match[1]
+}
+
+// HasWixOwnerID オーナーIDが入っているか
+func HasWixOwnerID(ownerID string) bool {
+	return !slices.Contains(EmptyWixOwnerIDs, ownerID)
+}
@@ -0,0 +1,20 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExtractWixOwnerID(t *testing.T) {
+	html := `<script>var viewerModel = {"siteOwnerId":"x","ownerId":"1b2c3d4e-0000-4a5b-8c9d-0123456789ab","metaSiteId":"y"};</script>`
+	assert.Equal(t, "1b2c3d4e-0000-4a5b-8c9d-0123456789ab", ExtractWixOwnerID(html))
+	assert.Equal(t, "", ExtractWixOwnerID(`<html><body>no owner</body></html>`))
+}
+
+func TestHasWixOwnerID(t *testing.T) {
+	assert.True(t, HasWixOwnerID("1b2c3d4e-0000-4a5b-8c9d-0123456789ab"))
+	assert.False(t, HasWixOwnerID(""))
+	assert.False(t, HasWixOwnerID("-"))
+	assert.False(t, HasWixOwnerID("−"))
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"os"
 
 	"cloud.google.com/go/pubsub"
 	"google.golang.org/api/option"
@@ -11,7 +12,8 @@ import (
 
 type PubSubClient interface {
 	Publish(ctx context.Context, topicID string, data []byte) (string, error)
-	Subscribe(ctx context.Context, subscriptionID string, handler func(context.Context, *pubsub.Message)) error
+	Subscribe(ctx context.Context, subscriptionID string, maxOutstanding int, handler func(context.Context, *pubsub.Message)) error
+	HasDeadLetterPolicy(ctx context.Context, subscriptionID string) (bool, error)
 	Close() error
 }
 
@@ -21,9 +23,14 @@ type pubSubClient struct {
 }
 
 // NewPubSubClient Google Cloud Pub/Subクライアントを初期化
+// PUBSUB_EMULATOR_HOSTが設定されている場合はエミュレータに接続するため認証情報を使わない
 func NewPubSubClient(projectID string, credPath string) PubSubClient {
 	ctx := context.Background()
-	client, err := pubsub.NewClient(ctx, projectID, option.WithCredentialsFile(credPath))
+	var opts []option.ClientOption
+	if os.Getenv("PUBSUB_EMULATOR_HOST") == "" {
+		opts = append(opts, option.WithCredentialsFile(credPath))
+	}
+	client, err := pubsub.NewClient(ctx, projectID, opts...)
 	if err != nil {
 		panic(err)
 	}
@@ -58,10 +65,13 @@ func (c *pubSubClient) Publish(ctx context.Context, topicID string, data []byte)
 }
 
 // Subscribe サブスクリプションからメッセージを受信
-// handlerは各メッセージに対して呼ばれるコールバック関数
+// handlerは各メッセージに対して呼ばれるコールバック関数。maxOutstandingは同時に受け取るメッセージ数の上限（0は既定値）
 // このメソッドはブロッキングし、contextがキャンセルされるまで実行し続けます
-func (c *pubSubClient) Subscribe(ctx context.Context, subscriptionID string, handler func(context.Context, *pubsub.Message)) error {
+func (c *pubSubClient) Subscribe(ctx context.Context, subscriptionID string, maxOutstanding int, handler func(context.Context, *pubsub.Message)) error {
 	sub := c.client.Subscription(subscriptionID)
+	if maxOutstanding > 0 {
+		sub.ReceiveSettings.MaxOutstandingMessages = maxOutstanding
+	}
 
 	slog.Info("Starting to receive messages",
 		"subscription_id", subscriptionID,
@@ -86,6 +96,16 @@ func (c *pubSubClient) Subscribe(ctx context.Context, subscriptionID string, han
 	return nil
 }
 
+// HasDeadLetterPolicy サブスクリプションにデッドレターの設定があるか
+// 設定がないとメッセージの配信回数（DeliveryAttempt）が付かない
+func (c *pubSubClient) HasDeadLetterPolicy(ctx context.Context, subscriptionID string) (bool, error) {
+	cfg, err := c.client.Subscription(subscriptionID).Config(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to get subscription config: %w", err)
+	}
+	return cfg.DeadLetterPolicy != nil, nil
+}
+
 // Close クライアントをクローズ
 func (c *pubSubClient) Close() error {
 	if err := c.client.Close(); err != nil {
@@ -7,6 +7,8 @@ import (
 	"time"
 
 	"github.com/redis/go-redis/v9"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
 	"github.com/zuxt268/sales/internal/config"
 )
 
@@ -39,3 +41,40 @@ func NewRedisQueue() *redis.Client {
 
 	return client
 }
+
+// NewTestContainerRedisClient テスト用のRedisコンテナを起動する
+func NewTestContainerRedisClient() (*redis.Client, func(), error) {
+	ctx := context.Background()
+
+	container, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
+		ContainerRequest: testcontainers.ContainerRequest{
+			Image:        "redis:7-alpine",
+			ExposedPorts: []string{"6379/tcp"},
+			WaitingFor:   wait.ForListeningPort("6379/tcp").WithStartupTimeout(60 * time.Second),
+		},
+		Started: true,
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to start Redis container: %w", err)
+	}
+
+	host, err := container.Host(ctx)
+	if err != nil {
+		_ = container.Terminate(ctx)
+		return nil, nil, fmt.Errorf("failed to get container host: %w", err)
+	}
+	port, err := container.MappedPort(ctx, "6379")
+	if err != nil {
+		_ = container.Terminate(ctx)
+		return nil, nil, fmt.Errorf("failed to get container port: %w", err)
+	}
+
+	client := redis.NewClient(&redis.Options{
+		Addr: fmt.Sprintf("%s:%s", host, port.Port()),
+	})
+	cleanup := func() {
+		_ = client.Close()
+		_ = container.Terminate(ctx)
+	}
+	return client, cleanup, nil
+}
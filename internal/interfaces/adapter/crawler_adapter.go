@@ -0,0 +1,209 @@
+package adapter
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/zuxt268/sales/internal/entity"
+	"github.com/zuxt268/sales/internal/interfaces/dto/external"
+)
+
+// companyInfoMaxRunes GPTに渡すテキストの上限（domains.raw_pageの上限と合わせる）
+const companyInfoMaxRunes = 6000
+
+// fetchPageMaxBytes 1ページで読むHTMLの上限
+const fetchPageMaxBytes = 2 << 20
+
+type CrawlerAdapter interface {
+	CrawlCompanyInfo(ctx context.Context, siteURL string) (*external.CompanyInfo, error)
+	FetchPage(ctx context.Context, siteURL string) (*external.SitePage, error)
+}
+
+type crawlerAdapter struct {
+	client   *http.Client
+	maxPages int
+}
+
+func NewCrawlerAdapter(maxPages int) CrawlerAdapter {
+	return &crawlerAdapter{
+		client: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+		maxPages: maxPages,
+	}
+}
+
+// CrawlCompanyInfo トップページから会社概要などのページを探して巡回し、
+// 重複を除いたテキストと、テキストの取得元URLを返す
+func (a *crawlerAdapter) CrawlCompanyInfo(ctx context.Context, siteURL string) (*external.CompanyInfo, error) {
+	top, err := url.Parse(siteURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid site url %s: %w", siteURL, err)
+	}
+	if top.Scheme == "" {
+		top, err = url.Parse("https://" + siteURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid site url %s: %w", siteURL, err)
+		}
+	}
+
+	doc, finalURL, err := a.get(ctx, top.String())
+	if err != nil {
+		return nil, err
+	}
+
+	pages := [][]string{textSegments(doc)}
+	urls := []string{finalURL.String()}
+
+	for _, link := range companyInfoLinks(doc, finalURL, a.maxPages) {
+		d, u, err := a.get(ctx, link)
+		if err != nil {
+			slog.Warn("failed to crawl company info page", "url", link, "error", err)
+			continue
+		}
+		pages = append(pages, textSegments(d))
+		urls = append(urls, u.String())
+	}
+
+	// 会社情報ページを優先してテキストに含める
+	if len(pages) > 1 {
+		pages = append(pages[1:], pages[0])
+		urls = append(urls[1:], urls[0])
+	}
+
+	return &external.CompanyInfo{
+		Text: entity.BuildCorpus(pages, companyInfoMaxRunes),
+		URLs: urls,
+	}, nil
+}
+
+// FetchPage ページのヘッダーとHTMLをそのまま返す。スキームがない場合はhttpsで取得する
+func (a *crawlerAdapter) FetchPage(ctx context.Context, siteURL string) (*external.SitePage, error) {
+	if !strings.Contains(siteURL, "://") {
+		siteURL = "https://" + siteURL
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, siteURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s: %w", siteURL, err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to get %s: %s", siteURL, resp.Status)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, fetchPageMaxBytes))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", siteURL, err)
+	}
+	return &external.SitePage{
+		URL:    resp.Request.URL.String(),
+		Header: resp.Header,
+		HTML:   string(body),
+	}, nil
+}
+
+func (a *crawlerAdapter) get(ctx context.Context, u string) (*goquery.Document, *url.URL, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get %s: %w", u, err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	if resp.StatusCode != http.StatusOK {
+		return nil, nil, fmt.Errorf("failed to get %s: %s", u, resp.Status)
+	}
+	doc, err := goquery.NewDocumentFromReader(io.LimitReader(resp.Body, fetchPageMaxBytes))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse %s: %w", u, err)
+	}
+	return doc, resp.Request.URL, nil
+}
+
+// companyInfoLinks 同一ホストのリンクのうち会社情報ページらしいものを点数の高い順に最大limit件返す
+func companyInfoLinks(doc *goquery.Document, base *url.URL, limit int) []string {
+	type candidate struct {
+		url   string
+		score int
+	}
+	seen := map[string]struct{}{
+		base.String(): {},
+	}
+	var candidates []candidate
+	doc.Find("a[href]").Each(func(_ int, s *goquery.Selection) {
+		href, _ := s.Attr("href")
+		text := s.Text()
+		if title, ok := s.Attr("title"); ok {
+			text += " " + title
+		}
+		score := entity.CompanyInfoLinkScore(href, text)
+		if score == 0 {
+			return
+		}
+		ref, err := url.Parse(strings.TrimSpace(href))
+		if err != nil {
+			return
+		}
+		u := base.ResolveReference(ref)
+		u.Fragment = ""
+		if u.Host != base.Host || (u.Scheme != "http" && u.Scheme != "https") {
+			return
+		}
+		if _, ok := seen[u.String()]; ok {
+			return
+		}
+		seen[u.String()] = struct{}{}
+		candidates = append(candidates, candidate{url: u.String(), score: score})
+	})
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].score > candidates[j].score
+	})
+	if len(candidates) > limit {
+		candidates = candidates[:limit]
+	}
+	links := make([]string, 0, len(candidates))
+	for _, c := range candidates {
+		links = append(links, c.url)
+	}
+	return links
+}
+
+// textSegments body内のテキストノードを順に取り出す
+func textSegments(doc *goquery.Document) []string {
+	body := doc.Find("body")
+	body.Find("script,style,link,noscript,iframe,svg").Remove()
+
+	var segments []string
+	var walk func(s *goquery.Selection)
+	walk = func(s *goquery.Selection) {
+		s.Contents().Each(func(_ int, c *goquery.Selection) {
+			if goquery.NodeName(c) == "#text" {
+				if text := strings.Join(strings.Fields(c.Text()), " "); text != "" {
+					segments = append(segments, text)
+				}
+				return
+			}
+			walk(c)
+		})
+	}
+	walk(body)
+	return segments
+}
@@ -0,0 +1,45 @@
+package adapter
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCrawlerAdapter_CrawlCompanyInfo(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/":
+			_, _ = w.Write([]byte(`<html><body><h1>山中総合研究所</h1><a href="/company">会社概要</a><a href="https://other.example/about">about</a></body></html>`))
+		case "/company":
+			_, _ = w.Write([]byte(`<html><body><p>所在地 東京都千代田区1-1-1</p></body></html>`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	info, err := NewCrawlerAdapter(5).CrawlCompanyInfo(context.Background(), srv.URL)
+	assert.NoError(t, err)
+	// 会社情報ページを先に、別ホストのリンクはたどらない
+	assert.Equal(t, []string{srv.URL + "/company", srv.URL}, info.URLs)
+	assert.True(t, strings.Index(info.Text, "所在地") < strings.Index(info.Text, "山中総合研究所"))
+}
+
+func TestCrawlerAdapter_CrawlCompanyInfo_LimitsPageSize(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`<html><body><p>top</p><p>`))
+		_, _ = w.Write([]byte(strings.Repeat("a", fetchPageMaxBytes)))
+		_, _ = w.Write([]byte(`</p><p>after limit</p></body></html>`))
+	}))
+	t.Cleanup(srv.Close)
+
+	info, err := NewCrawlerAdapter(0).CrawlCompanyInfo(context.Background(), srv.URL)
+	assert.NoError(t, err)
+	assert.Contains(t, info.Text, "top")
+	assert.NotContains(t, info.Text, "after limit")
+}
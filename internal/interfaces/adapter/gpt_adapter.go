@@ -2,190 +2,239 @@ package adapter
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"log/slog"
 	"net/http"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/sashabaranov/go-openai"
+	"github.com/sashabaranov/go-openai/jsonschema"
 	"github.com/zuxt268/sales/internal/config"
-	"github.com/zuxt268/sales/internal/model"
+	"github.com/zuxt268/sales/internal/entity"
+	"github.com/zuxt268/sales/internal/interfaces/dto/external"
 )
 
 type GptAdapter interface {
-	Analyze(ctx context.Context, domain *model.Domain) error
-	AnalyzeSiteIndustry(ctx context.Context, text string) (string, error)
+	Analyze(ctx context.Context, text string, hints entity.AnalysisHints) (*entity.CompanyAnalysis, *external.LLMUsage, error)
+	AnalyzeSiteIndustry(ctx context.Context, text string) (string, *external.LLMUsage, error)
 }
 
+const (
+	LLMProviderOpenAI = "openai"
+	LLMProviderStub   = "stub"
+)
+
 type gptAdapter struct {
-	client *openai.Client
+	client      *openai.Client
+	model       string
+	temperature float32
+	maxTokens   int
+	cache       LLMCacheAdapter
 }
 
-func NewGptAdapter() GptAdapter {
+// NewGptAdapter LLM_PROVIDERに応じたアダプターを返す
+// openaiはLLM_BASE_URLを指定するとOpenAI互換のローカルサーバーにも接続できる
+// 同じ入力に対する応答はcacheから返す。対応していないLLM_PROVIDERの場合はエラー
+func NewGptAdapter(cache LLMCacheAdapter) (GptAdapter, error) {
+	switch config.Env.LLMProvider {
+	case LLMProviderOpenAI:
+	case LLMProviderStub:
+		return NewStubGptAdapter(), nil
+	default:
+		return nil, fmt.Errorf("unknown llm provider: %q", config.Env.LLMProvider)
+	}
+
 	apiKey := config.Env.OpenaiApiKey
 	cfg := openai.DefaultConfig(apiKey)
+	if config.Env.LLMBaseURL != "" {
+		cfg.BaseURL = config.Env.LLMBaseURL
+	}
 	cfg.HTTPClient = &http.Client{
-		Timeout: 10 * time.Second,
+		Timeout: config.Env.LLMTimeout,
 	}
 	client := openai.NewClientWithConfig(cfg)
 	return &gptAdapter{
-		client: client,
+		client:      client,
+		model:       config.Env.LLMModel,
+		temperature: config.Env.LLMTemperature,
+		maxTokens:   config.Env.LLMMaxTokens,
+		cache:       cache,
+	}, nil
+}
+
+// chatRequest 設定されたモデル・温度・最大トークン数でリクエストを組み立てる
+func (a *gptAdapter) chatRequest(prompt string) openai.ChatCompletionRequest {
+	return openai.ChatCompletionRequest{
+		Model: a.model,
+		Messages: []openai.ChatCompletionMessage{
+			{
+				Role:    openai.ChatMessageRoleSystem,
+				Content: systemPrompt,
+			},
+			{
+				Role:    openai.ChatMessageRoleUser,
+				Content: prompt,
+			},
+		},
+		Temperature:         a.temperature,
+		MaxCompletionTokens: a.maxTokens,
 	}
 }
 
-const systemPrompt = `
-業種リストです。この中から業種を選らんでください。
-農業
-林業
-漁業（水産養殖業を除く）
-水産養殖業
-鉱業，採石業，砂利採取業
-総合工事業
-職別工事業(設備工事業を除く)
-設備工事業
-食料品製造業
-飲料・たばこ・飼料製造業
-繊維工業
-木材・木製品製造業（家具を除く）
-家具・装備品製造業
-パルプ・紙・紙加工品製造業
-印刷・同関連業
-化学工業
-石油製品・石炭製品製造業
-プラスチック製品製造業（別掲を除く）
-ゴム製品製造業
-なめし革・同製品・毛皮製造業
-窯業・土石製品製造業
-鉄鋼業
-非鉄金属製造業
-金属製品製造業
-はん用機械器具製造業
-生産用機械器具製造業
-業務用機械器具製造業
-電子部品・デバイス・電子回路製造業
-電気機械器具製造業
-情報通信機械器具製造業
-輸送用機械器具製造業
-その他の製造業
-電気業
-ガス業
-熱供給業
-水道業
-通信業
-放送業
-情報サービス業
-インターネット附随サービス業
-映像・音声・文字情報制作業
-鉄道業
-道路旅客運送業
-道路貨物運送業
-水運業
-航空運輸業
-倉庫業
-運輸に附帯するサービス業
-郵便業（信書便事業を含む）
-各種商品卸売業
-繊維・衣服等卸売業
-飲食料品卸売業
-建築材料，鉱物・金属材料等卸売業
-機械器具卸売業
-その他の卸売業
-各種商品小売業
-織物・衣服・身の回り品小売業
-飲食料品小売業
-機械器具小売業
-その他の小売業
-無店舗小売業
-銀行業
-協同組織金融業
-貸金業，クレジットカード業等非預金信用機関
-金融商品取引業，商品先物取引業
-補助的金融業等
-保険業（保険媒介代理業，保険サービス業を含む）
-不動産取引業
-不動産賃貸業・管理業
-物品賃貸業
-学術・開発研究機関
-専門サービス業（他に分類されないもの）
-広告業
-技術サービス業（他に分類されないもの）
-宿泊業
-飲食店
-持ち帰り・配達飲食サービス業
-洗濯・理容・美容・浴場業
-その他の生活関連サービス業
-娯楽業
-学校教育
-その他の教育，学習支援業
-医療業
-保健衛生
-社会保険・社会福祉・介護事業
-郵便局
-協同組合（他に分類されないもの）
-廃棄物処理業
-自動車整備業
-機械等修理業（別掲を除く）
-職業紹介・労働者派遣業
-その他の事業サービス業
-政治・経済・文化団体
-宗教
-その他のサービス業
-外国公務
-国家公務
-地方公務
-分類不能の産業
-`
+// complete リクエストを送信し、レスポンスと使用量を返す
+func (a *gptAdapter) complete(ctx context.Context, req openai.ChatCompletionRequest) (openai.ChatCompletionResponse, *external.LLMUsage, error) {
+	start := time.Now()
+	resp, err := a.client.CreateChatCompletion(ctx, req)
+	if err != nil {
+		return resp, nil, fmt.Errorf("ChatCompletion error: %w", err)
+	}
+	usage := &external.LLMUsage{
+		Model:            resp.Model,
+		PromptTokens:     resp.Usage.PromptTokens,
+		CompletionTokens: resp.Usage.CompletionTokens,
+		Latency:          time.Since(start),
+	}
+	if usage.Model == "" {
+		usage.Model = req.Model
+	}
+	return resp, usage, nil
+}
+
+// getCache キャッシュから取得する。取得できなくても解析は続けるためエラーはログに残すだけにする
+func (a *gptAdapter) getCache(ctx context.Context, key string, v any) bool {
+	ok, err := a.cache.Get(ctx, key, v)
+	if err != nil {
+		slog.Warn("failed to get llm cache", "key", key, "error", err)
+		return false
+	}
+	return ok
+}
+
+func (a *gptAdapter) setCache(ctx context.Context, key string, v any) {
+	if err := a.cache.Set(ctx, key, v); err != nil {
+		slog.Warn("failed to set llm cache", "key", key, "error", err)
+	}
+}
+
+// プロンプトやスキーマを変更した場合はバージョンを上げて古いキャッシュを使わないようにする
+const (
+	analyzePromptVersion             = "analyze:v1"
+	analyzeKnownPrefPromptVersion    = "analyze_known_prefecture:v1"
+	analyzeSiteIndustryPromptVersion = "analyze_site_industry:v1"
+)
+
+var systemPrompt = "\n業種リストです。この中から業種を選らんでください。\n" + strings.Join(entity.Industries, "\n") + "\n"
 
 const promptTemplate = `"""%s"""
-以上の情報から、業種、代表者名、会社名、都道府県を答えてください。単語で答えてください。見つからない場合は、「なし」と表示してください。
-４つをカンマ区切りで一行で出力してください。
-順番は守ってください。
-例）自動車整備業,山田太郎,株式会社タロウ,東京都
+以上の情報から、業種、代表者名、会社名、都道府県を答えてください。単語で答えてください。見つからない場合は、「なし」と答えてください。
+業種は業種リストから、都道府県は47都道府県から選んでください。
+それぞれの回答について、確信度を0から1の数値で答えてください。
 `
 
-func (a *gptAdapter) Analyze(ctx context.Context, d *model.Domain) error {
-	prompt := fmt.Sprintf(promptTemplate, d.RawPage)
-	resp, err := a.client.CreateChatCompletion(
-		ctx,
-		openai.ChatCompletionRequest{
-			Model: "gpt-5-nano",
-			Messages: []openai.ChatCompletionMessage{
-				{
-					Role:    openai.ChatMessageRoleSystem,
-					Content: systemPrompt,
-				},
-				{
-					Role:    openai.ChatMessageRoleUser,
-					Content: prompt,
-				},
-			},
+// promptKnownPrefectureTemplate 都道府県を住所や郵便番号から判定できた場合のプロンプト
+const promptKnownPrefectureTemplate = `"""%s"""
+以上の情報から、業種、代表者名、会社名を答えてください。単語で答えてください。見つからない場合は、「なし」と答えてください。
+業種は業種リストから選んでください。
+それぞれの回答について、確信度を0から1の数値で答えてください。
+`
+
+// companyAnalysisSchema 企業情報解析の構造化出力のスキーマ
+var companyAnalysisSchema = &jsonschema.Definition{
+	Type: jsonschema.Object,
+	Properties: map[string]jsonschema.Definition{
+		"industry": {
+			Type:        jsonschema.String,
+			Description: "業種リストから選んだ業種",
+			Enum:        append(append([]string{}, entity.Industries...), entity.NotFoundAnswer),
 		},
-	)
-	if err != nil {
-		return fmt.Errorf("ChatCompletion error: %w", err)
+		"industry_confidence": {Type: jsonschema.Number, Description: "業種の確信度(0〜1)"},
+		"president": {
+			Type:        jsonschema.String,
+			Description: "代表者名",
+		},
+		"president_confidence": {Type: jsonschema.Number, Description: "代表者名の確信度(0〜1)"},
+		"company": {
+			Type:        jsonschema.String,
+			Description: "会社名",
+		},
+		"company_confidence": {Type: jsonschema.Number, Description: "会社名の確信度(0〜1)"},
+		"prefecture": {
+			Type:        jsonschema.String,
+			Description: "所在地の都道府県",
+			Enum:        append(append([]string{}, entity.Prefectures...), entity.NotFoundAnswer),
+		},
+		"prefecture_confidence": {Type: jsonschema.Number, Description: "都道府県の確信度(0〜1)"},
+	},
+	Required: []string{
+		"industry", "industry_confidence",
+		"president", "president_confidence",
+		"company", "company_confidence",
+		"prefecture", "prefecture_confidence",
+	},
+	AdditionalProperties: false,
+}
+
+// companyAnalysisWithoutPrefectureSchema 都道府県を尋ねない場合のスキーマ
+var companyAnalysisWithoutPrefectureSchema = withoutProperties(companyAnalysisSchema, "prefecture", "prefecture_confidence")
+
+// withoutProperties スキーマから指定した項目を除いたコピー
+func withoutProperties(schema *jsonschema.Definition, names ...string) *jsonschema.Definition {
+	s := *schema
+	s.Properties = make(map[string]jsonschema.Definition, len(schema.Properties))
+	for k, v := range schema.Properties {
+		if !slices.Contains(names, k) {
+			s.Properties[k] = v
+		}
 	}
+	s.Required = make([]string, 0, len(schema.Required))
+	for _, k := range schema.Required {
+		if !slices.Contains(names, k) {
+			s.Required = append(s.Required, k)
+		}
+	}
+	return &s
+}
 
-	if len(resp.Choices) == 0 {
-		return nil
+// Analyze 業種・代表者名・会社名・都道府県を尋ねる。hintsで判定できている項目は尋ねない
+func (a *gptAdapter) Analyze(ctx context.Context, text string, hints entity.AnalysisHints) (*entity.CompanyAnalysis, *external.LLMUsage, error) {
+	version, template, schema := analyzePromptVersion, promptTemplate, companyAnalysisSchema
+	if hints.Prefecture != "" {
+		version, template, schema = analyzeKnownPrefPromptVersion, promptKnownPrefectureTemplate, companyAnalysisWithoutPrefectureSchema
 	}
-	contents := strings.Split(resp.Choices[0].Message.Content, ",")
-	if len(contents) != 4 {
-		return nil
+	key := LLMCacheKey(version, a.model, text)
+	var cached entity.CompanyAnalysis
+	if a.getCache(ctx, key, &cached) {
+		return &cached, nil, nil
 	}
-	fmt.Println(contents)
-	if contents[0] != "なし" {
-		d.Industry = contents[0]
+
+	prompt := fmt.Sprintf(template, text)
+	req := a.chatRequest(prompt)
+	req.ResponseFormat = &openai.ChatCompletionResponseFormat{
+		Type: openai.ChatCompletionResponseFormatTypeJSONSchema,
+		JSONSchema: &openai.ChatCompletionResponseFormatJSONSchema{
+			Name:   "company_analysis",
+			Schema: schema,
+			Strict: true,
+		},
 	}
-	if contents[1] != "なし" {
-		d.President = contents[1]
+	resp, usage, err := a.complete(ctx, req)
+	if err != nil {
+		return nil, nil, err
 	}
-	if contents[2] != "なし" {
-		d.Company = contents[2]
+	if len(resp.Choices) == 0 {
+		return nil, usage, fmt.Errorf("ChatCompletion returned no choices")
 	}
-	if contents[3] != "なし" {
-		d.Prefecture = contents[3]
+
+	var analysis entity.CompanyAnalysis
+	if err := json.Unmarshal([]byte(resp.Choices[0].Message.Content), &analysis); err != nil {
+		return nil, usage, fmt.Errorf("failed to parse analysis %q: %w", resp.Choices[0].Message.Content, err)
 	}
-	return nil
+	analysis.Normalize()
+	a.setCache(ctx, key, analysis)
+	return &analysis, usage, nil
 }
 
 const promptAnalyzeIndustryTemplate = `"""%s"""
@@ -195,29 +244,22 @@ const promptAnalyzeIndustryTemplate = `"""%s"""
 例）自動車整備業,広告業
 `
 
-func (a *gptAdapter) AnalyzeSiteIndustry(ctx context.Context, text string) (string, error) {
+func (a *gptAdapter) AnalyzeSiteIndustry(ctx context.Context, text string) (string, *external.LLMUsage, error) {
+	key := LLMCacheKey(analyzeSiteIndustryPromptVersion, a.model, text)
+	var cached string
+	if a.getCache(ctx, key, &cached) {
+		return cached, nil, nil
+	}
+
 	prompt := fmt.Sprintf(promptAnalyzeIndustryTemplate, text)
-	resp, err := a.client.CreateChatCompletion(
-		ctx,
-		openai.ChatCompletionRequest{
-			Model: "gpt-5-nano",
-			Messages: []openai.ChatCompletionMessage{
-				{
-					Role:    openai.ChatMessageRoleSystem,
-					Content: systemPrompt,
-				},
-				{
-					Role:    openai.ChatMessageRoleUser,
-					Content: prompt,
-				},
-			},
-		},
-	)
+	resp, usage, err := a.complete(ctx, a.chatRequest(prompt))
 	if err != nil {
-		return "", fmt.Errorf("ChatCompletion error: %w", err)
+		return "", nil, err
 	}
 	if len(resp.Choices) == 0 {
-		return "", nil
+		return "", usage, nil
 	}
-	return resp.Choices[0].Message.Content, nil
+	industry := resp.Choices[0].Message.Content
+	a.setCache(ctx, key, industry)
+	return industry, usage, nil
 }
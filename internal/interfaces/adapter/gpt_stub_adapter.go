@@ -0,0 +1,95 @@
+package adapter
+
+import (
+	"context"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/zuxt268/sales/internal/entity"
+	"github.com/zuxt268/sales/internal/interfaces/dto/external"
+)
+
+// stubGptAdapter ネットワークに接続せず、テキストから機械的に結果を返すスタブ
+// テストやローカル実行で使う。同じ入力には常に同じ結果を返す
+type stubGptAdapter struct{}
+
+const stubModel = "stub"
+
+func NewStubGptAdapter() GptAdapter {
+	return &stubGptAdapter{}
+}
+
+var (
+	stubCompanyPattern   = regexp.MustCompile(`(?:株式会社|有限会社|合同会社)[^\s　、。,（(]+|[^\s　、。,:：]+(?:株式会社|有限会社|合同会社)`)
+	stubPresidentPattern = regexp.MustCompile(`代表(?:取締役)?(?:社長)?(?:者)?[\s　:：]*([^\s　、。,]{2,10})`)
+)
+
+// Analyze テキストに含まれる業種名・会社名・代表者名・都道府県を返す。hintsで判定できている項目は返さない
+func (a *stubGptAdapter) Analyze(_ context.Context, text string, hints entity.AnalysisHints) (*entity.CompanyAnalysis, *external.LLMUsage, error) {
+	analysis := &entity.CompanyAnalysis{}
+	if industries := stubIndustries(text, 1); len(industries) > 0 {
+		analysis.Industry = industries[0]
+		analysis.IndustryConfidence = 1
+	}
+	if m := stubCompanyPattern.FindString(text); m != "" {
+		analysis.Company = m
+		analysis.CompanyConfidence = 1
+	}
+	if m := stubPresidentPattern.FindStringSubmatch(text); m != nil {
+		analysis.President = m[1]
+		analysis.PresidentConfidence = 1
+	}
+	if p := entity.ExtractContactInfo(text).Prefecture; p != "" && hints.Prefecture == "" {
+		analysis.Prefecture = p
+		analysis.PrefectureConfidence = 1
+	}
+	return analysis, stubUsage(text), nil
+}
+
+// AnalyzeSiteIndustry テキストに含まれる業種名を最大3個カンマ区切りで返す
+func (a *stubGptAdapter) AnalyzeSiteIndustry(_ context.Context, text string) (string, *external.LLMUsage, error) {
+	industries := stubIndustries(text, 3)
+	if len(industries) == 0 {
+		return "分類不能の産業", stubUsage(text), nil
+	}
+	return strings.Join(industries, ","), stubUsage(text), nil
+}
+
+// stubUsage 入力の文字数をトークン数とみなした使用量
+func stubUsage(text string) *external.LLMUsage {
+	return &external.LLMUsage{
+		Model:        stubModel,
+		PromptTokens: utf8.RuneCountInString(text),
+	}
+}
+
+// stubIndustries テキストに出現する業種名を出現順に最大limit個返す
+func stubIndustries(text string, limit int) []string {
+	type found struct {
+		name string
+		pos  int
+	}
+	var hits []found
+	for _, name := range entity.Industries {
+		if i := strings.Index(text, name); i >= 0 {
+			hits = append(hits, found{name: name, pos: i})
+		}
+	}
+	// 出現位置の昇順。同じ位置なら長い業種名を優先する
+	sort.SliceStable(hits, func(i, j int) bool {
+		if hits[i].pos != hits[j].pos {
+			return hits[i].pos < hits[j].pos
+		}
+		return len(hits[i].name) > len(hits[j].name)
+	})
+	names := make([]string, 0, limit)
+	for _, h := range hits {
+		if len(names) >= limit {
+			break
+		}
+		names = append(names, h.name)
+	}
+	return names
+}
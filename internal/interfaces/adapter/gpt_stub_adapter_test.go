@@ -0,0 +1,62 @@
+package adapter
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zuxt268/sales/internal/config"
+	"github.com/zuxt268/sales/internal/entity"
+)
+
+func TestStubGptAdapter_Analyze(t *testing.T) {
+	text := "会社概要\n会社名 株式会社タロウ\n代表取締役 山田太郎\n〒100-0001 東京都千代田区千代田1-1\n事業内容 自動車整備業"
+	a := NewStubGptAdapter()
+
+	got, usage, err := a.Analyze(context.Background(), text, entity.AnalysisHints{})
+	assert.NoError(t, err)
+	assert.Equal(t, "stub", usage.Model)
+	assert.Equal(t, "自動車整備業", got.Industry)
+	assert.Equal(t, "株式会社タロウ", got.Company)
+	assert.Equal(t, "山田太郎", got.President)
+	assert.Equal(t, "東京都", got.Prefecture)
+	assert.Empty(t, got.Validate(0.6))
+
+	// 同じ入力には同じ結果を返す
+	again, _, err := a.Analyze(context.Background(), text, entity.AnalysisHints{})
+	assert.NoError(t, err)
+	assert.Equal(t, got, again)
+
+	// 判定できている都道府県は返さない
+	narrowed, _, err := a.Analyze(context.Background(), text, entity.AnalysisHints{Prefecture: "東京都"})
+	assert.NoError(t, err)
+	assert.Empty(t, narrowed.Prefecture)
+	assert.Equal(t, got.Industry, narrowed.Industry)
+}
+
+func TestStubGptAdapter_AnalyzeSiteIndustry(t *testing.T) {
+	a := NewStubGptAdapter()
+
+	got, _, err := a.AnalyzeSiteIndustry(context.Background(), "飲食店と宿泊業と広告業と娯楽業を営んでいます")
+	assert.NoError(t, err)
+	assert.Equal(t, "飲食店,宿泊業,広告業", got)
+
+	got, _, err = a.AnalyzeSiteIndustry(context.Background(), "hello")
+	assert.NoError(t, err)
+	assert.Equal(t, "分類不能の産業", got)
+}
+
+func TestNewGptAdapter(t *testing.T) {
+	provider := config.Env.LLMProvider
+	t.Cleanup(func() { config.Env.LLMProvider = provider })
+
+	config.Env.LLMProvider = LLMProviderStub
+	a, err := NewGptAdapter(nil)
+	assert.NoError(t, err)
+	assert.IsType(t, &stubGptAdapter{}, a)
+
+	// 対応していないプロバイダーは起動時にエラーにする
+	config.Env.LLMProvider = "unknown"
+	_, err = NewGptAdapter(nil)
+	assert.Error(t, err)
+}
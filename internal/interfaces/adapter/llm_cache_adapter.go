@@ -0,0 +1,117 @@
+package adapter
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const llmCacheKeyPrefix = "llm:cache:"
+
+// LLMCacheAdapter LLMの応答を入力テキストのハッシュで保存するキャッシュ
+type LLMCacheAdapter interface {
+	Get(ctx context.Context, key string, v any) (bool, error)
+	Set(ctx context.Context, key string, v any) error
+	Clear(ctx context.Context) (int, error)
+}
+
+type llmCacheAdapter struct {
+	client *redis.Client
+	ttl    time.Duration
+}
+
+// NewLLMCacheAdapter ttlが0以下の場合はキャッシュしない
+func NewLLMCacheAdapter(client *redis.Client, ttl time.Duration) LLMCacheAdapter {
+	return &llmCacheAdapter{
+		client: client,
+		ttl:    ttl,
+	}
+}
+
+// noopLLMCacheAdapter Redisを使わない場合のキャッシュ。何も保存しない
+type noopLLMCacheAdapter struct{}
+
+func NewNoopLLMCacheAdapter() LLMCacheAdapter {
+	return noopLLMCacheAdapter{}
+}
+
+func (noopLLMCacheAdapter) Get(context.Context, string, any) (bool, error) { return false, nil }
+func (noopLLMCacheAdapter) Set(context.Context, string, any) error         { return nil }
+func (noopLLMCacheAdapter) Clear(context.Context) (int, error)             { return 0, nil }
+
+// LLMCacheKey プロンプトのバージョン・モデル・入力テキストのsha256からキーを作る
+func LLMCacheKey(promptVersion, model, text string) string {
+	sum := sha256.Sum256([]byte(text))
+	return fmt.Sprintf("%s%s:%s:%s", llmCacheKeyPrefix, promptVersion, model, hex.EncodeToString(sum[:]))
+}
+
+func (a *llmCacheAdapter) Get(ctx context.Context, key string, v any) (bool, error) {
+	if a.ttl <= 0 {
+		return false, nil
+	}
+	data, err := a.client.Get(ctx, key).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to get llm cache: %w", err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return false, fmt.Errorf("failed to unmarshal llm cache: %w", err)
+	}
+	return true, nil
+}
+
+func (a *llmCacheAdapter) Set(ctx context.Context, key string, v any) error {
+	if a.ttl <= 0 {
+		return nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("failed to marshal llm cache: %w", err)
+	}
+	if err := a.client.Set(ctx, key, data, a.ttl).Err(); err != nil {
+		return fmt.Errorf("failed to set llm cache: %w", err)
+	}
+	return nil
+}
+
+// Clear キャッシュをすべて削除し、削除した件数を返す
+func (a *llmCacheAdapter) Clear(ctx context.Context) (int, error) {
+	deleted := 0
+	iter := a.client.Scan(ctx, 0, llmCacheKeyPrefix+"*", 1000).Iterator()
+	keys := make([]string, 0, 1000)
+	flush := func() error {
+		if len(keys) == 0 {
+			return nil
+		}
+		n, err := a.client.Del(ctx, keys...).Result()
+		if err != nil {
+			return fmt.Errorf("failed to delete llm cache: %w", err)
+		}
+		deleted += int(n)
+		keys = keys[:0]
+		return nil
+	}
+	for iter.Next(ctx) {
+		keys = append(keys, iter.Val())
+		if len(keys) >= 1000 {
+			if err := flush(); err != nil {
+				return deleted, err
+			}
+		}
+	}
+	if err := iter.Err(); err != nil {
+		return deleted, fmt.Errorf("failed to scan llm cache: %w", err)
+	}
+	if err := flush(); err != nil {
+		return deleted, err
+	}
+	return deleted, nil
+}
@@ -0,0 +1,32 @@
+package adapter
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLLMCacheKey(t *testing.T) {
+	key := LLMCacheKey("analyze:v1", "gpt-5-nano", "会社概要")
+	assert.Equal(t, key, LLMCacheKey("analyze:v1", "gpt-5-nano", "会社概要"))
+	assert.Regexp(t, `^llm:cache:analyze:v1:gpt-5-nano:[0-9a-f]{64}$`, key)
+
+	assert.NotEqual(t, key, LLMCacheKey("analyze:v2", "gpt-5-nano", "会社概要"))
+	assert.NotEqual(t, key, LLMCacheKey("analyze:v1", "gpt-5-mini", "会社概要"))
+	assert.NotEqual(t, key, LLMCacheKey("analyze:v1", "gpt-5-nano", "会社案内"))
+}
+
+func TestNoopLLMCacheAdapter(t *testing.T) {
+	a := NewNoopLLMCacheAdapter()
+	ctx := context.Background()
+
+	assert.NoError(t, a.Set(ctx, "k", "v"))
+	var v string
+	ok, err := a.Get(ctx, "k", &v)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+	n, err := a.Clear(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, n)
+}
@@ -0,0 +1,94 @@
+package adapter
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+const lockKeyPrefix = "lock:"
+
+// unlockScript 自分が取ったロックの場合だけ削除する
+var unlockScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+// LockAdapter 複数のインスタンスで共有するロック
+type LockAdapter interface {
+	// TryLock keyのロックを取る。他が持っている場合はfalseを返す
+	// ロックはunlockを呼ぶかttlを過ぎると外れる
+	TryLock(ctx context.Context, key string, ttl time.Duration) (unlock func(), ok bool, err error)
+}
+
+type redisLockAdapter struct {
+	client *redis.Client
+}
+
+func NewRedisLockAdapter(client *redis.Client) LockAdapter {
+	return &redisLockAdapter{
+		client: client,
+	}
+}
+
+// localLockAdapter Redisを使わない場合のロック。同じプロセスの中でだけ排他する
+type localLockAdapter struct {
+	mu   sync.Mutex
+	held map[string]string // keyごとのロックのトークン
+}
+
+func NewLocalLockAdapter() LockAdapter {
+	return &localLockAdapter{
+		held: make(map[string]string),
+	}
+}
+
+func (a *localLockAdapter) TryLock(_ context.Context, key string, ttl time.Duration) (func(), bool, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if _, ok := a.held[key]; ok {
+		return nil, false, nil
+	}
+	token := uuid.NewString()
+	a.held[key] = token
+	release := func() {
+		a.mu.Lock()
+		defer a.mu.Unlock()
+		if a.held[key] == token {
+			delete(a.held, key)
+		}
+	}
+	timer := time.AfterFunc(ttl, release)
+	return func() {
+		timer.Stop()
+		release()
+	}, true, nil
+}
+
+func (a *redisLockAdapter) TryLock(ctx context.Context, key string, ttl time.Duration) (func(), bool, error) {
+	key = lockKeyPrefix + key
+	token := uuid.NewString()
+	ok, err := a.client.SetNX(ctx, key, token, ttl).Result()
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to acquire lock: %w", err)
+	}
+	if !ok {
+		return nil, false, nil
+	}
+	unlock := func() {
+		// 処理中にctxがキャンセルされていてもロックは外す
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := unlockScript.Run(ctx, a.client, []string{key}, token).Err(); err != nil {
+			slog.Error("failed to release lock", "key", key, "error", err)
+		}
+	}
+	return unlock, true, nil
+}
@@ -0,0 +1,49 @@
+package adapter
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocalLockAdapter_TryLock(t *testing.T) {
+	a := NewLocalLockAdapter()
+	ctx := context.Background()
+
+	unlock, ok, err := a.TryLock(ctx, "fetch", time.Minute)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	_, ok, err = a.TryLock(ctx, "fetch", time.Minute)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	// 別のキーは取れる
+	unlockOther, ok, _ := a.TryLock(ctx, "reap", time.Minute)
+	assert.True(t, ok)
+	unlockOther()
+
+	unlock()
+	unlock, ok, _ = a.TryLock(ctx, "fetch", time.Minute)
+	assert.True(t, ok)
+	unlock()
+}
+
+func TestLocalLockAdapter_TryLock_Expires(t *testing.T) {
+	a := NewLocalLockAdapter()
+	ctx := context.Background()
+
+	stale, ok, _ := a.TryLock(ctx, "fetch", 10*time.Millisecond)
+	assert.True(t, ok)
+	assert.Eventually(t, func() bool {
+		_, ok, _ := a.TryLock(ctx, "fetch", time.Minute)
+		return ok
+	}, time.Second, 5*time.Millisecond)
+
+	// 期限切れのあとに外しても、ほかが取ったロックは外さない
+	stale()
+	_, ok, _ = a.TryLock(ctx, "fetch", time.Minute)
+	assert.False(t, ok)
+}
@@ -2,14 +2,13 @@ package adapter
 
 import (
 	"context"
-	"encoding/json"
 
 	"github.com/zuxt268/sales/internal/infrastructure"
 	"github.com/zuxt268/sales/internal/interfaces/dto/external"
 )
 
 type PubSubAdapter interface {
-	PushDomain(ctx context.Context, domain *external.DomainMessage) error
+	Queue
 }
 
 type pubSubAdapter struct {
@@ -24,14 +23,18 @@ func NewPubSubAdapter(
 	}
 }
 
-func (a *pubSubAdapter) PushDomain(ctx context.Context, domain *external.DomainMessage) error {
-	jsonData, err := json.Marshal(domain)
-	if err != nil {
-		return err
-	}
-	_, err = a.pubSubClient.Publish(ctx, "domain-pipeline", jsonData)
-	if err != nil {
-		return err
-	}
+// Publish JSONをそのままトピックに送る。outboxに書き込んだメッセージの送信に使う
+func (a *pubSubAdapter) Publish(ctx context.Context, topic string, data []byte) error {
+	_, err := a.pubSubClient.Publish(ctx, topic, data)
 	return err
 }
+
+// PublishPipelineMessage ドメインの処理の依頼をDomainPipelineTopicに送る
+func (a *pubSubAdapter) PublishPipelineMessage(ctx context.Context, msg *external.PipelineMessage) error {
+	return publishJSON(ctx, a, external.DomainPipelineTopic, msg)
+}
+
+// PublishDeadLetter 処理できなかったメッセージをデッドレタートピックに送る
+func (a *pubSubAdapter) PublishDeadLetter(ctx context.Context, topic string, msg *external.DeadLetterMessage) error {
+	return publishJSON(ctx, a, topic, msg)
+}
@@ -0,0 +1,24 @@
+package adapter
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/zuxt268/sales/internal/interfaces/dto/external"
+)
+
+// Queue メッセージの送信先。QUEUE_BACKEND でPub/SubかRedis Streamsを選ぶ
+type Queue interface {
+	Publish(ctx context.Context, topic string, data []byte) error
+	PublishPipelineMessage(ctx context.Context, msg *external.PipelineMessage) error
+	PublishDeadLetter(ctx context.Context, topic string, msg *external.DeadLetterMessage) error
+}
+
+func publishJSON(ctx context.Context, q Queue, topic string, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("failed to marshal message: %w", err)
+	}
+	return q.Publish(ctx, topic, data)
+}
@@ -0,0 +1,53 @@
+package adapter
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/zuxt268/sales/internal/interfaces/dto/external"
+)
+
+// RedisStreamDataField Redis Streamsのエントリでメッセージ本体を入れるフィールド
+const RedisStreamDataField = "data"
+
+// redisQueueAdapter トピックと同じ名前のRedis Streamにメッセージを追加する
+// 受信はworker.NewRedisStreamReceiverがコンシューマーグループで行う
+type redisQueueAdapter struct {
+	client *redis.Client
+	maxLen int64
+}
+
+// NewRedisQueueAdapter maxLenを超えた古いエントリは追加時におおよそで削除する。0は削除しない
+func NewRedisQueueAdapter(
+	client *redis.Client,
+	maxLen int64,
+) Queue {
+	return &redisQueueAdapter{
+		client: client,
+		maxLen: maxLen,
+	}
+}
+
+func (a *redisQueueAdapter) Publish(ctx context.Context, topic string, data []byte) error {
+	args := &redis.XAddArgs{
+		Stream: topic,
+		Values: map[string]any{RedisStreamDataField: data},
+	}
+	if a.maxLen > 0 {
+		args.MaxLen = a.maxLen
+		args.Approx = true
+	}
+	if err := a.client.XAdd(ctx, args).Err(); err != nil {
+		return fmt.Errorf("failed to add message to stream %s: %w", topic, err)
+	}
+	return nil
+}
+
+func (a *redisQueueAdapter) PublishPipelineMessage(ctx context.Context, msg *external.PipelineMessage) error {
+	return publishJSON(ctx, a, external.DomainPipelineTopic, msg)
+}
+
+func (a *redisQueueAdapter) PublishDeadLetter(ctx context.Context, topic string, msg *external.DeadLetterMessage) error {
+	return publishJSON(ctx, a, topic, msg)
+}
@@ -0,0 +1,42 @@
+package adapter
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"time"
+)
+
+// resolverTimeout 1つのホスト名の名前解決を待つ時間
+const resolverTimeout = 5 * time.Second
+
+// ResolverAdapter ホスト名をIPアドレスに名前解決する
+type ResolverAdapter interface {
+	LookupIP(ctx context.Context, host string) ([]string, error)
+}
+
+type resolverAdapter struct {
+	resolver *net.Resolver
+}
+
+func NewResolverAdapter() ResolverAdapter {
+	return &resolverAdapter{
+		resolver: net.DefaultResolver,
+	}
+}
+
+// LookupIP A・AAAAレコードのアドレス。IPv4射影アドレスはIPv4の表記にする
+func (a *resolverAdapter) LookupIP(ctx context.Context, host string) ([]string, error) {
+	ctx, cancel := context.WithTimeout(ctx, resolverTimeout)
+	defer cancel()
+
+	addrs, err := a.resolver.LookupNetIP(ctx, "ip", host)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve %s: %w", host, err)
+	}
+	ips := make([]string, 0, len(addrs))
+	for _, addr := range addrs {
+		ips = append(ips, addr.Unmap().String())
+	}
+	return ips, nil
+}
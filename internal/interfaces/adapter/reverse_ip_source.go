@@ -0,0 +1,159 @@
+package adapter
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/zuxt268/sales/internal/entity"
+	"github.com/zuxt268/sales/internal/interfaces/dto/external"
+)
+
+// viewDNSPageSize ViewDNSのReverseIPの1ページあたりの件数
+const viewDNSPageSize = 10000
+
+// viewDNSLastResolvedLayout ViewDNSの last_resolved の形式
+const viewDNSLastResolvedLayout = "2006-01-02"
+
+// ReverseIPPage 逆引きの1ページ分の結果
+type ReverseIPPage struct {
+	Domains    []ReverseIPDomain
+	TotalPages int // 0件の場合は0
+}
+
+type ReverseIPDomain struct {
+	Name         string
+	LastResolved *time.Time // 取得元が最後に名前解決を確認した日。わからない場合はnil
+}
+
+// ReverseIPSource IPアドレスからそのIPを指すドメインを引く取得元
+type ReverseIPSource interface {
+	// Lookup pageは1から。エラーの場合も含め、取得元に送ったリクエスト数（課金される呼び出し回数）も返す
+	Lookup(ctx context.Context, ip string, page int) (*ReverseIPPage, int, error)
+}
+
+// ReverseIPSources 取得元の名前（model.ReverseIPSourceViewDNS など）ごとのReverseIPSource
+type ReverseIPSources map[string]ReverseIPSource
+
+type viewDNSSource struct {
+	viewDnsAdapter ViewDNSAdapter
+	apiKey         string
+}
+
+func NewViewDNSSource(viewDnsAdapter ViewDNSAdapter, apiKey string) ReverseIPSource {
+	return &viewDNSSource{
+		viewDnsAdapter: viewDnsAdapter,
+		apiKey:         apiKey,
+	}
+}
+
+func (s *viewDNSSource) Lookup(ctx context.Context, ip string, page int) (*ReverseIPPage, int, error) {
+	resp, requests, err := s.viewDnsAdapter.GetReverseIP(ctx, &external.ReverseIpRequest{
+		Host:   ip,
+		ApiKey: s.apiKey,
+		Page:   page,
+	})
+	if err != nil {
+		return nil, requests, err
+	}
+	count, err := strconv.Atoi(resp.Response.DomainCount)
+	if err != nil {
+		return nil, requests, fmt.Errorf("failed to parse domain count (ip=%s): %v: %w", ip, err, entity.ErrExternalAPI)
+	}
+	domains := make([]ReverseIPDomain, 0, len(resp.Response.Domains))
+	for _, d := range resp.Response.Domains {
+		domain := ReverseIPDomain{Name: d.Name}
+		if t, err := time.ParseInLocation(viewDNSLastResolvedLayout, d.LastResolved, time.Local); err == nil {
+			domain.LastResolved = &t
+		}
+		domains = append(domains, domain)
+	}
+	return &ReverseIPPage{
+		Domains:    domains,
+		TotalPages: (count + viewDNSPageSize - 1) / viewDNSPageSize,
+	}, requests, nil
+}
+
+// passiveDNSFileSource dirに置いたパッシブDNSのCSV（*.csv）とゾーンファイル（*.zone, *.db, *.txt）から引く
+// ファイルはLookupのたびに読み直すため、置き換えれば次の取得から反映される。結果は1ページにまとめる
+type passiveDNSFileSource struct {
+	dir string
+}
+
+func NewPassiveDNSFileSource(dir string) ReverseIPSource {
+	return &passiveDNSFileSource{
+		dir: dir,
+	}
+}
+
+func (s *passiveDNSFileSource) Lookup(ctx context.Context, ip string, page int) (*ReverseIPPage, int, error) {
+	if page > 1 {
+		return &ReverseIPPage{Domains: []ReverseIPDomain{}, TotalPages: 1}, 0, nil
+	}
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to read passive dns dir: %w", err)
+	}
+
+	seen := make(map[string]struct{})
+	for _, e := range entries {
+		if err := ctx.Err(); err != nil {
+			return nil, 0, err
+		}
+		if e.IsDir() {
+			continue
+		}
+		names, err := s.readFile(filepath.Join(s.dir, e.Name()), ip)
+		if err != nil {
+			return nil, 0, err
+		}
+		for _, n := range names {
+			seen[n] = struct{}{}
+		}
+	}
+
+	names := make([]string, 0, len(seen))
+	for n := range seen {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	domains := make([]ReverseIPDomain, 0, len(names))
+	for _, n := range names {
+		domains = append(domains, ReverseIPDomain{Name: n})
+	}
+	totalPages := 0
+	if len(domains) > 0 {
+		totalPages = 1
+	}
+	return &ReverseIPPage{Domains: domains, TotalPages: totalPages}, 0, nil
+}
+
+func (s *passiveDNSFileSource) readFile(path, ip string) ([]string, error) {
+	var parse func(f *os.File) ([]string, error)
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		parse = func(f *os.File) ([]string, error) { return entity.ParsePassiveDNSCSV(f, ip) }
+	case ".zone", ".db", ".txt":
+		parse = func(f *os.File) ([]string, error) { return entity.ParseZoneFile(f, ip) }
+	default:
+		return nil, nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+	names, err := parse(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return names, nil
+}
@@ -0,0 +1,62 @@
+package adapter
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestViewDNSSource_Lookup(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"response":{"domain_count":"10001","domains":[{"name":"example.com","last_resolved":"2026-10-01"},{"name":"example.jp"}]}}`))
+	}))
+	defer srv.Close()
+
+	s := NewViewDNSSource(NewViewDNSAdapter(srv.URL, time.Second, 0), "key")
+	page, _, err := s.Lookup(context.Background(), "192.0.2.1", 1)
+	assert.NoError(t, err)
+	resolved := time.Date(2026, 10, 1, 0, 0, 0, 0, time.Local)
+	assert.Equal(t, []ReverseIPDomain{
+		{Name: "example.com", LastResolved: &resolved},
+		{Name: "example.jp"},
+	}, page.Domains)
+	assert.Equal(t, 2, page.TotalPages)
+}
+
+func TestPassiveDNSFileSource_Lookup(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"export.csv":   "rrname,rrtype,rdata\nexample.com.,A,192.0.2.1\nother.com,A,192.0.2.2\n",
+		"example.zone": "$ORIGIN example.jp.\n@ IN A 192.0.2.1\nwww IN A 192.0.2.1\n",
+		"README.md":    "example.org A 192.0.2.1\n",
+	}
+	for name, body := range files {
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(body), 0o644))
+	}
+	// CSVとゾーンファイルの両方にあるドメインは1つにまとめる
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "dup.txt"), []byte("example.com. IN A 192.0.2.1\n"), 0o644))
+
+	s := NewPassiveDNSFileSource(dir)
+	page, _, err := s.Lookup(context.Background(), "192.0.2.1", 1)
+	assert.NoError(t, err)
+	assert.Equal(t, []ReverseIPDomain{{Name: "example.com"}, {Name: "example.jp"}, {Name: "www.example.jp"}}, page.Domains)
+	assert.Equal(t, 1, page.TotalPages)
+
+	page, _, err = s.Lookup(context.Background(), "192.0.2.1", 2)
+	assert.NoError(t, err)
+	assert.Empty(t, page.Domains)
+
+	page, _, err = s.Lookup(context.Background(), "198.51.100.1", 1)
+	assert.NoError(t, err)
+	assert.Empty(t, page.Domains)
+	assert.Equal(t, 0, page.TotalPages)
+
+	_, _, err = NewPassiveDNSFileSource(filepath.Join(dir, "missing")).Lookup(context.Background(), "192.0.2.1", 1)
+	assert.Error(t, err)
+}
@@ -0,0 +1,152 @@
+package adapter
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/zuxt268/sales/internal/entity"
+	"github.com/zuxt268/sales/internal/interfaces/dto/external"
+)
+
+const (
+	siteHealthTimeout  = 10 * time.Second
+	sitemapMaxBytes    = 10 << 20 // 1つのサイトマップで読む上限
+	maxChildSitemaps   = 20       // サイトマップインデックスから読む子のサイトマップの上限
+	defaultTLSPort     = "443"
+	sitemapDefaultPath = "/sitemap.xml"
+)
+
+// SiteHealthAdapter サイトのSSL証明書とページ数を調べる
+type SiteHealthAdapter interface {
+	CheckTLS(ctx context.Context, host string) (*external.SiteCertificate, error)
+	CountSitemapPages(ctx context.Context, siteURL string) (int, error)
+}
+
+type siteHealthAdapter struct {
+	client  *http.Client
+	timeout time.Duration
+}
+
+func NewSiteHealthAdapter() SiteHealthAdapter {
+	return &siteHealthAdapter{
+		client: &http.Client{
+			Timeout: siteHealthTimeout,
+		},
+		timeout: siteHealthTimeout,
+	}
+}
+
+// CheckTLS TLSハンドシェイクを行い証明書を返す。ポートがない場合は443に接続する
+// 検証に通らない証明書も発行者と有効期限を記録するため、Verifiedをfalseにして返す
+func (a *siteHealthAdapter) CheckTLS(ctx context.Context, host string) (*external.SiteCertificate, error) {
+	addr := host
+	if _, _, err := net.SplitHostPort(host); err != nil {
+		addr = net.JoinHostPort(host, defaultTLSPort)
+	}
+	serverName, _, _ := net.SplitHostPort(addr)
+
+	cert, err := a.handshake(ctx, addr, &tls.Config{ServerName: serverName})
+	if err == nil {
+		return newSiteCertificate(cert, true), nil
+	}
+	var verifyErr *tls.CertificateVerificationError
+	if !errors.As(err, &verifyErr) {
+		return nil, fmt.Errorf("failed to handshake with %s: %w", addr, err)
+	}
+	if len(verifyErr.UnverifiedCertificates) > 0 {
+		return newSiteCertificate(verifyErr.UnverifiedCertificates[0], false), nil
+	}
+	cert, err = a.handshake(ctx, addr, &tls.Config{ServerName: serverName, InsecureSkipVerify: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to handshake with %s: %w", addr, err)
+	}
+	return newSiteCertificate(cert, false), nil
+}
+
+func (a *siteHealthAdapter) handshake(ctx context.Context, addr string, config *tls.Config) (*x509.Certificate, error) {
+	ctx, cancel := context.WithTimeout(ctx, a.timeout)
+	defer cancel()
+
+	dialer := &tls.Dialer{Config: config}
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = conn.Close()
+	}()
+	certs := conn.(*tls.Conn).ConnectionState().PeerCertificates
+	if len(certs) == 0 {
+		return nil, errors.New("no peer certificate")
+	}
+	return certs[0], nil
+}
+
+func newSiteCertificate(cert *x509.Certificate, verified bool) *external.SiteCertificate {
+	issuer := cert.Issuer.CommonName
+	if len(cert.Issuer.Organization) > 0 {
+		issuer = cert.Issuer.Organization[0]
+	}
+	return &external.SiteCertificate{
+		Issuer:   issuer,
+		NotAfter: cert.NotAfter,
+		Verified: verified,
+	}
+}
+
+// CountSitemapPages サイトの/sitemap.xmlに載っているページ数
+// サイトマップインデックスの場合は子のサイトマップを1階層だけ読んで合計する
+func (a *siteHealthAdapter) CountSitemapPages(ctx context.Context, siteURL string) (int, error) {
+	if !strings.Contains(siteURL, "://") {
+		siteURL = "https://" + siteURL
+	}
+	root, err := a.getSitemap(ctx, strings.TrimSuffix(siteURL, "/")+sitemapDefaultPath)
+	if err != nil {
+		return 0, err
+	}
+
+	count := root.URLs
+	for i, loc := range root.Sitemaps {
+		if i >= maxChildSitemaps {
+			break
+		}
+		child, err := a.getSitemap(ctx, loc)
+		if err != nil {
+			slog.Warn("failed to get child sitemap", "url", loc, "error", err)
+			continue
+		}
+		count += child.URLs
+	}
+	return count, nil
+}
+
+func (a *siteHealthAdapter) getSitemap(ctx context.Context, u string) (*entity.Sitemap, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s: %w", u, err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to get %s: %s", u, resp.Status)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, sitemapMaxBytes))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", u, err)
+	}
+	return entity.ParseSitemap(body)
+}
@@ -0,0 +1,66 @@
+package adapter
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSiteHealthAdapter_CheckTLS(t *testing.T) {
+	srv := httptest.NewTLSServer(http.NotFoundHandler())
+	t.Cleanup(srv.Close)
+
+	a := NewSiteHealthAdapter()
+	cert, err := a.CheckTLS(context.Background(), strings.TrimPrefix(srv.URL, "https://"))
+	assert.NoError(t, err)
+	// httptestの証明書は自己署名のため検証に通らないが、発行者と有効期限は取れる
+	assert.False(t, cert.Verified)
+	assert.Equal(t, srv.Certificate().Issuer.Organization[0], cert.Issuer)
+	assert.Equal(t, srv.Certificate().NotAfter, cert.NotAfter)
+}
+
+func TestSiteHealthAdapter_CheckTLS_NotTLS(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	t.Cleanup(srv.Close)
+
+	_, err := NewSiteHealthAdapter().CheckTLS(context.Background(), strings.TrimPrefix(srv.URL, "http://"))
+	assert.Error(t, err)
+}
+
+func TestSiteHealthAdapter_CountSitemapPages(t *testing.T) {
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/sitemap.xml":
+			_, _ = w.Write([]byte(`<sitemapindex>
+  <sitemap><loc>` + srv.URL + `/page-sitemap.xml</loc></sitemap>
+  <sitemap><loc>` + srv.URL + `/post-sitemap.xml</loc></sitemap>
+  <sitemap><loc>` + srv.URL + `/missing-sitemap.xml</loc></sitemap>
+</sitemapindex>`))
+		case "/page-sitemap.xml":
+			_, _ = w.Write([]byte(`<urlset><url><loc>/</loc></url><url><loc>/about</loc></url></urlset>`))
+		case "/post-sitemap.xml":
+			_, _ = w.Write([]byte(`<urlset><url><loc>/news/1</loc></url></urlset>`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	// 読めなかった子のサイトマップは数えない
+	n, err := NewSiteHealthAdapter().CountSitemapPages(context.Background(), srv.URL+"/")
+	assert.NoError(t, err)
+	assert.Equal(t, 3, n)
+}
+
+func TestSiteHealthAdapter_CountSitemapPages_NotFound(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	t.Cleanup(srv.Close)
+
+	_, err := NewSiteHealthAdapter().CountSitemapPages(context.Background(), srv.URL)
+	assert.Error(t, err)
+}
@@ -3,55 +3,200 @@ package adapter
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"log/slog"
+	"net"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/zuxt268/sales/internal/entity"
 	"github.com/zuxt268/sales/internal/interfaces/dto/external"
 )
 
+const (
+	// viewDNSRetryWait 再試行の最初の待ち時間。以降は倍々に延ばす
+	viewDNSRetryWait = time.Second
+	// viewDNSMaxRetryWait Retry-After を含めた待ち時間の上限
+	viewDNSMaxRetryWait = time.Minute
+)
+
+// viewDNSQuotaErrors 本文のエラーのうち、呼び出し回数の上限やクレジットの不足を表す文言（小文字で比較する）
+// レート制限やページ数の上限のエラーで取得を止めないよう、上限・クレジットについての言い回しだけに一致させる
+var viewDNSQuotaErrors = []string{
+	"query limit reached",
+	"exceeded your query limit",
+	"exceeded your daily query limit",
+	"exceeded your monthly query limit",
+	"out of credits",
+	"insufficient credits",
+	"no credits remaining",
+}
+
+// isViewDNSQuotaError 本文のエラーが呼び出し回数の上限に達したことを表すか
+func isViewDNSQuotaError(msg string) bool {
+	msg = strings.ToLower(msg)
+	for _, q := range viewDNSQuotaErrors {
+		if strings.Contains(msg, q) {
+			return true
+		}
+	}
+	return false
+}
+
 type ViewDNSAdapter interface {
-	GetReverseIP(ctx context.Context, req *external.ReverseIpRequest) (*external.ReverseIpResponse, error)
+	GetReverseIP(ctx context.Context, req *external.ReverseIpRequest) (*external.ReverseIpResponse, int, error)
 }
 
 type viewDNSAdapter struct {
-	baseURL string
+	baseURL    string
+	client     *http.Client
+	timeout    time.Duration
+	maxRetries int
+	retryWait  time.Duration
 }
 
-func NewViewDNSAdapter(baseURL string) ViewDNSAdapter {
+// NewViewDNSAdapter timeoutは1回のリクエストの制限時間、maxRetriesは5xx・429・通信エラーの再試行回数
+func NewViewDNSAdapter(baseURL string, timeout time.Duration, maxRetries int) ViewDNSAdapter {
 	return &viewDNSAdapter{
-		baseURL: baseURL,
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		client:     &http.Client{},
+		timeout:    timeout,
+		maxRetries: max(maxRetries, 0),
+		retryWait:  viewDNSRetryWait,
 	}
 }
 
-func (r *viewDNSAdapter) GetReverseIP(ctx context.Context, params *external.ReverseIpRequest) (*external.ReverseIpResponse, error) {
-	url := fmt.Sprintf("%s/reverseip/?host=%s&apikey=%s", r.baseURL, params.Host, params.ApiKey)
-
+// GetReverseIP 再試行を含めて送ったリクエスト数も返す。ViewDNSは失敗したリクエストも呼び出し回数に数える
+// エラーは entity.ErrTimeout か entity.ErrExternalAPI、上限に達した場合は entity.ErrViewDNSQuotaExceeded をラップして返す
+// APIキーはエラーに含めない
+func (r *viewDNSAdapter) GetReverseIP(ctx context.Context, params *external.ReverseIpRequest) (*external.ReverseIpResponse, int, error) {
+	query := url.Values{}
+	query.Set("host", params.Host)
+	query.Set("apikey", params.ApiKey)
 	if params.Page != 0 {
-		url = fmt.Sprintf("%s&page=%d", url, params.Page)
+		query.Set("page", strconv.Itoa(params.Page))
+	}
+	reqURL := r.baseURL + "/reverseip/?" + query.Encode()
+
+	for attempt := 0; ; attempt++ {
+		resp, retry, err := r.getReverseIP(ctx, reqURL)
+		if err == nil {
+			return resp, attempt + 1, nil
+		}
+		err = redactAPIKey(err, params.ApiKey)
+		if !retry.ok || attempt >= r.maxRetries || ctx.Err() != nil {
+			return nil, attempt + 1, fmt.Errorf("failed to get reverse ip (host=%s, page=%d): %w", params.Host, params.Page, err)
+		}
+
+		wait := r.retryWait << attempt
+		if retry.after > 0 {
+			wait = retry.after
+		}
+		wait = min(wait, viewDNSMaxRetryWait)
+		slog.Warn("retrying viewdns request", "host", params.Host, "page", params.Page, "attempt", attempt+1, "wait", wait, "error", err)
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+			return nil, attempt + 1, fmt.Errorf("failed to get reverse ip (host=%s, page=%d): %w", params.Host, params.Page, err)
+		}
+	}
+}
+
+// viewDNSRetry 再試行してよいかと、Retry-After で指定された待ち時間
+type viewDNSRetry struct {
+	ok    bool
+	after time.Duration
+}
+
+func (r *viewDNSAdapter) getReverseIP(ctx context.Context, reqURL string) (*external.ReverseIpResponse, viewDNSRetry, error) {
+	if r.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.timeout)
+		defer cancel()
 	}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		return nil, viewDNSRetry{}, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	resp, err := r.client.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to send request: %w", err)
+		var netErr net.Error
+		if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()) {
+			return nil, viewDNSRetry{ok: true}, fmt.Errorf("request timed out: %w: %w", err, entity.ErrTimeout)
+		}
+		return nil, viewDNSRetry{ok: true}, fmt.Errorf("failed to send request: %w: %w", err, entity.ErrExternalAPI)
 	}
 	defer func() {
 		_ = resp.Body.Close()
 	}()
 
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError {
+		retry := viewDNSRetry{ok: true, after: parseRetryAfter(resp.Header.Get("Retry-After"))}
+		return nil, retry, fmt.Errorf("unexpected status %s: %w", resp.Status, entity.ErrExternalAPI)
+	}
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("failed to fetch reverse ip address: %s", resp.Status)
+		return nil, viewDNSRetry{}, fmt.Errorf("unexpected status %s: %w", resp.Status, entity.ErrExternalAPI)
 	}
 
 	var response external.ReverseIpResponse
 	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
-		return nil, fmt.Errorf("failed to decode reverse ip response: %w", err)
+		if errors.Is(err, context.DeadlineExceeded) {
+			return nil, viewDNSRetry{ok: true}, fmt.Errorf("reading response timed out: %w: %w", err, entity.ErrTimeout)
+		}
+		return nil, viewDNSRetry{}, fmt.Errorf("failed to decode reverse ip response: %v: %w", err, entity.ErrExternalAPI)
 	}
+	// ViewDNSはAPIキーの誤りや上限超過も200で返し、本文にエラーを入れる
+	if msg := response.ErrorMessage(); msg != "" {
+		if isViewDNSQuotaError(msg) {
+			return nil, viewDNSRetry{}, fmt.Errorf("viewdns error: %s: %w", msg, entity.ErrViewDNSQuotaExceeded)
+		}
+		return nil, viewDNSRetry{}, fmt.Errorf("viewdns error: %s: %w", msg, entity.ErrExternalAPI)
+	}
+	return &response, viewDNSRetry{}, nil
+}
 
-	return &response, nil
+// parseRetryAfter 秒数で指定された Retry-After を読む。日時の形式や不正な値は0
+func parseRetryAfter(v string) time.Duration {
+	sec, err := strconv.Atoi(strings.TrimSpace(v))
+	if err != nil || sec < 0 {
+		return 0
+	}
+	return time.Duration(sec) * time.Second
 }
+
+// redactAPIKey エラーに含まれるAPIキーを伏せる
+// http.Client のエラーはURLをそのまま含むため、url.Error のURLも書き換える
+func redactAPIKey(err error, apiKey string) error {
+	if apiKey == "" {
+		return err
+	}
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		urlErr.URL = redact(urlErr.URL, apiKey)
+	}
+	msg := err.Error()
+	if redacted := redact(msg, apiKey); redacted != msg {
+		return &redactedError{msg: redacted, err: err}
+	}
+	return err
+}
+
+func redact(s, apiKey string) string {
+	s = strings.ReplaceAll(s, url.QueryEscape(apiKey), "REDACTED")
+	return strings.ReplaceAll(s, apiKey, "REDACTED")
+}
+
+// redactedError メッセージだけを差し替え、errors.Is で元のエラーを辿れるようにする
+type redactedError struct {
+	msg string
+	err error
+}
+
+func (e *redactedError) Error() string { return e.msg }
+func (e *redactedError) Unwrap() error { return e.err }
@@ -0,0 +1,210 @@
+package adapter
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zuxt268/sales/internal/entity"
+	"github.com/zuxt268/sales/internal/interfaces/dto/external"
+)
+
+const testViewDNSKey = "k&ey=s3cret"
+
+const reverseIPBody = `{
+  "query": {"tool": "reverseip_PRO", "host": "192.0.2.1"},
+  "response": {"domain_count": "2", "domains": [
+    {"name": "example.com", "last_resolved": "2026-10-01"},
+    {"name": "example.jp", "last_resolved": "2026-10-02"}
+  ]}
+}`
+
+// newViewDNSStandIn handleで応答するViewDNSの代わり。nは何回目のリクエストか
+func newViewDNSStandIn(t *testing.T, calls *atomic.Int32, handle func(w http.ResponseWriter, r *http.Request, n int)) *viewDNSAdapter {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := int(calls.Add(1))
+		handle(w, r, n)
+	}))
+	t.Cleanup(srv.Close)
+
+	a := NewViewDNSAdapter(srv.URL, time.Second, 2).(*viewDNSAdapter)
+	a.retryWait = time.Millisecond
+	return a
+}
+
+func reverseIPRequest() *external.ReverseIpRequest {
+	return &external.ReverseIpRequest{Host: "192.0.2.1", ApiKey: testViewDNSKey, Page: 2}
+}
+
+func TestViewDNSAdapter_GetReverseIP(t *testing.T) {
+	var calls atomic.Int32
+	a := newViewDNSStandIn(t, &calls, func(w http.ResponseWriter, r *http.Request, _ int) {
+		assert.Equal(t, "/reverseip/", r.URL.Path)
+		assert.Equal(t, "192.0.2.1", r.URL.Query().Get("host"))
+		assert.Equal(t, testViewDNSKey, r.URL.Query().Get("apikey"))
+		assert.Equal(t, "2", r.URL.Query().Get("page"))
+		_, _ = w.Write([]byte(reverseIPBody))
+	})
+
+	resp, requests, err := a.GetReverseIP(context.Background(), reverseIPRequest())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, requests)
+	assert.Equal(t, "2", resp.Response.DomainCount)
+	assert.Len(t, resp.Response.Domains, 2)
+	assert.Equal(t, "example.com", resp.Response.Domains[0].Name)
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestViewDNSAdapter_GetReverseIP_Retry(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+	}{
+		{"server error", http.StatusServiceUnavailable},
+		{"too many requests", http.StatusTooManyRequests},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls atomic.Int32
+			a := newViewDNSStandIn(t, &calls, func(w http.ResponseWriter, _ *http.Request, n int) {
+				if n < 3 {
+					w.Header().Set("Retry-After", "0")
+					w.WriteHeader(tt.status)
+					return
+				}
+				_, _ = w.Write([]byte(reverseIPBody))
+			})
+
+			resp, requests, err := a.GetReverseIP(context.Background(), reverseIPRequest())
+			assert.NoError(t, err)
+			assert.Len(t, resp.Response.Domains, 2)
+			assert.Equal(t, int32(3), calls.Load())
+			assert.Equal(t, 3, requests)
+		})
+	}
+}
+
+func TestViewDNSAdapter_GetReverseIP_Errors(t *testing.T) {
+	tests := []struct {
+		name      string
+		handle    func(w http.ResponseWriter, r *http.Request, n int)
+		wantErr   error
+		wantCalls int32
+	}{
+		{
+			name: "retries exhausted",
+			handle: func(w http.ResponseWriter, _ *http.Request, _ int) {
+				w.WriteHeader(http.StatusBadGateway)
+			},
+			wantErr:   entity.ErrExternalAPI,
+			wantCalls: 3,
+		},
+		{
+			name: "client error is not retried",
+			handle: func(w http.ResponseWriter, _ *http.Request, _ int) {
+				w.WriteHeader(http.StatusBadRequest)
+			},
+			wantErr:   entity.ErrExternalAPI,
+			wantCalls: 1,
+		},
+		{
+			name: "error in body",
+			handle: func(w http.ResponseWriter, _ *http.Request, _ int) {
+				_, _ = w.Write([]byte(`{"query":{"tool":"reverseip_PRO","host":"192.0.2.1"},"response":{"error":"Invalid API key"}}`))
+			},
+			wantErr:   entity.ErrExternalAPI,
+			wantCalls: 1,
+		},
+		{
+			name: "top level error in body",
+			handle: func(w http.ResponseWriter, _ *http.Request, _ int) {
+				_, _ = w.Write([]byte(`{"error":"Service temporarily unavailable"}`))
+			},
+			wantErr:   entity.ErrExternalAPI,
+			wantCalls: 1,
+		},
+		{
+			name: "quota error in body",
+			handle: func(w http.ResponseWriter, _ *http.Request, _ int) {
+				_, _ = w.Write([]byte(`{"query":{"tool":"reverseip_PRO","host":"192.0.2.1"},"response":{"error":"You have exceeded your daily query limit"}}`))
+			},
+			wantErr:   entity.ErrViewDNSQuotaExceeded,
+			wantCalls: 1,
+		},
+		{
+			name: "credit error in body",
+			handle: func(w http.ResponseWriter, _ *http.Request, _ int) {
+				_, _ = w.Write([]byte(`{"error":"You have run out of credits"}`))
+			},
+			wantErr:   entity.ErrViewDNSQuotaExceeded,
+			wantCalls: 1,
+		},
+		{
+			// 上限に関係のない limit を含むエラーで取得を止めない
+			name: "rate limit error in body is not a quota error",
+			handle: func(w http.ResponseWriter, _ *http.Request, _ int) {
+				_, _ = w.Write([]byte(`{"error":"Rate limit exceeded, page limit is 10"}`))
+			},
+			wantErr:   entity.ErrExternalAPI,
+			wantCalls: 1,
+		},
+		{
+			name: "broken json",
+			handle: func(w http.ResponseWriter, _ *http.Request, _ int) {
+				_, _ = w.Write([]byte(`<html>maintenance</html>`))
+			},
+			wantErr:   entity.ErrExternalAPI,
+			wantCalls: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls atomic.Int32
+			a := newViewDNSStandIn(t, &calls, tt.handle)
+
+			_, requests, err := a.GetReverseIP(context.Background(), reverseIPRequest())
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.wantCalls, calls.Load())
+			assert.Equal(t, int(tt.wantCalls), requests)
+		})
+	}
+}
+
+func TestViewDNSAdapter_GetReverseIP_Timeout(t *testing.T) {
+	var calls atomic.Int32
+	a := newViewDNSStandIn(t, &calls, func(w http.ResponseWriter, r *http.Request, _ int) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+	})
+	a.timeout = 20 * time.Millisecond
+
+	_, _, err := a.GetReverseIP(context.Background(), reverseIPRequest())
+	assert.ErrorIs(t, err, entity.ErrTimeout)
+	assert.Equal(t, int32(3), calls.Load())
+	// http.Client のエラーはURLを含むが、APIキーは伏せる
+	assert.NotContains(t, err.Error(), "s3cret")
+	assert.Contains(t, err.Error(), "REDACTED")
+}
+
+func TestViewDNSAdapter_GetReverseIP_Canceled(t *testing.T) {
+	var calls atomic.Int32
+	a := newViewDNSStandIn(t, &calls, func(w http.ResponseWriter, _ *http.Request, _ int) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+	a.retryWait = time.Hour
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, _, err := a.GetReverseIP(ctx, reverseIPRequest())
+	assert.ErrorIs(t, err, entity.ErrExternalAPI)
+	assert.Less(t, time.Since(start), time.Second)
+	assert.Equal(t, int32(1), calls.Load())
+}
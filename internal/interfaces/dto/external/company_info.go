@@ -0,0 +1,6 @@
+package external
+
+type CompanyInfo struct {
+	Text string
+	URLs []string
+}
@@ -0,0 +1,11 @@
+package external
+
+import "time"
+
+// LLMUsage LLM呼び出し1回分の使用量
+type LLMUsage struct {
+	Model            string
+	PromptTokens     int
+	CompletionTokens int
+	Latency          time.Duration
+}
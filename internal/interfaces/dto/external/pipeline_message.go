@@ -0,0 +1,94 @@
+package external
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// DomainPipelineTopic ドメインの処理を依頼するトピック
+const DomainPipelineTopic = "domain-pipeline"
+
+// PipelineMessageVersion 現在のメッセージの形式。形式を変えたら上げる
+// 0は domain_id だけを持つ以前のメッセージ
+const PipelineMessageVersion = 1
+
+// PipelineMessageType ドメインに対して依頼する処理の種類
+type PipelineMessageType string
+
+const (
+	PipelineCheckView  PipelineMessageType = "check_view"
+	PipelineCheckJapan PipelineMessageType = "check_japan"
+	PipelineCheckWix   PipelineMessageType = "check_wix"
+	PipelineCrawl      PipelineMessageType = "crawl"
+	PipelineAnalyze    PipelineMessageType = "analyze"
+	PipelineExport     PipelineMessageType = "export"
+)
+
+// PipelineMessageTypes 有効な処理の種類
+var PipelineMessageTypes = []PipelineMessageType{
+	PipelineCheckView,
+	PipelineCheckJapan,
+	PipelineCheckWix,
+	PipelineCrawl,
+	PipelineAnalyze,
+	PipelineExport,
+}
+
+// PipelineMessage ドメインの処理を依頼するメッセージ
+type PipelineMessage struct {
+	Version    int                 `json:"version"`
+	MessageID  string              `json:"message_id,omitempty"` // 受信側の重複排除に使う
+	Type       PipelineMessageType `json:"type"`
+	DomainID   int                 `json:"domain_id"`
+	Attempt    int                 `json:"attempt,omitempty"` // 再送の場合は何回目か
+	TraceID    string              `json:"trace_id,omitempty"`
+	EnqueuedAt time.Time           `json:"enqueued_at"`
+}
+
+// DecodePipelineMessage JSONをPipelineMessageにする
+// typeのない以前のメッセージはanalyzeとして扱う。対応していない形式や種類の場合はエラー
+func DecodePipelineMessage(data []byte) (*PipelineMessage, error) {
+	var msg PipelineMessage
+	if err := json.Unmarshal(data, &msg); err != nil {
+		return nil, fmt.Errorf("invalid pipeline message: %w", err)
+	}
+	if msg.Version > PipelineMessageVersion {
+		return nil, fmt.Errorf("unsupported pipeline message version %d", msg.Version)
+	}
+	if msg.Type == "" {
+		msg.Type = PipelineAnalyze
+	}
+	if !IsPipelineMessageType(msg.Type) {
+		return nil, fmt.Errorf("unknown pipeline message type %q", msg.Type)
+	}
+	if msg.DomainID <= 0 {
+		return nil, fmt.Errorf("invalid domain_id %d", msg.DomainID)
+	}
+	return &msg, nil
+}
+
+func IsPipelineMessageType(t PipelineMessageType) bool {
+	for _, v := range PipelineMessageTypes {
+		if t == v {
+			return true
+		}
+	}
+	return false
+}
+
+type PubSubPushRequest struct {
+	Message struct {
+		Data []byte `json:"data"` // ← ここを []byte にする
+	} `json:"message"`
+}
+
+// DeadLetterMessage 処理できなかったメッセージ。元のデータと失敗した理由をデッドレタートピックに送る
+type DeadLetterMessage struct {
+	MessageID       string    `json:"message_id"`
+	Type            string    `json:"type"`
+	Data            []byte    `json:"data"`
+	Error           string    `json:"error"`
+	DeliveryAttempt int       `json:"delivery_attempt"`
+	FailedAt        time.Time `json:"failed_at"`
+}
@@ -0,0 +1,33 @@
+package external
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecodePipelineMessage(t *testing.T) {
+	msg, err := DecodePipelineMessage([]byte(`{"version":1,"message_id":"m1","type":"check_japan","domain_id":3,"attempt":2,"trace_id":"t1","enqueued_at":"2026-10-18T12:00:00+09:00"}`))
+	assert.NoError(t, err)
+	assert.Equal(t, PipelineCheckJapan, msg.Type)
+	assert.Equal(t, 3, msg.DomainID)
+	assert.Equal(t, 2, msg.Attempt)
+	assert.Equal(t, "t1", msg.TraceID)
+
+	// 以前のメッセージはanalyzeとして扱う
+	msg, err = DecodePipelineMessage([]byte(`{"domain_id":5}`))
+	assert.NoError(t, err)
+	assert.Equal(t, 0, msg.Version)
+	assert.Equal(t, PipelineAnalyze, msg.Type)
+	assert.Equal(t, 5, msg.DomainID)
+
+	for _, data := range []string{
+		`not json`,
+		`{"version":2,"type":"analyze","domain_id":1}`,
+		`{"version":1,"type":"unknown","domain_id":1}`,
+		`{"version":1,"type":"analyze"}`,
+	} {
+		_, err := DecodePipelineMessage([]byte(data))
+		assert.Error(t, err, data)
+	}
+}
@@ -17,5 +17,15 @@ type ReverseIpResponse struct {
 			Name         string `json:"name"`
 			LastResolved string `json:"last_resolved"`
 		} `json:"domains"`
+		Error string `json:"error"`
 	} `json:"response"`
+	Error string `json:"error"`
+}
+
+// ErrorMessage ViewDNSが本文で返したエラー。エラーでなければ空
+func (r *ReverseIpResponse) ErrorMessage() string {
+	if r.Error != "" {
+		return r.Error
+	}
+	return r.Response.Error
 }
@@ -0,0 +1,11 @@
+package external
+
+import "time"
+
+// SiteCertificate TLSハンドシェイクで受け取ったサーバー証明書
+// Verifiedは証明書チェーンとホスト名の検証に通ったかどうか
+type SiteCertificate struct {
+	Issuer   string
+	NotAfter time.Time
+	Verified bool
+}
@@ -0,0 +1,10 @@
+package external
+
+import "net/http"
+
+// SitePage 取得したページ。URLはリダイレクト後のもの
+type SitePage struct {
+	URL    string
+	Header http.Header
+	HTML   string
+}
@@ -16,8 +16,13 @@ type GetDomains struct {
 	MobilePhone   *string       `query:"mobile_phone"`
 	LandlinePhone *string       `query:"landline_phone"`
 	Industry      *string       `query:"industry"`
+	IndustryCode  *string       `query:"industry_code"`
 	Prefecture    *string       `query:"prefecture"`
 	IsSSL         *bool         `query:"is_ssl"`
+	MovedAway     *bool         `query:"moved_away"`
+	Platform      *string       `query:"platform"`
+	CMS           *string       `query:"cms"`
+	HostingIP     *string       `query:"hosting_ip"`
 	Status        *model.Status `query:"status"`
 }
 
@@ -64,3 +69,7 @@ type CreateDomain struct {
 	PageNum       int          `json:"page_num"`
 	Status        model.Status `json:"status"`
 }
+
+type GetDomainStatusHistories struct {
+	Pagination
+}
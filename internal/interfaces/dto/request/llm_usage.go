@@ -0,0 +1,9 @@
+package request
+
+import "github.com/zuxt268/sales/internal/model"
+
+type GetLLMUsage struct {
+	From    *string           `query:"from"`
+	To      *string           `query:"to"`
+	Feature *model.LLMFeature `query:"feature"`
+}
@@ -0,0 +1,11 @@
+package request
+
+// UpdateSchedule 指定した項目だけ変更する。pausedをfalseにすると再開する
+type UpdateSchedule struct {
+	CronExpr *string `json:"cron_expr"`
+	Paused   *bool   `json:"paused"`
+}
+
+type GetScheduleRuns struct {
+	Pagination
+}
@@ -0,0 +1,97 @@
+package request
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/zuxt268/sales/internal/entity"
+)
+
+type ImportTargets struct {
+	Targets []ImportTarget `json:"targets"`
+}
+
+// ImportTarget 取り込む1行。ValueはIPアドレス・ホスト名・CIDRのいずれか
+type ImportTarget struct {
+	Value    string `json:"value"`
+	Name     string `json:"name"`     // 省略時はIPアドレス
+	Source   string `json:"source"`   // viewdns（省略時）, passive_dns
+	Platform string `json:"platform"` // generic（省略時）, wix
+	Weight   *int   `json:"weight"`   // 省略時は1
+}
+
+// CSVで値の列として読む列名
+var importTargetValueColumns = []string{"value", "ip", "host", "hostname", "cidr", "address"}
+
+// ParseImportTargetsCSV 1行目が列名のCSVを読む
+// 値の列（value / ip / host / hostname / cidr / address）が必要で、name・source・platform・weight の列は任意
+func ParseImportTargetsCSV(r io.Reader) (*ImportTargets, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+
+	header, err := cr.Read()
+	if errors.Is(err, io.EOF) {
+		return &ImportTargets{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read csv header: %v: %w", err, entity.ErrValidation)
+	}
+	cols := map[string]int{"value": -1, "name": -1, "source": -1, "platform": -1, "weight": -1}
+	for i, h := range header {
+		h = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(h, "\ufeff")))
+		for _, c := range importTargetValueColumns {
+			if h == c && cols["value"] < 0 {
+				cols["value"] = i
+			}
+		}
+		if _, ok := cols[h]; ok && h != "value" {
+			cols[h] = i
+		}
+	}
+	if cols["value"] < 0 {
+		return nil, fmt.Errorf("csv must have a value column (%s): %w", strings.Join(importTargetValueColumns, ", "), entity.ErrValidation)
+	}
+
+	req := &ImportTargets{}
+	for line := 2; ; line++ {
+		rec, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read csv: %v: %w", err, entity.ErrValidation)
+		}
+		field := func(name string) string {
+			if i := cols[name]; i >= 0 && i < len(rec) {
+				return strings.TrimSpace(rec[i])
+			}
+			return ""
+		}
+		t := ImportTarget{
+			Value:    field("value"),
+			Name:     field("name"),
+			Source:   field("source"),
+			Platform: field("platform"),
+		}
+		if w := field("weight"); w != "" {
+			n, err := strconv.Atoi(w)
+			if err != nil {
+				return nil, fmt.Errorf("invalid weight %q at line %d: %w", w, line, entity.ErrValidation)
+			}
+			t.Weight = &n
+		}
+		req.Targets = append(req.Targets, t)
+	}
+	return req, nil
+}
+
+type GetTargetStats struct {
+	Limit  *int `query:"limit"`
+	Offset *int `query:"offset"`
+	Scans  *int `query:"scans"` // ターゲットごとに返す周回の記録の数（既定10、最大100）
+}
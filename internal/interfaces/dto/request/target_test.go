@@ -0,0 +1,34 @@
+package request
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zuxt268/sales/internal/entity"
+)
+
+func TestParseImportTargetsCSV(t *testing.T) {
+	req, err := ParseImportTargetsCSV(strings.NewReader(
+		"\ufeffIP,Name,Weight\n" +
+			"192.0.2.1,hosting-a,3\n" +
+			"198.51.100.0/30,,\n" +
+			"example.com\n"))
+	assert.NoError(t, err)
+	three := 3
+	assert.Equal(t, []ImportTarget{
+		{Value: "192.0.2.1", Name: "hosting-a", Weight: &three},
+		{Value: "198.51.100.0/30"},
+		{Value: "example.com"},
+	}, req.Targets)
+
+	req, err = ParseImportTargetsCSV(strings.NewReader(""))
+	assert.NoError(t, err)
+	assert.Empty(t, req.Targets)
+
+	_, err = ParseImportTargetsCSV(strings.NewReader("name,weight\nfoo,1\n"))
+	assert.ErrorIs(t, err, entity.ErrValidation)
+
+	_, err = ParseImportTargetsCSV(strings.NewReader("value,weight\n192.0.2.1,many\n"))
+	assert.ErrorIs(t, err, entity.ErrValidation)
+}
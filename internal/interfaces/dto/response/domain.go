@@ -7,28 +7,43 @@ import (
 )
 
 type Domain struct {
-	ID            int          `json:"id"`
-	Name          string       `json:"name"`
-	Target        string       `json:"target"`
-	CanView       bool         `json:"can_view"`
-	IsJapan       bool         `json:"is_japan"`
-	IsSend        bool         `json:"is_send"`
-	Title         string       `json:"title"`
-	OwnerID       string       `json:"owner_id"`
-	Address       string       `json:"address"`
-	Phone         string       `json:"phone"`
-	MobilePhone   string       `json:"mobile_phone"`
-	LandlinePhone string       `json:"landline_phone"`
-	Industry      string       `json:"industry"`
-	President     string       `json:"president"`
-	Company       string       `json:"company"`
-	Prefecture    string       `json:"prefecture"`
-	IsSSL         bool         `json:"is_ssl"`
-	RawPage       string       `json:"raw_page"`
-	PageNum       int          `json:"page_num"`
-	Status        model.Status `json:"status"`
-	UpdatedAt     time.Time    `json:"updated_at"`
-	CreatedAt     time.Time    `json:"created_at"`
+	ID              int          `json:"id"`
+	Name            string       `json:"name"`
+	Target          string       `json:"target"`
+	Source          string       `json:"source"`
+	Platform        string       `json:"platform"`
+	MovedAwayAt     *time.Time   `json:"moved_away_at"` // すべてのターゲットで見つからなくなった日時
+	CMS             string       `json:"cms"`
+	CMSVersion      string       `json:"cms_version"`
+	HostingIP       string       `json:"hosting_ip"`
+	FingerprintedAt *time.Time   `json:"fingerprinted_at"` // CMSを判定した日時
+	CanView         bool         `json:"can_view"`
+	IsJapan         bool         `json:"is_japan"`
+	IsSend          bool         `json:"is_send"`
+	Title           string       `json:"title"`
+	OwnerID         string       `json:"owner_id"`
+	Address         string       `json:"address"`
+	Phone           string       `json:"phone"`
+	MobilePhone     string       `json:"mobile_phone"`
+	LandlinePhone   string       `json:"landline_phone"`
+	Industry        string       `json:"industry"`
+	IndustryCodes   []string     `json:"industry_codes"`
+	President       string       `json:"president"`
+	Company         string       `json:"company"`
+	Prefecture      string       `json:"prefecture"`
+	IsSSL           bool         `json:"is_ssl"`
+	SSLIssuer       string       `json:"ssl_issuer"`
+	SSLExpiresAt    *time.Time   `json:"ssl_expires_at"`
+	RedirectURL     string       `json:"redirect_url"` // ほかのサイトにリダイレクトしている場合の転送先
+	IsParked        bool         `json:"is_parked"`
+	HealthCheckedAt *time.Time   `json:"health_checked_at"` // サイトの死活・SSLを確認した日時
+	RawPage         string       `json:"raw_page"`
+	PageNum         int          `json:"page_num"`
+	CrawledURLs     []string     `json:"crawled_urls"`
+	ReviewReason    string       `json:"review_reason"`
+	Status          model.Status `json:"status"`
+	UpdatedAt       time.Time    `json:"updated_at"`
+	CreatedAt       time.Time    `json:"created_at"`
 }
 
 type Domains struct {
@@ -36,37 +51,57 @@ type Domains struct {
 	Paginate
 }
 
-func GetDomain(d *model.Domain) *Domain {
+// GetDomain industryCodesはdomain_industriesに紐付いた業種コード
+func GetDomain(d *model.Domain, industryCodes []string) *Domain {
+	if industryCodes == nil {
+		industryCodes = []string{}
+	}
 	return &Domain{
-		ID:            d.ID,
-		Name:          d.Name,
-		Target:        d.Target,
-		CanView:       d.CanView,
-		IsJapan:       d.IsJapan,
-		IsSend:        d.IsSend,
-		Title:         d.Title,
-		OwnerID:       d.OwnerID,
-		Address:       d.Address,
-		Phone:         d.Phone,
-		MobilePhone:   d.MobilePhone,
-		LandlinePhone: d.LandlinePhone,
-		Industry:      d.Industry,
-		President:     d.President,
-		Company:       d.Company,
-		Prefecture:    d.Prefecture,
-		IsSSL:         d.IsSSL,
-		RawPage:       d.RawPage,
-		PageNum:       d.PageNum,
-		Status:        d.Status,
-		UpdatedAt:     d.UpdatedAt,
-		CreatedAt:     d.CreatedAt,
+		ID:              d.ID,
+		Name:            d.Name,
+		Target:          d.Target,
+		Source:          d.Source,
+		Platform:        d.Platform,
+		MovedAwayAt:     d.MovedAwayAt,
+		CMS:             d.CMS,
+		CMSVersion:      d.CMSVersion,
+		HostingIP:       d.HostingIP,
+		FingerprintedAt: d.FingerprintedAt,
+		CanView:         d.CanView,
+		IsJapan:         d.IsJapan,
+		IsSend:          d.IsSend,
+		Title:           d.Title,
+		OwnerID:         d.OwnerID,
+		Address:         d.Address,
+		Phone:           d.Phone,
+		MobilePhone:     d.MobilePhone,
+		LandlinePhone:   d.LandlinePhone,
+		Industry:        d.Industry,
+		IndustryCodes:   industryCodes,
+		President:       d.President,
+		Company:         d.Company,
+		Prefecture:      d.Prefecture,
+		IsSSL:           d.IsSSL,
+		SSLIssuer:       d.SSLIssuer,
+		SSLExpiresAt:    d.SSLExpiresAt,
+		RedirectURL:     d.RedirectURL,
+		IsParked:        d.IsParked,
+		HealthCheckedAt: d.HealthCheckedAt,
+		RawPage:         d.RawPage,
+		PageNum:         d.PageNum,
+		CrawledURLs:     d.GetCrawledURLs(),
+		ReviewReason:    d.ReviewReason,
+		Status:          d.Status,
+		UpdatedAt:       d.UpdatedAt,
+		CreatedAt:       d.CreatedAt,
 	}
 }
 
-func GetDomains(domains []*model.Domain, total int64) *Domains {
+// GetDomains industryCodesはドメインのIDごとの業種コード
+func GetDomains(domains []*model.Domain, industryCodes map[int][]string, total int64) *Domains {
 	resDomains := make([]*Domain, 0, len(domains))
 	for _, d := range domains {
-		resDomains = append(resDomains, GetDomain(d))
+		resDomains = append(resDomains, GetDomain(d, industryCodes[d.ID]))
 	}
 	return &Domains{
 		Domains: resDomains,
@@ -76,3 +111,44 @@ func GetDomains(domains []*model.Domain, total int64) *Domains {
 		},
 	}
 }
+
+type DomainStatusHistory struct {
+	ID         int          `json:"id"`
+	FromStatus model.Status `json:"from_status"`
+	ToStatus   model.Status `json:"to_status"`
+	Actor      string       `json:"actor"`
+	Reason     string       `json:"reason"`
+	CreatedAt  time.Time    `json:"created_at"`
+}
+
+func GetDomainStatusHistories(histories []*model.DomainStatusHistory) []*DomainStatusHistory {
+	res := make([]*DomainStatusHistory, 0, len(histories))
+	for _, h := range histories {
+		res = append(res, &DomainStatusHistory{
+			ID:         h.ID,
+			FromStatus: h.FromStatus,
+			ToStatus:   h.ToStatus,
+			Actor:      h.Actor,
+			Reason:     h.Reason,
+			CreatedAt:  h.CreatedAt,
+		})
+	}
+	return res
+}
+
+type ReapDomains struct {
+	Retried   int `json:"retried"`   // 再送したドメイン数
+	Abandoned int `json:"abandoned"` // 再送回数の上限に達してunknownにしたドメイン数
+}
+
+type RelayOutbox struct {
+	Sent     int `json:"sent"`     // 送信したメッセージ数
+	Retrying int `json:"retrying"` // 送信に失敗して再送を待つメッセージ数
+	Failed   int `json:"failed"`   // 再送回数の上限に達したメッセージ数
+}
+
+type Fingerprint struct {
+	Processed int            `json:"processed"` // 判定したドメイン数
+	Failed    int            `json:"failed"`    // 保存できなかったドメイン数。次の実行でまた判定する
+	Counts    map[string]int `json:"counts"`    // CMSごとのドメイン数
+}
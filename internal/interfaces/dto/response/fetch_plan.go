@@ -0,0 +1,66 @@
+package response
+
+import (
+	"time"
+
+	"github.com/zuxt268/sales/internal/model"
+)
+
+// FetchPlan 次のFetchで呼び出す予定と、当日のViewDNSの呼び出し回数
+type FetchPlan struct {
+	Date        string              `json:"date"`
+	DailyLimit  int                 `json:"daily_limit"` // 0は無制限
+	Used        int                 `json:"used"`
+	Remaining   int                 `json:"remaining"` // -1は無制限
+	CallsPerRun int                 `json:"calls_per_run"`
+	NonWixRatio float64             `json:"non_wix_ratio"`
+	Calls       []*FetchPlanTarget  `json:"calls"`
+	BackingOff  []*BackingOffTarget `json:"backing_off"` // 失敗後の待機中で今回は取得しないもの
+}
+
+type FetchPlanTarget struct {
+	TargetID int    `json:"target_id"`
+	IP       string `json:"ip"`
+	Name     string `json:"name"`
+	Calls    int    `json:"calls"` // 今回進めるページ数
+}
+
+type BackingOffTarget struct {
+	TargetID     int        `json:"target_id"`
+	IP           string     `json:"ip"`
+	Name         string     `json:"name"`
+	FailureCount int        `json:"failure_count"`
+	NextFetchAt  *time.Time `json:"next_fetch_at"`
+	LastError    string     `json:"last_error"`
+}
+
+// GetFetchPlanTargets 呼び出し順の計画をターゲットごとの回数にまとめる。並びは最初に呼び出す順
+func GetFetchPlanTargets(calls []*model.Target) []*FetchPlanTarget {
+	res := make([]*FetchPlanTarget, 0, len(calls))
+	byID := make(map[int]*FetchPlanTarget)
+	for _, t := range calls {
+		if p, ok := byID[t.ID]; ok {
+			p.Calls++
+			continue
+		}
+		p := &FetchPlanTarget{TargetID: t.ID, IP: t.IP, Name: t.Name, Calls: 1}
+		byID[t.ID] = p
+		res = append(res, p)
+	}
+	return res
+}
+
+func GetBackingOffTargets(targets []*model.Target) []*BackingOffTarget {
+	res := make([]*BackingOffTarget, 0, len(targets))
+	for _, t := range targets {
+		res = append(res, &BackingOffTarget{
+			TargetID:     t.ID,
+			IP:           t.IP,
+			Name:         t.Name,
+			FailureCount: t.FailureCount,
+			NextFetchAt:  t.NextFetchAt,
+			LastError:    t.LastError,
+		})
+	}
+	return res
+}
@@ -0,0 +1,63 @@
+package response
+
+import (
+	"github.com/zuxt268/sales/internal/entity"
+	"github.com/zuxt268/sales/internal/model"
+)
+
+type Industry struct {
+	Code        string      `json:"code"`
+	Name        string      `json:"name"`
+	DomainCount int         `json:"domain_count"`
+	HomstaCount int         `json:"homsta_count"`
+	Children    []*Industry `json:"children,omitempty"`
+}
+
+type Industries struct {
+	Industries []*Industry `json:"industries"`
+}
+
+// GetIndustries 大分類の下に中分類をぶら下げた一覧を作る。名称は業種リスト（entity）から引く
+func GetIndustries(industries []*model.Industry, domainCounts, homstaCounts []*model.IndustryCount) *Industries {
+	domains := make(map[string]int, len(domainCounts))
+	for _, c := range domainCounts {
+		domains[c.Code] = c.Count
+	}
+	homstas := make(map[string]int, len(homstaCounts))
+	for _, c := range homstaCounts {
+		homstas[c.Code] = c.Count
+	}
+
+	majors := make([]*Industry, 0)
+	byCode := make(map[string]*Industry)
+	for _, i := range industries {
+		if i.Level != model.IndustryLevelMajor {
+			continue
+		}
+		m := &Industry{
+			Code:        i.Code,
+			Name:        entity.IndustryMajorName(i.Code),
+			DomainCount: domains[i.Code],
+			HomstaCount: homstas[i.Code],
+			Children:    make([]*Industry, 0),
+		}
+		majors = append(majors, m)
+		byCode[i.Code] = m
+	}
+	for _, i := range industries {
+		if i.Level != model.IndustryLevelMiddle || i.ParentCode == nil {
+			continue
+		}
+		m, ok := byCode[*i.ParentCode]
+		if !ok {
+			continue
+		}
+		m.Children = append(m.Children, &Industry{
+			Code:        i.Code,
+			Name:        entity.IndustryName(i.Code),
+			DomainCount: domains[i.Code],
+			HomstaCount: homstas[i.Code],
+		})
+	}
+	return &Industries{Industries: majors}
+}
@@ -0,0 +1,22 @@
+package response
+
+import "github.com/zuxt268/sales/internal/model"
+
+type LLMUsage struct {
+	Today  LLMBudget                `json:"today"`
+	Usages []*model.LLMUsageSummary `json:"usages"`
+}
+
+type LLMCacheCleared struct {
+	Deleted int `json:"deleted"`
+}
+
+// LLMBudget 当日の使用量と1日の予算。予算が0の項目は無制限
+type LLMBudget struct {
+	Date        string  `json:"date"`
+	TotalTokens int     `json:"total_tokens"`
+	Cost        float64 `json:"cost"`
+	TokenBudget int     `json:"token_budget"`
+	CostBudget  float64 `json:"cost_budget"`
+	Exceeded    bool    `json:"exceeded"`
+}
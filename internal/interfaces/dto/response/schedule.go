@@ -0,0 +1,60 @@
+package response
+
+import (
+	"time"
+
+	"github.com/zuxt268/sales/internal/model"
+)
+
+type Schedule struct {
+	Name      string       `json:"name"`
+	CronExpr  string       `json:"cron_expr"`
+	Paused    bool         `json:"paused"`
+	NextRunAt *time.Time   `json:"next_run_at"`
+	LastRun   *ScheduleRun `json:"last_run"` // 直近の実行結果
+}
+
+type ScheduleRun struct {
+	ID           int64                   `json:"id"`
+	ScheduleName string                  `json:"schedule_name"`
+	Trigger      model.ScheduleTrigger   `json:"trigger"`
+	Status       model.ScheduleRunStatus `json:"status"`
+	Error        string                  `json:"error"`
+	Instance     string                  `json:"instance"`
+	StartedAt    time.Time               `json:"started_at"`
+	FinishedAt   *time.Time              `json:"finished_at"`
+}
+
+func GetSchedule(s *model.Schedule, lastRun *model.ScheduleRun) *Schedule {
+	res := &Schedule{
+		Name:      s.Name,
+		CronExpr:  s.CronExpr,
+		Paused:    s.Paused,
+		NextRunAt: s.NextRunAt,
+	}
+	if lastRun != nil {
+		res.LastRun = GetScheduleRun(lastRun)
+	}
+	return res
+}
+
+func GetScheduleRun(r *model.ScheduleRun) *ScheduleRun {
+	return &ScheduleRun{
+		ID:           r.ID,
+		ScheduleName: r.ScheduleName,
+		Trigger:      r.Trigger,
+		Status:       r.Status,
+		Error:        r.Error,
+		Instance:     r.Instance,
+		StartedAt:    r.StartedAt,
+		FinishedAt:   r.FinishedAt,
+	}
+}
+
+func GetScheduleRuns(runs []*model.ScheduleRun) []*ScheduleRun {
+	res := make([]*ScheduleRun, 0, len(runs))
+	for _, r := range runs {
+		res = append(res, GetScheduleRun(r))
+	}
+	return res
+}
@@ -0,0 +1,83 @@
+package response
+
+import (
+	"time"
+
+	"github.com/zuxt268/sales/internal/entity"
+	"github.com/zuxt268/sales/internal/model"
+)
+
+// TargetImport 取り込みの結果。Rowは取り込んだ行の番号（1から）
+type TargetImport struct {
+	Created    []*TargetImportRow `json:"created"`
+	Duplicates []*TargetImportRow `json:"duplicates"` // 登録済み、または同じ取り込みの中で重複したアドレス
+	Invalid    []*TargetImportRow `json:"invalid"`
+}
+
+type TargetImportRow struct {
+	Row   int    `json:"row"`
+	Value string `json:"value"`
+	IP    string `json:"ip,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+// TargetStats ターゲットの取得の成果
+// ドメイン数はそのターゲットのIPで逆引きして見つけたドメインを数える
+type TargetStats struct {
+	TargetID       int                    `json:"target_id"`
+	IP             string                 `json:"ip"`
+	Name           string                 `json:"name"`
+	Source         string                 `json:"source"`
+	Status         model.TargetStatus     `json:"status"`
+	Weight         int                    `json:"weight"`
+	TotalDomains   int64                  `json:"total_domains"`
+	StatusCounts   map[model.Status]int64 `json:"status_counts"`
+	DoneRatio      float64                `json:"done_ratio"` // done まで進んだドメインの割合
+	CurrentPage    int                    `json:"current_page"`
+	MaxPage        int                    `json:"max_page"` // 最後に取得したときの総ページ数（未取得は0）
+	LastFetchedAt  *time.Time             `json:"last_fetched_at"`
+	LastFullScanAt *time.Time             `json:"last_full_scan_at"`
+	Scans          []*TargetScan          `json:"scans"` // 新しい順
+}
+
+// TargetScan 1周分の取得の記録。FinishedAtがnullの周は取得中
+type TargetScan struct {
+	StartedAt    time.Time  `json:"started_at"`
+	FinishedAt   *time.Time `json:"finished_at"`
+	Pages        int        `json:"pages"`
+	DomainsFound int        `json:"domains_found"`
+	NewDomains   int        `json:"new_domains"` // 初めて登録したドメイン数
+}
+
+func GetTargetStats(target *model.Target, counts map[model.Status]int64, scans []*model.TargetScan) *TargetStats {
+	total, ratio := entity.DomainDoneRatio(counts)
+	if counts == nil {
+		counts = map[model.Status]int64{}
+	}
+	res := &TargetStats{
+		TargetID:       target.ID,
+		IP:             target.IP,
+		Name:           target.Name,
+		Source:         target.GetSource(),
+		Status:         target.Status,
+		Weight:         target.Weight,
+		TotalDomains:   total,
+		StatusCounts:   counts,
+		DoneRatio:      ratio,
+		CurrentPage:    target.CurrentPage,
+		MaxPage:        target.MaxPage,
+		LastFetchedAt:  target.LastFetchedAt,
+		LastFullScanAt: target.LastFullScanAt,
+		Scans:          make([]*TargetScan, 0, len(scans)),
+	}
+	for _, s := range scans {
+		res.Scans = append(res.Scans, &TargetScan{
+			StartedAt:    s.StartedAt,
+			FinishedAt:   s.FinishedAt,
+			Pages:        s.Pages,
+			DomainsFound: s.DomainsFound,
+			NewDomains:   s.NewDomains,
+		})
+	}
+	return res
+}
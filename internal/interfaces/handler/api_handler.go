@@ -1,7 +1,6 @@
 package handler
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
@@ -10,6 +9,7 @@ import (
 	"log/slog"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/zuxt268/sales/internal/entity"
 	"github.com/zuxt268/sales/internal/interfaces/adapter"
@@ -27,6 +27,9 @@ type ApiHandler interface {
 	GetDomains(c echo.Context) error
 	UpdateDomain(c echo.Context) error
 	DeleteDomain(c echo.Context) error
+	GetDomainStatusHistories(c echo.Context) error
+	GetDomainSightings(c echo.Context) error
+	FingerprintDomain(c echo.Context) error
 	FetchDomains(c echo.Context) error
 	PollingDomains(c echo.Context) error
 	BackupGoogleDrive(c echo.Context) error
@@ -35,6 +38,9 @@ type ApiHandler interface {
 	CreateTarget(c echo.Context) error
 	UpdateTarget(c echo.Context) error
 	DeleteTarget(c echo.Context) error
+	ImportTargets(c echo.Context) error
+	GetTargetStats(c echo.Context) error
+	GetTargetsStats(c echo.Context) error
 	DeployWordpress(c echo.Context) error
 	DeployWordpressOne(c echo.Context) error
 	FetchHomstaDomains(c echo.Context) error
@@ -50,21 +56,41 @@ type ApiHandler interface {
 	GetHomsta(c echo.Context) error
 
 	Fetch(c echo.Context) error
+	GetFetchPlan(c echo.Context) error
 	Polling(c echo.Context) error
+	Reap(c echo.Context) error
+	Fingerprint(c echo.Context) error
 	Analyze(c echo.Context) error
 	Output(c echo.Context) error
+
+	GetLLMUsage(c echo.Context) error
+	ClearLLMCache(c echo.Context) error
+
+	GetIndustries(c echo.Context) error
+
+	RelayOutbox(c echo.Context) error
+
+	GetSchedules(c echo.Context) error
+	UpdateSchedule(c echo.Context) error
+	TriggerSchedule(c echo.Context) error
+	GetScheduleRuns(c echo.Context) error
 }
 
 type apiHandler struct {
-	fetchUsecase  usecase.FetchUsecase
-	domainUsecase usecase.DomainUsecase
-	targetUsecase usecase.TargetUsecase
-	gptUsecase    usecase.GptUsecase
-	deployUsecase usecase.DeployUsecase
-	sheetUsecase  usecase.SheetUsecase
-	growthUsecase usecase.GrowthUsecase
-	homstaUsecase usecase.HomstaUsecase
-	slackAdapter  adapter.SlackAdapter
+	fetchUsecase       usecase.FetchUsecase
+	domainUsecase      usecase.DomainUsecase
+	targetUsecase      usecase.TargetUsecase
+	gptUsecase         usecase.GptUsecase
+	deployUsecase      usecase.DeployUsecase
+	sheetUsecase       usecase.SheetUsecase
+	growthUsecase      usecase.GrowthUsecase
+	homstaUsecase      usecase.HomstaUsecase
+	llmUsecase         usecase.LLMUsecase
+	industryUsecase    usecase.IndustryUsecase
+	outboxUsecase      usecase.OutboxUsecase
+	scheduleUsecase    usecase.ScheduleUsecase
+	fingerprintUsecase usecase.FingerprintUsecase
+	slackAdapter       adapter.SlackAdapter
 }
 
 func NewApiHandler(
@@ -76,18 +102,28 @@ func NewApiHandler(
 	sheetUsecase usecase.SheetUsecase,
 	growthUsecase usecase.GrowthUsecase,
 	homstaUsecase usecase.HomstaUsecase,
+	llmUsecase usecase.LLMUsecase,
+	industryUsecase usecase.IndustryUsecase,
+	outboxUsecase usecase.OutboxUsecase,
+	scheduleUsecase usecase.ScheduleUsecase,
+	fingerprintUsecase usecase.FingerprintUsecase,
 	slackAdapter adapter.SlackAdapter,
 ) ApiHandler {
 	return &apiHandler{
-		fetchUsecase:  fetchUsecase,
-		domainUsecase: domainUsecase,
-		targetUsecase: targetUsecase,
-		gptUsecase:    gptUsecase,
-		deployUsecase: deployUsecase,
-		sheetUsecase:  sheetUsecase,
-		growthUsecase: growthUsecase,
-		homstaUsecase: homstaUsecase,
-		slackAdapter:  slackAdapter,
+		fetchUsecase:       fetchUsecase,
+		domainUsecase:      domainUsecase,
+		targetUsecase:      targetUsecase,
+		gptUsecase:         gptUsecase,
+		deployUsecase:      deployUsecase,
+		sheetUsecase:       sheetUsecase,
+		growthUsecase:      growthUsecase,
+		homstaUsecase:      homstaUsecase,
+		llmUsecase:         llmUsecase,
+		industryUsecase:    industryUsecase,
+		outboxUsecase:      outboxUsecase,
+		scheduleUsecase:    scheduleUsecase,
+		fingerprintUsecase: fingerprintUsecase,
+		slackAdapter:       slackAdapter,
 	}
 }
 
@@ -127,7 +163,12 @@ func (h *apiHandler) GetDomain(c echo.Context) error {
 // @Param owner_id query string false "owner_id"
 // @Param status query string false "ステータス"
 // @Param industry query string false "業種"
+// @Param industry_code query string false "業種コード（日本標準産業分類の大分類A〜Tまたは中分類01〜99）"
 // @Param is_ssl query boolean false "SSL対応可否"
+// @Param moved_away query boolean false "すべてのターゲットで見つからなくなったもの"
+// @Param platform query string false "サイトの基盤（generic, wix）"
+// @Param cms query string false "CMS・ビルダー（wordpress, wix, jimdo, shopify, studio, ameba_ownd, static, other, unknown）"
+// @Param hosting_ip query string false "ホスティング先のIP"
 // @Success 200 {array} response.Domains
 // @Router /domains [get]
 func (h *apiHandler) GetDomains(c echo.Context) error {
@@ -193,6 +234,75 @@ func (h *apiHandler) DeleteDomain(c echo.Context) error {
 	return c.NoContent(http.StatusNoContent)
 }
 
+// GetDomainStatusHistories godoc
+// @Summary Get domain status histories
+// @Description ドメインのステータス変更履歴を新しい順に取得する
+// @Tags ドメイン
+// @Accept json
+// @Produce json
+// @Param id path string true "ID"
+// @Param limit query int false "Limit"
+// @Param offset query int false "Offset"
+// @Success 200 {array} response.DomainStatusHistory
+// @Router /domains/{id}/status-histories [get]
+func (h *apiHandler) GetDomainStatusHistories(c echo.Context) error {
+	var id int
+	if err := echo.PathParamsBinder(c).Int("id", &id).BindError(); err != nil {
+		return c.JSON(http.StatusBadRequest, err.Error())
+	}
+	var req request.GetDomainStatusHistories
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, err.Error())
+	}
+	resp, err := h.domainUsecase.GetStatusHistories(c.Request().Context(), id, req)
+	if err != nil {
+		return handleError(c, err)
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+// GetDomainSightings godoc
+// @Summary Get domain sightings
+// @Description ドメインを逆引きで見つけたターゲットのIPごとに、最初と最後に見つけた日時、移転したとみなした日時を取得する
+// @Tags ドメイン
+// @Accept json
+// @Produce json
+// @Param id path string true "ID"
+// @Success 200 {array} model.DomainSighting
+// @Router /domains/{id}/sightings [get]
+func (h *apiHandler) GetDomainSightings(c echo.Context) error {
+	var id int
+	if err := echo.PathParamsBinder(c).Int("id", &id).BindError(); err != nil {
+		return c.JSON(http.StatusBadRequest, err.Error())
+	}
+	resp, err := h.domainUsecase.GetSightings(c.Request().Context(), id)
+	if err != nil {
+		return handleError(c, err)
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+// FingerprintDomain godoc
+// @Summary Fingerprint domain
+// @Description ドメインのサイトを作ったCMS・ビルダーとホスティング先のIPを判定し直す
+// @Tags ドメイン
+// @Accept json
+// @Produce json
+// @Param id path string true "ID"
+// @Success 200 {object} response.Domain
+// @Router /domains/{id}/fingerprint [post]
+func (h *apiHandler) FingerprintDomain(c echo.Context) error {
+	var id int
+	if err := echo.PathParamsBinder(c).Int("id", &id).BindError(); err != nil {
+		return c.JSON(http.StatusBadRequest, err.Error())
+	}
+	resp, err := h.fingerprintUsecase.FingerprintDomain(c.Request().Context(), id)
+	if err != nil {
+		return handleError(c, err)
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
 // AnalyzeDomains godoc
 // @Summary サイトの情報を解析する
 // @Tags ドメイン
@@ -342,6 +452,102 @@ func (h *apiHandler) DeleteTarget(c echo.Context) error {
 	return c.NoContent(http.StatusNoContent)
 }
 
+// ImportTargets godoc
+// @Summary Import targets
+// @Description IPアドレス・ホスト名・CIDRの一覧からターゲットをまとめて登録する。JSON、CSV（text/csv）、またはmultipartのfileでCSVを受け付ける
+// @Tags ターゲット
+// @Accept json,text/csv,multipart/form-data
+// @Produce json
+// @Param request body request.ImportTargets false "取り込むターゲット"
+// @Param file formData file false "CSVファイル（1行目は列名。value/ip/host/hostname/cidr/address, name, source, weight）"
+// @Success 200 {object} response.TargetImport
+// @Router /targets/import [post]
+func (h *apiHandler) ImportTargets(c echo.Context) error {
+	var req *request.ImportTargets
+	contentType := c.Request().Header.Get(echo.HeaderContentType)
+	switch {
+	case strings.HasPrefix(contentType, echo.MIMEMultipartForm):
+		fh, err := c.FormFile("file")
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, err.Error())
+		}
+		f, err := fh.Open()
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, err.Error())
+		}
+		defer f.Close()
+		req, err = request.ParseImportTargetsCSV(f)
+		if err != nil {
+			return handleError(c, err)
+		}
+	case strings.HasPrefix(contentType, "text/csv"):
+		var err error
+		req, err = request.ParseImportTargetsCSV(c.Request().Body)
+		if err != nil {
+			return handleError(c, err)
+		}
+	default:
+		req = &request.ImportTargets{}
+		if err := c.Bind(req); err != nil {
+			return c.JSON(http.StatusBadRequest, err.Error())
+		}
+	}
+	resp, err := h.targetUsecase.ImportTargets(c.Request().Context(), *req)
+	if err != nil {
+		return handleError(c, err)
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+// GetTargetStats godoc
+// @Summary Get target stats
+// @Description ターゲットの取得の成果（ステータスごとのドメイン数、doneの割合、ページの進み具合、周回ごとの新しいドメイン数）
+// @Tags ターゲット
+// @Accept json
+// @Produce json
+// @Param id path int true "ID"
+// @Param scans query int false "返す周回の記録の数（既定10、最大100）"
+// @Success 200 {object} response.TargetStats
+// @Router /targets/{id}/stats [get]
+func (h *apiHandler) GetTargetStats(c echo.Context) error {
+	var id int
+	if err := echo.PathParamsBinder(c).Int("id", &id).BindError(); err != nil {
+		return c.JSON(http.StatusBadRequest, err.Error())
+	}
+	var req request.GetTargetStats
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, err.Error())
+	}
+	resp, err := h.targetUsecase.GetTargetStats(c.Request().Context(), id, req)
+	if err != nil {
+		return handleError(c, err)
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+// GetTargetsStats godoc
+// @Summary Get stats of targets
+// @Description ターゲットごとの取得の成果の一覧
+// @Tags ターゲット
+// @Accept json
+// @Produce json
+// @Param limit query int false "Limit"
+// @Param offset query int false "Offset"
+// @Param scans query int false "ターゲットごとに返す周回の記録の数（既定10、最大100）"
+// @Success 200 {array} response.TargetStats
+// @Router /targets/stats [get]
+func (h *apiHandler) GetTargetsStats(c echo.Context) error {
+	var req request.GetTargetStats
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, err.Error())
+	}
+	resp, err := h.targetUsecase.GetTargetsStats(c.Request().Context(), req)
+	if err != nil {
+		return handleError(c, err)
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
 // DeployWordpress godoc
 // @Summary ワードプレスをデプロイします
 // @Description
@@ -468,56 +674,26 @@ func (h *apiHandler) AssortWordpress(c echo.Context) error {
 }
 
 // AnalyzeDomain godoc
-// @Summary PubSubのwebhookエンドポイント
+// @Summary PubSubのwebhookエンドポイント（GPTによる解析）
 // @Tags ドメイン
 // @Accept json
 // @Produce json
+// @Security BearerAuth
 // @Success 204
-// @Router /webhook/analyze [post]
+// @Router /webhook/analyze-domain [post]
 func (h *apiHandler) AnalyzeDomain(c echo.Context) error {
-	// リクエストBodyを読み取ってログ出力
-	bodyBytes, err := io.ReadAll(c.Request().Body)
+	msg, err := decodePushMessage(c)
 	if err != nil {
-		slog.Error("Failed to read request body", "error", err)
-		return c.JSON(http.StatusBadRequest, "Failed to read request body")
-	}
-
-	slog.Info("Received webhook request",
-		"body", string(bodyBytes),
-		"content-type", c.Request().Header.Get("Content-Type"),
-		"content-length", len(bodyBytes))
-
-	// Bodyを再度読めるように復元
-	c.Request().Body = io.NopCloser(bytes.NewBuffer(bodyBytes))
-
-	// Pub/Sub Push の構造体
-	var push struct {
-		Message struct {
-			Data []byte `json:"data"`
-		} `json:"message"`
-	}
-
-	// Pub/Sub JSON を解析
-	if err := json.Unmarshal(bodyBytes, &push); err != nil {
-		slog.Error("Failed to unmarshal pubsub push", "error", err)
-		return c.JSON(http.StatusBadRequest, "invalid pubsub message")
+		return c.JSON(http.StatusBadRequest, err.Error())
 	}
-
-	slog.Info("Decoded PubSub data", "data_json", string(push.Message.Data))
-
-	// Base64 decode 済み JSON を DomainMessage に Unmarshal
-	var domainMessage external.DomainMessage
-	if err := json.Unmarshal(push.Message.Data, &domainMessage); err != nil {
-		slog.Error("Failed to unmarshal domain message", "error", err, "decoded", string(push.Message.Data))
-		return c.JSON(http.StatusBadRequest, "invalid domain message")
+	if msg.Type != external.PipelineAnalyze {
+		// 再送されても処理できないため、受け取ったことにして読み捨てる
+		slog.Warn("Unsupported pipeline message type, acked without processing", "type", msg.Type, "message_id", msg.MessageID)
+		return c.NoContent(http.StatusNoContent)
 	}
-
-	slog.Info("Successfully parsed domain message", "message", domainMessage)
-
-	if err := h.gptUsecase.AnalyzeDomain(c.Request().Context(), &domainMessage); err != nil {
+	if err := h.gptUsecase.AnalyzeDomain(c.Request().Context(), msg); err != nil {
 		return handleError(c, err)
 	}
-
 	return c.NoContent(http.StatusNoContent)
 }
 
@@ -540,6 +716,22 @@ func (h *apiHandler) Fetch(c echo.Context) error {
 	return c.NoContent(http.StatusAccepted)
 }
 
+// GetFetchPlan godoc
+// @Summary 次のFetchの計画を取得する
+// @Description 次のFetchでReverseIPを呼び出すターゲットと回数、失敗後の待機中のターゲット、当日のViewDNSの残り回数を返す
+// @Tags ドメイン
+// @Accept json
+// @Produce json
+// @Success 200 {object} response.FetchPlan
+// @Router /growth/fetch/plan [get]
+func (h *apiHandler) GetFetchPlan(c echo.Context) error {
+	resp, err := h.growthUsecase.GetFetchPlan(c.Request().Context())
+	if err != nil {
+		return handleError(c, err)
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
 // Polling godoc
 // @Summary
 // @Tags ドメイン
@@ -549,6 +741,10 @@ func (h *apiHandler) Fetch(c echo.Context) error {
 // @Router /growth/polling [post]
 func (h *apiHandler) Polling(c echo.Context) error {
 	err := h.growthUsecase.Polling(context.Background())
+	if err == nil {
+		// 書き込んだメッセージをすぐに送る。送れなかった分は /outbox/relay で再送する
+		_, err = h.outboxUsecase.Relay(context.Background())
+	}
 	if err != nil {
 		msg := "[Polling]\n" + err.Error()
 		if err := h.slackAdapter.Send(c.Request().Context(), msg); err != nil {
@@ -559,19 +755,110 @@ func (h *apiHandler) Polling(c echo.Context) error {
 	return c.NoContent(http.StatusAccepted)
 }
 
+// RelayOutbox godoc
+// @Summary Relay outbox messages
+// @Description 送信待ちのメッセージをPub/Subに送信する。失敗したメッセージは間隔を空けて再送する
+// @Tags ドメイン
+// @Accept json
+// @Produce json
+// @Success 200 {object} response.RelayOutbox
+// @Router /outbox/relay [post]
+func (h *apiHandler) RelayOutbox(c echo.Context) error {
+	resp, err := h.outboxUsecase.Relay(c.Request().Context())
+	if err != nil {
+		msg := "[RelayOutbox]\n" + err.Error()
+		if err := h.slackAdapter.Send(c.Request().Context(), msg); err != nil {
+			return c.JSON(http.StatusInternalServerError, err.Error())
+		}
+		return handleError(c, err)
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+// Reap godoc
+// @Summary Reap stuck domains
+// @Description ワーカーの報告がないまま止まっているドメインを再送し、上限に達したものはunknownにする
+// @Tags ドメイン
+// @Accept json
+// @Produce json
+// @Success 200 {object} response.ReapDomains
+// @Router /growth/reap [post]
+func (h *apiHandler) Reap(c echo.Context) error {
+	resp, err := h.growthUsecase.Reap(c.Request().Context())
+	if err == nil {
+		_, err = h.outboxUsecase.Relay(c.Request().Context())
+	}
+	if err != nil {
+		msg := "[Reap]\n" + err.Error()
+		if err := h.slackAdapter.Send(c.Request().Context(), msg); err != nil {
+			return c.JSON(http.StatusInternalServerError, err.Error())
+		}
+		return handleError(c, err)
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+// Fingerprint godoc
+// @Summary Fingerprint domains
+// @Description まだ判定していないドメインのCMS・ビルダーとホスティング先のIPをまとめて判定する
+// @Tags ドメイン
+// @Accept json
+// @Produce json
+// @Success 200 {object} response.Fingerprint
+// @Router /growth/fingerprint [post]
+func (h *apiHandler) Fingerprint(c echo.Context) error {
+	resp, err := h.fingerprintUsecase.Fingerprint(c.Request().Context())
+	if err != nil {
+		msg := "[Fingerprint]\n" + err.Error()
+		if err := h.slackAdapter.Send(c.Request().Context(), msg); err != nil {
+			return c.JSON(http.StatusInternalServerError, err.Error())
+		}
+		return handleError(c, err)
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
 // Analyze godoc
-// @Summary
+// @Summary PubSubのwebhookエンドポイント
 // @Tags ドメイン
 // @Accept json
 // @Produce json
+// @Security BearerAuth
 // @Success 204
-// @Router /growth/analyze [post]
+// @Router /webhook/analyze [post]
 func (h *apiHandler) Analyze(c echo.Context) error {
-	// リクエストBodyを読み取ってログ出力
+	msg, err := decodePushMessage(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, err.Error())
+	}
+
+	// メッセージの種類ごとに処理を振り分ける
+	switch msg.Type {
+	case external.PipelineAnalyze:
+		err = h.growthUsecase.Analyze(c.Request().Context(), msg)
+	case external.PipelineCheckWix:
+		err = h.growthUsecase.CheckWix(c.Request().Context(), msg)
+	case external.PipelineCheckView:
+		err = h.growthUsecase.CheckView(c.Request().Context(), msg)
+	case external.PipelineCheckJapan:
+		err = h.growthUsecase.CheckJapan(c.Request().Context(), msg)
+	default:
+		// 処理がまだない種類は再送されても処理できないため、受け取ったことにして読み捨てる
+		slog.Warn("Unsupported pipeline message type, acked without processing", "type", msg.Type, "message_id", msg.MessageID)
+		return c.NoContent(http.StatusNoContent)
+	}
+	if err != nil {
+		return handleError(c, err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// decodePushMessage Pub/SubのpushリクエストからPipelineMessageを取り出す
+func decodePushMessage(c echo.Context) (*external.PipelineMessage, error) {
 	bodyBytes, err := io.ReadAll(c.Request().Body)
 	if err != nil {
 		slog.Error("Failed to read request body", "error", err)
-		return c.JSON(http.StatusBadRequest, "Failed to read request body")
+		return nil, errors.New("failed to read request body")
 	}
 
 	slog.Info("Received webhook request",
@@ -579,38 +866,23 @@ func (h *apiHandler) Analyze(c echo.Context) error {
 		"content-type", c.Request().Header.Get("Content-Type"),
 		"content-length", len(bodyBytes))
 
-	// Bodyを再度読めるように復元
-	c.Request().Body = io.NopCloser(bytes.NewBuffer(bodyBytes))
-
-	// Pub/Sub Push の構造体
-	var push struct {
-		Message struct {
-			Data []byte `json:"data"`
-		} `json:"message"`
-	}
-
 	// Pub/Sub JSON を解析
+	var push external.PubSubPushRequest
 	if err := json.Unmarshal(bodyBytes, &push); err != nil {
 		slog.Error("Failed to unmarshal pubsub push", "error", err)
-		return c.JSON(http.StatusBadRequest, "invalid pubsub message")
-	}
-
-	slog.Info("Decoded PubSub data", "data_json", string(push.Message.Data))
-
-	// Base64 decode 済み JSON を DomainMessage に Unmarshal
-	var domainMessage external.DomainMessage
-	if err := json.Unmarshal(push.Message.Data, &domainMessage); err != nil {
-		slog.Error("Failed to unmarshal domain message", "error", err, "decoded", string(push.Message.Data))
-		return c.JSON(http.StatusBadRequest, "invalid domain message")
+		return nil, errors.New("invalid pubsub message")
 	}
 
-	slog.Info("Successfully parsed domain message", "message", domainMessage)
-
-	if err := h.growthUsecase.Analyze(c.Request().Context(), &domainMessage); err != nil {
-		return handleError(c, err)
+	// Base64 decode 済み JSON を PipelineMessage にする
+	msg, err := external.DecodePipelineMessage(push.Message.Data)
+	if err != nil {
+		slog.Error("Failed to decode pipeline message", "error", err, "decoded", string(push.Message.Data))
+		return nil, err
 	}
 
-	return c.NoContent(http.StatusNoContent)
+	slog.Info("Successfully parsed pipeline message",
+		"type", msg.Type, "domain_id", msg.DomainID, "message_id", msg.MessageID, "trace_id", msg.TraceID)
+	return msg, nil
 }
 
 // Output godoc
@@ -731,6 +1003,137 @@ func (h *apiHandler) GetHomsta(c echo.Context) error {
 	return c.JSON(http.StatusOK, homsta)
 }
 
+// GetLLMUsage godoc
+// @Summary LLMの使用量を取得する
+// @Description 日別・機能別に集計した使用量と、当日の予算の消化状況を返す
+// @Tags LLM
+// @Accept json
+// @Produce json
+// @Param from query string false "集計開始日(YYYY-MM-DD)。省略時は29日前"
+// @Param to query string false "集計終了日(YYYY-MM-DD)。省略時は当日"
+// @Param feature query string false "機能(analyze_domain, analyze_site_industry)"
+// @Success 200 {object} response.LLMUsage
+// @Router /llm/usage [get]
+func (h *apiHandler) GetLLMUsage(c echo.Context) error {
+	var req request.GetLLMUsage
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, err.Error())
+	}
+	resp, err := h.llmUsecase.GetUsage(c.Request().Context(), req)
+	if err != nil {
+		return handleError(c, err)
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+// ClearLLMCache godoc
+// @Summary LLMの応答キャッシュを削除する
+// @Description プロンプトや業種リストを変更した後など、同じ入力でも再解析させたい場合に使う
+// @Tags LLM
+// @Accept json
+// @Produce json
+// @Success 200 {object} response.LLMCacheCleared
+// @Router /llm/cache [delete]
+func (h *apiHandler) ClearLLMCache(c echo.Context) error {
+	resp, err := h.llmUsecase.ClearCache(c.Request().Context())
+	if err != nil {
+		return handleError(c, err)
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+// GetIndustries godoc
+// @Summary 業種一覧を取得する
+// @Description 日本標準産業分類の大分類・中分類ごとに、紐付くドメイン数とHomsta数を返す
+// @Tags 業種
+// @Accept json
+// @Produce json
+// @Success 200 {object} response.Industries
+// @Router /industries [get]
+func (h *apiHandler) GetIndustries(c echo.Context) error {
+	resp, err := h.industryUsecase.GetIndustries(c.Request().Context())
+	if err != nil {
+		return handleError(c, err)
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+// GetSchedules godoc
+// @Summary 定期実行するジョブの一覧を取得する
+// @Description cron式・一時停止中かどうか・次の実行日時・直近の実行結果を返す
+// @Tags スケジュール
+// @Accept json
+// @Produce json
+// @Success 200 {array} response.Schedule
+// @Router /schedules [get]
+func (h *apiHandler) GetSchedules(c echo.Context) error {
+	resp, err := h.scheduleUsecase.GetSchedules(c.Request().Context())
+	if err != nil {
+		return handleError(c, err)
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+// UpdateSchedule godoc
+// @Summary ジョブのcron式を変更、または一時停止・再開する
+// @Tags スケジュール
+// @Accept json
+// @Produce json
+// @Param name path string true "ジョブ名"
+// @Param request body request.UpdateSchedule true "変更内容"
+// @Success 200 {object} response.Schedule
+// @Router /schedules/{name} [put]
+func (h *apiHandler) UpdateSchedule(c echo.Context) error {
+	var req request.UpdateSchedule
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, err.Error())
+	}
+	resp, err := h.scheduleUsecase.UpdateSchedule(c.Request().Context(), c.Param("name"), req)
+	if err != nil {
+		return handleError(c, err)
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+// TriggerSchedule godoc
+// @Summary ジョブをすぐに実行する
+// @Description 実行は非同期。同じジョブが実行中の場合は409を返す
+// @Tags スケジュール
+// @Accept json
+// @Produce json
+// @Param name path string true "ジョブ名"
+// @Success 202 {object} response.ScheduleRun
+// @Router /schedules/{name}/run [post]
+func (h *apiHandler) TriggerSchedule(c echo.Context) error {
+	resp, err := h.scheduleUsecase.Trigger(c.Request().Context(), c.Param("name"))
+	if err != nil {
+		return handleError(c, err)
+	}
+	return c.JSON(http.StatusAccepted, resp)
+}
+
+// GetScheduleRuns godoc
+// @Summary ジョブの実行履歴を新しい順に取得する
+// @Tags スケジュール
+// @Accept json
+// @Produce json
+// @Param name path string true "ジョブ名"
+// @Param limit query int false "Limit"
+// @Param offset query int false "Offset"
+// @Success 200 {array} response.ScheduleRun
+// @Router /schedules/{name}/runs [get]
+func (h *apiHandler) GetScheduleRuns(c echo.Context) error {
+	var req request.GetScheduleRuns
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, err.Error())
+	}
+	resp, err := h.scheduleUsecase.GetScheduleRuns(c.Request().Context(), c.Param("name"), req)
+	if err != nil {
+		return handleError(c, err)
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
 func handleError(c echo.Context, err error) error {
 	// ログ出力
 	slog.Error("Handler error",
@@ -783,6 +1186,18 @@ func handleError(c echo.Context, err error) error {
 			Message: "Request timed out",
 		})
 
+	case errors.Is(err, entity.ErrViewDNSQuotaExceeded):
+		return c.JSON(http.StatusTooManyRequests, model.ErrorResponse{
+			Error:   "viewdns_quota_exceeded",
+			Message: "Daily ViewDNS quota has been exceeded",
+		})
+
+	case errors.Is(err, entity.ErrLLMBudgetExceeded):
+		return c.JSON(http.StatusTooManyRequests, model.ErrorResponse{
+			Error:   "llm_budget_exceeded",
+			Message: "Daily LLM budget has been exceeded",
+		})
+
 	case errors.Is(err, entity.ErrDatabase):
 		return c.JSON(http.StatusInternalServerError, model.ErrorResponse{
 			Error:   "database_error",
@@ -0,0 +1,105 @@
+package middleware
+
+import (
+	"context"
+	"crypto/subtle"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/zuxt268/sales/internal/config"
+	"google.golang.org/api/idtoken"
+)
+
+// PubSubPushSecretHeader 共有シークレットで認証する場合のヘッダー。クエリパラメータ token でもよい
+const PubSubPushSecretHeader = "X-Pubsub-Push-Secret"
+
+// googleIssuers Googleが署名したIDトークンの発行者
+var googleIssuers = []string{"accounts.google.com", "https://accounts.google.com"}
+
+type pushAuthConfig struct {
+	audience       string
+	serviceAccount string
+	secret         string
+}
+
+type idTokenValidator func(ctx context.Context, token, audience string) (*idtoken.Payload, error)
+
+// PubSubPushMiddleware はPub/Subのpushリクエストを検証するミドルウェア
+// GoogleのOIDCトークンのaudienceとサービスアカウントのメールアドレスを設定と照合する
+// ローカルでの確認用に、共有シークレットが設定されていればそれでも認証できる
+func PubSubPushMiddleware() echo.MiddlewareFunc {
+	return pubSubPushMiddleware(pushAuthConfig{
+		audience:       config.Env.PubSubPushAudience,
+		serviceAccount: config.Env.PubSubPushServiceAccount,
+		secret:         config.Env.PubSubPushSecret,
+	}, idtoken.Validate)
+}
+
+func pubSubPushMiddleware(cfg pushAuthConfig, validate idTokenValidator) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if token, ok := bearerToken(c); ok && cfg.audience != "" && cfg.serviceAccount != "" {
+				if err := verifyPushToken(c.Request().Context(), cfg, validate, token); err != nil {
+					slog.Warn("Invalid pubsub push token", "error", err)
+					return unauthorized(c, "invalid token")
+				}
+				return next(c)
+			}
+
+			if cfg.secret != "" {
+				secret := c.Request().Header.Get(PubSubPushSecretHeader)
+				if secret == "" {
+					secret = c.QueryParam("token")
+				}
+				if subtle.ConstantTimeCompare([]byte(secret), []byte(cfg.secret)) == 1 {
+					return next(c)
+				}
+			}
+
+			slog.Warn("Unauthenticated pubsub push request", "path", c.Path())
+			return unauthorized(c, "unauthenticated push request")
+		}
+	}
+}
+
+func verifyPushToken(ctx context.Context, cfg pushAuthConfig, validate idTokenValidator, token string) error {
+	payload, err := validate(ctx, token, cfg.audience)
+	if err != nil {
+		return err
+	}
+	if !isGoogleIssuer(payload.Issuer) {
+		return echo.NewHTTPError(http.StatusUnauthorized, "unexpected issuer: "+payload.Issuer)
+	}
+	email, _ := payload.Claims["email"].(string)
+	verified, _ := payload.Claims["email_verified"].(bool)
+	if email != cfg.serviceAccount || !verified {
+		return echo.NewHTTPError(http.StatusUnauthorized, "unexpected service account: "+email)
+	}
+	return nil
+}
+
+func bearerToken(c echo.Context) (string, bool) {
+	parts := strings.SplitN(c.Request().Header.Get("Authorization"), " ", 2)
+	if len(parts) != 2 || parts[0] != "Bearer" || parts[1] == "" {
+		return "", false
+	}
+	return parts[1], true
+}
+
+func isGoogleIssuer(iss string) bool {
+	for _, v := range googleIssuers {
+		if iss == v {
+			return true
+		}
+	}
+	return false
+}
+
+func unauthorized(c echo.Context, message string) error {
+	return c.JSON(http.StatusUnauthorized, map[string]string{
+		"error":   "unauthorized",
+		"message": message,
+	})
+}
@@ -0,0 +1,72 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/api/idtoken"
+)
+
+const (
+	testAudience       = "https://sales.example.com/api/webhook/analyze"
+	testServiceAccount = "pubsub-push@example.iam.gserviceaccount.com"
+)
+
+// fakeValidator "valid" のトークンだけを受け付け、claimsを返す
+func fakeValidator(issuer string, claims map[string]interface{}) idTokenValidator {
+	return func(_ context.Context, token, audience string) (*idtoken.Payload, error) {
+		if token != "valid" || audience != testAudience {
+			return nil, errors.New("invalid token")
+		}
+		return &idtoken.Payload{Issuer: issuer, Audience: audience, Claims: claims}, nil
+	}
+}
+
+func TestPubSubPushMiddleware(t *testing.T) {
+	validClaims := map[string]interface{}{"email": testServiceAccount, "email_verified": true}
+	oidc := pushAuthConfig{audience: testAudience, serviceAccount: testServiceAccount}
+	both := pushAuthConfig{audience: testAudience, serviceAccount: testServiceAccount, secret: "s3cret"}
+
+	tests := []struct {
+		name      string
+		cfg       pushAuthConfig
+		validator idTokenValidator
+		target    string
+		header    map[string]string
+		want      int
+	}{
+		{"valid token", oidc, fakeValidator("https://accounts.google.com", validClaims), "/", map[string]string{"Authorization": "Bearer valid"}, http.StatusNoContent},
+		{"invalid token", oidc, fakeValidator("https://accounts.google.com", validClaims), "/", map[string]string{"Authorization": "Bearer forged"}, http.StatusUnauthorized},
+		{"unexpected issuer", oidc, fakeValidator("https://example.com", validClaims), "/", map[string]string{"Authorization": "Bearer valid"}, http.StatusUnauthorized},
+		{"other service account", oidc, fakeValidator("accounts.google.com", map[string]interface{}{"email": "other@example.com", "email_verified": true}), "/", map[string]string{"Authorization": "Bearer valid"}, http.StatusUnauthorized},
+		{"unverified email", oidc, fakeValidator("accounts.google.com", map[string]interface{}{"email": testServiceAccount}), "/", map[string]string{"Authorization": "Bearer valid"}, http.StatusUnauthorized},
+		{"no credentials", oidc, fakeValidator("accounts.google.com", validClaims), "/", nil, http.StatusUnauthorized},
+		{"invalid token does not fall back to secret", both, fakeValidator("accounts.google.com", validClaims), "/?token=s3cret", map[string]string{"Authorization": "Bearer forged"}, http.StatusUnauthorized},
+		{"secret in header", both, nil, "/", map[string]string{PubSubPushSecretHeader: "s3cret"}, http.StatusNoContent},
+		{"secret in query", both, nil, "/?token=s3cret", nil, http.StatusNoContent},
+		{"wrong secret", both, nil, "/?token=wrong", nil, http.StatusUnauthorized},
+		{"nothing configured", pushAuthConfig{}, nil, "/?token=", map[string]string{"Authorization": "Bearer valid"}, http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodPost, tt.target, nil)
+			for k, v := range tt.header {
+				req.Header.Set(k, v)
+			}
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			h := pubSubPushMiddleware(tt.cfg, tt.validator)(func(c echo.Context) error {
+				return c.NoContent(http.StatusNoContent)
+			})
+			assert.NoError(t, h(c))
+			assert.Equal(t, tt.want, rec.Code)
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/zuxt268/sales/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type APICallLedgerRepository interface {
+	GetCalls(ctx context.Context, api string, date time.Time) (int, error)
+	Increment(ctx context.Context, api string, date time.Time, n int) error
+}
+
+type apiCallLedgerRepository struct {
+	db *gorm.DB
+}
+
+func NewAPICallLedgerRepository(db *gorm.DB) APICallLedgerRepository {
+	return &apiCallLedgerRepository{
+		db: db,
+	}
+}
+
+// GetCalls dateの日に呼び出した回数。dateは0時に揃えて渡す。記録がなければ0
+func (r *apiCallLedgerRepository) GetCalls(ctx context.Context, api string, date time.Time) (int, error) {
+	var l model.APICallLedger
+	err := r.getDb(ctx).Where("date = ? AND api = ?", date, api).First(&l).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to get api call ledger: %w", err)
+	}
+	return l.Calls, nil
+}
+
+func (r *apiCallLedgerRepository) Increment(ctx context.Context, api string, date time.Time, n int) error {
+	err := r.getDb(ctx).Clauses(clause.OnConflict{
+		DoUpdates: clause.Assignments(map[string]any{"calls": gorm.Expr("calls + ?", n)}),
+	}).Create(&model.APICallLedger{
+		Date:  date,
+		API:   api,
+		Calls: n,
+	}).Error
+	if err != nil {
+		return fmt.Errorf("failed to increment api call ledger: %w", err)
+	}
+	return nil
+}
+
+func (r *apiCallLedgerRepository) getDb(ctx context.Context) *gorm.DB {
+	if v, ok := ctx.Value(TxKey{}).(*gorm.DB); ok {
+		return v.WithContext(ctx)
+	}
+	return r.db.WithContext(ctx)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 	"time"
 
 	"github.com/zuxt268/sales/internal/entity"
@@ -20,7 +21,9 @@ type DomainRepository interface {
 	FindAll(ctx context.Context, f DomainFilter) ([]*model.Domain, error)
 	Save(ctx context.Context, domain *model.Domain) error
 	BulkInsert(ctx context.Context, domains []*model.Domain) error
-	BulkUpdateStatus(ctx context.Context, fromStatus, toStatus model.Status) error
+	BulkUpdateStatus(ctx context.Context, ids []int, fromStatus, toStatus model.Status) error
+	UpdateMovedAway(ctx context.Context, names []string, at *time.Time) error
+	UpdateFingerprint(ctx context.Context, d *model.Domain) error
 	Delete(ctx context.Context, f DomainFilter) error
 	Count(ctx context.Context, f DomainFilter) (int64, error)
 }
@@ -97,6 +100,9 @@ func (r *domainRepository) Save(ctx context.Context, d *model.Domain) error {
 }
 
 func (r *domainRepository) BulkInsert(ctx context.Context, domains []*model.Domain) error {
+	if len(domains) == 0 {
+		return nil
+	}
 	err := r.getDb(ctx).Clauses(clause.OnConflict{
 		Columns:   []clause.Column{{Name: "name"}},
 		DoNothing: true,
@@ -107,13 +113,19 @@ func (r *domainRepository) BulkInsert(ctx context.Context, domains []*model.Doma
 	return nil
 }
 
-// BulkUpdateStatus updates all domains from one status to another in a single query
-func (r *domainRepository) BulkUpdateStatus(ctx context.Context, fromStatus, toStatus model.Status) error {
+// BulkUpdateStatus updates the given domains from one status to another in a single query
+func (r *domainRepository) BulkUpdateStatus(ctx context.Context, ids []int, fromStatus, toStatus model.Status) error {
+	if len(ids) == 0 {
+		return nil
+	}
 	err := r.getDb(ctx).Model(&model.Domain{}).
+		Where("id IN ?", ids).
 		Where("status = ?", fromStatus).
 		Updates(map[string]interface{}{
-			"status":     toStatus,
-			"updated_at": time.Now(),
+			"status":            toStatus,
+			"status_updated_at": time.Now(),
+			"attempts":          0,
+			"updated_at":        time.Now(),
 		}).Error
 	if err != nil {
 		return fmt.Errorf("failed to bulk update status: %w", err)
@@ -121,6 +133,39 @@ func (r *domainRepository) BulkUpdateStatus(ctx context.Context, fromStatus, toS
 	return nil
 }
 
+// UpdateMovedAway 移転したとみなした日時を記録する。atがnilの場合は戻ってきたものとして消す
+func (r *domainRepository) UpdateMovedAway(ctx context.Context, names []string, at *time.Time) error {
+	// ReverseIPの1ページは1万件あるため、IN句が長くなりすぎないように分けて更新する
+	for chunk := range slices.Chunk(names, nameInBatchSize) {
+		db := r.getDb(ctx).Model(&model.Domain{}).Where("name IN ?", chunk)
+		if at != nil {
+			db = db.Where("moved_away_at IS NULL")
+		} else {
+			db = db.Where("moved_away_at IS NOT NULL")
+		}
+		if err := db.Update("moved_away_at", at).Error; err != nil {
+			return fmt.Errorf("failed to update moved away: %w", err)
+		}
+	}
+	return nil
+}
+
+// UpdateFingerprint CMSの判定結果だけを書き込む。パイプラインが更新するステータスなどは触らない
+func (r *domainRepository) UpdateFingerprint(ctx context.Context, d *model.Domain) error {
+	err := r.getDb(ctx).Model(&model.Domain{}).
+		Where("id = ?", d.ID).
+		Updates(map[string]interface{}{
+			"cms":              d.CMS,
+			"cms_version":      d.CMSVersion,
+			"hosting_ip":       d.HostingIP,
+			"fingerprinted_at": d.FingerprintedAt,
+		}).Error
+	if err != nil {
+		return fmt.Errorf("failed to update fingerprint: %w", err)
+	}
+	return nil
+}
+
 func (r *domainRepository) Delete(ctx context.Context, f DomainFilter) error {
 	err := f.Apply(r.db.WithContext(ctx)).Delete(&model.Domain{}).Error
 	if err != nil {
@@ -137,19 +182,31 @@ func (r *domainRepository) getDb(ctx context.Context) *gorm.DB {
 }
 
 type DomainFilter struct {
-	ID          *int
-	PartialName *string
-	Name        *string
-	Target      *string
-	CanView     *bool
-	IsJapan     *bool
-	IsSend      *bool
-	OwnerID     *string
-	Industry    *string
-	IsSSL       *bool
-	Status      *model.Status
-	Limit       *int
-	Offset      *int
+	ID                  *int
+	PartialName         *string
+	Name                *string
+	Names               []string
+	Target              *string
+	CanView             *bool
+	IsJapan             *bool
+	IsSend              *bool
+	OwnerID             *string
+	HasOwnerID          *bool
+	Platform            *string
+	CMS                 *string
+	HostingIP           *string
+	Fingerprinted       *bool
+	Industry            *string
+	IndustryCode        *string
+	IsSSL               *bool
+	MovedAway           *bool
+	Status              *model.Status
+	Statuses            []model.Status
+	NotStatuses         []model.Status
+	StatusUpdatedBefore *time.Time
+	OrderBy             []string
+	Limit               *int
+	Offset              *int
 }
 
 func (d *DomainFilter) Apply(db *gorm.DB) *gorm.DB {
@@ -162,6 +219,9 @@ func (d *DomainFilter) Apply(db *gorm.DB) *gorm.DB {
 	if d.Name != nil {
 		db = db.Where("name = ?", *d.Name)
 	}
+	if len(d.Names) > 0 {
+		db = db.Where("name IN ?", d.Names)
+	}
 	if d.Target != nil {
 		db = db.Where("target = ?", *d.Target)
 	}
@@ -174,18 +234,66 @@ func (d *DomainFilter) Apply(db *gorm.DB) *gorm.DB {
 	if d.OwnerID != nil {
 		db = db.Where("owner_id = ?", *d.OwnerID)
 	}
+	if d.HasOwnerID != nil {
+		if *d.HasOwnerID {
+			db = db.Where("owner_id NOT IN ?", entity.EmptyWixOwnerIDs)
+		} else {
+			db = db.Where("owner_id IN ?", entity.EmptyWixOwnerIDs)
+		}
+	}
+	if d.Platform != nil {
+		db = db.Where("platform = ?", *d.Platform)
+	}
+	if d.CMS != nil {
+		db = db.Where("cms = ?", *d.CMS)
+	}
+	if d.HostingIP != nil {
+		db = db.Where("hosting_ip = ?", *d.HostingIP)
+	}
+	if d.Fingerprinted != nil {
+		if *d.Fingerprinted {
+			db = db.Where("fingerprinted_at IS NOT NULL")
+		} else {
+			db = db.Where("fingerprinted_at IS NULL")
+		}
+	}
 	if d.Industry != nil {
 		db = db.Where("industry = ?", *d.Industry)
 	}
+	if d.IndustryCode != nil {
+		// 大分類コードの場合は配下の中分類のいずれかに紐付くもの
+		db = db.Where("id IN (SELECT di.domain_id FROM domain_industries di "+
+			"JOIN industries i ON i.code = di.industry_code WHERE i.code = ? OR i.parent_code = ?)",
+			*d.IndustryCode, *d.IndustryCode)
+	}
 	if d.IsSend != nil {
 		db = db.Where("is_send = ?", *d.IsSend)
 	}
 	if d.IsSSL != nil {
 		db = db.Where("is_ssl = ?", *d.IsSSL)
 	}
+	if d.MovedAway != nil {
+		if *d.MovedAway {
+			db = db.Where("moved_away_at IS NOT NULL")
+		} else {
+			db = db.Where("moved_away_at IS NULL")
+		}
+	}
 	if d.Status != nil {
 		db = db.Where("status = ?", *d.Status)
 	}
+	if len(d.Statuses) > 0 {
+		db = db.Where("status IN ?", d.Statuses)
+	}
+	if len(d.NotStatuses) > 0 {
+		db = db.Where("status NOT IN ?", d.NotStatuses)
+	}
+	if d.StatusUpdatedBefore != nil {
+		db = db.Where("status_updated_at <= ?", *d.StatusUpdatedBefore)
+	}
+	for _, order := range d.OrderBy {
+		db = db.Order(order)
+	}
 	if d.Limit != nil {
 		db = db.Limit(*d.Limit)
 		if d.Offset != nil {
@@ -0,0 +1,143 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/zuxt268/sales/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// nameInBatchSize ドメイン名の IN 句に一度に渡す件数の上限
+const nameInBatchSize = 1000
+
+type DomainSightingRepository interface {
+	Upsert(ctx context.Context, sightings []*model.DomainSighting) error
+	MarkMovedAway(ctx context.Context, targetIP string, notSeenSince, at time.Time) ([]string, error)
+	FindActiveDomains(ctx context.Context, names []string) ([]string, error)
+	FindAll(ctx context.Context, f DomainSightingFilter) ([]*model.DomainSighting, error)
+	CountByTargetIPAndStatus(ctx context.Context, targetIPs []string) ([]*model.DomainStatusCount, error)
+}
+
+type domainSightingRepository struct {
+	db *gorm.DB
+}
+
+func NewDomainSightingRepository(db *gorm.DB) DomainSightingRepository {
+	return &domainSightingRepository{
+		db: db,
+	}
+}
+
+// Upsert ドメインとターゲットのIPの組ごとに、初めてなら作り、既にあれば最後に見つけた日時を更新して移転の記録を消す
+// 取得元が名前解決の日を返さなかった場合は前の値を残す
+func (r *domainSightingRepository) Upsert(ctx context.Context, sightings []*model.DomainSighting) error {
+	if len(sightings) == 0 {
+		return nil
+	}
+	err := r.getDb(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "domain"}, {Name: "target_ip"}},
+		DoUpdates: clause.Assignments(map[string]any{
+			"last_resolved": gorm.Expr("COALESCE(VALUES(last_resolved), last_resolved)"),
+			"last_seen":     gorm.Expr("VALUES(last_seen)"),
+			"moved_away_at": nil,
+		}),
+	}).CreateInBatches(sightings, 500).Error
+	if err != nil {
+		return fmt.Errorf("failed to upsert domain sightings: %w", err)
+	}
+	return nil
+}
+
+// MarkMovedAway targetIPでnotSeenSince以降に見つからなかったドメインに移転した日時を記録し、そのドメイン名を返す
+func (r *domainSightingRepository) MarkMovedAway(ctx context.Context, targetIP string, notSeenSince, at time.Time) ([]string, error) {
+	db := r.getDb(ctx)
+	var names []string
+	err := db.Model(&model.DomainSighting{}).
+		Where("target_ip = ? AND last_seen < ? AND moved_away_at IS NULL", targetIP, notSeenSince).
+		Pluck("domain", &names).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to find stale domain sightings: %w", err)
+	}
+	if len(names) == 0 {
+		return names, nil
+	}
+	err = db.Model(&model.DomainSighting{}).
+		Where("target_ip = ? AND domain IN ? AND moved_away_at IS NULL", targetIP, names).
+		Update("moved_away_at", at).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to mark domain sightings moved away: %w", err)
+	}
+	return names, nil
+}
+
+// FindActiveDomains namesのうち、移転していないターゲットで見つかっているもの
+func (r *domainSightingRepository) FindActiveDomains(ctx context.Context, names []string) ([]string, error) {
+	active := []string{}
+	if len(names) == 0 {
+		return active, nil
+	}
+	for chunk := range slices.Chunk(names, nameInBatchSize) {
+		var found []string
+		err := r.getDb(ctx).Model(&model.DomainSighting{}).
+			Where("domain IN ? AND moved_away_at IS NULL", chunk).
+			Distinct().Pluck("domain", &found).Error
+		if err != nil {
+			return nil, fmt.Errorf("failed to find active domain sightings: %w", err)
+		}
+		active = append(active, found...)
+	}
+	return active, nil
+}
+
+func (r *domainSightingRepository) FindAll(ctx context.Context, f DomainSightingFilter) ([]*model.DomainSighting, error) {
+	var sightings []*model.DomainSighting
+	err := f.Apply(r.getDb(ctx)).Order("last_seen DESC").Find(&sightings).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to find domain sightings: %w", err)
+	}
+	return sightings, nil
+}
+
+// CountByTargetIPAndStatus ターゲットのIPごと・ステータスごとに、そのIPで見つけたドメインの数を数える
+func (r *domainSightingRepository) CountByTargetIPAndStatus(ctx context.Context, targetIPs []string) ([]*model.DomainStatusCount, error) {
+	cs := []*model.DomainStatusCount{}
+	if len(targetIPs) == 0 {
+		return cs, nil
+	}
+	err := r.getDb(ctx).Table("domain_sightings AS s").
+		Joins("JOIN domains AS d ON d.name = s.domain").
+		Where("s.target_ip IN ?", targetIPs).
+		Select("s.target_ip AS target_ip, d.status AS status, COUNT(*) AS count").
+		Group("s.target_ip, d.status").
+		Scan(&cs).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to count domains by target ip and status: %w", err)
+	}
+	return cs, nil
+}
+
+func (r *domainSightingRepository) getDb(ctx context.Context) *gorm.DB {
+	if v, ok := ctx.Value(TxKey{}).(*gorm.DB); ok {
+		return v.WithContext(ctx)
+	}
+	return r.db.WithContext(ctx)
+}
+
+type DomainSightingFilter struct {
+	Domain   *string
+	TargetIP *string
+}
+
+func (f *DomainSightingFilter) Apply(db *gorm.DB) *gorm.DB {
+	if f.Domain != nil {
+		db = db.Where("domain = ?", *f.Domain)
+	}
+	if f.TargetIP != nil {
+		db = db.Where("target_ip = ?", *f.TargetIP)
+	}
+	return db
+}
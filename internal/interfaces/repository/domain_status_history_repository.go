@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/zuxt268/sales/internal/model"
+	"gorm.io/gorm"
+)
+
+type DomainStatusHistoryRepository interface {
+	Create(ctx context.Context, history *model.DomainStatusHistory) error
+	BulkCreate(ctx context.Context, histories []*model.DomainStatusHistory) error
+	FindAll(ctx context.Context, f DomainStatusHistoryFilter) ([]*model.DomainStatusHistory, error)
+}
+
+type domainStatusHistoryRepository struct {
+	db *gorm.DB
+}
+
+func NewDomainStatusHistoryRepository(db *gorm.DB) DomainStatusHistoryRepository {
+	return &domainStatusHistoryRepository{
+		db: db,
+	}
+}
+
+func (r *domainStatusHistoryRepository) Create(ctx context.Context, history *model.DomainStatusHistory) error {
+	err := r.getDb(ctx).Create(history).Error
+	if err != nil {
+		return fmt.Errorf("failed to create domain status history: %w", err)
+	}
+	return nil
+}
+
+func (r *domainStatusHistoryRepository) BulkCreate(ctx context.Context, histories []*model.DomainStatusHistory) error {
+	if len(histories) == 0 {
+		return nil
+	}
+	err := r.getDb(ctx).CreateInBatches(histories, 500).Error
+	if err != nil {
+		return fmt.Errorf("failed to create domain status histories: %w", err)
+	}
+	return nil
+}
+
+func (r *domainStatusHistoryRepository) FindAll(ctx context.Context, f DomainStatusHistoryFilter) ([]*model.DomainStatusHistory, error) {
+	var hs []*model.DomainStatusHistory
+	err := f.Apply(r.getDb(ctx)).Order("created_at DESC").Order("id DESC").Find(&hs).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch domain status histories: %w", err)
+	}
+	return hs, nil
+}
+
+func (r *domainStatusHistoryRepository) getDb(ctx context.Context) *gorm.DB {
+	if v, ok := ctx.Value(TxKey{}).(*gorm.DB); ok {
+		return v.WithContext(ctx)
+	}
+	return r.db.WithContext(ctx)
+}
+
+type DomainStatusHistoryFilter struct {
+	DomainID *int
+	Limit    *int
+	Offset   *int
+}
+
+func (f *DomainStatusHistoryFilter) Apply(db *gorm.DB) *gorm.DB {
+	if f.DomainID != nil {
+		db = db.Where("domain_id = ?", *f.DomainID)
+	}
+	if f.Limit != nil {
+		db = db.Limit(*f.Limit)
+		if f.Offset != nil {
+			db = db.Offset(*f.Offset)
+		}
+	}
+	return db
+}
@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/zuxt268/sales/internal/model"
+	"gorm.io/gorm"
+)
+
+type IndustryRepository interface {
+	FindAll(ctx context.Context) ([]*model.Industry, error)
+	CountDomains(ctx context.Context) ([]*model.IndustryCount, error)
+	CountHomstas(ctx context.Context) ([]*model.IndustryCount, error)
+	ReplaceDomainIndustries(ctx context.Context, domainID int, codes []string) error
+	FindDomainIndustryCodes(ctx context.Context, domainIDs []int) (map[int][]string, error)
+	ReplaceHomstaIndustries(ctx context.Context, homstaID int, codes []string) error
+}
+
+type industryRepository struct {
+	db *gorm.DB
+}
+
+func NewIndustryRepository(db *gorm.DB) IndustryRepository {
+	return &industryRepository{
+		db: db,
+	}
+}
+
+func (r *industryRepository) FindAll(ctx context.Context) ([]*model.Industry, error) {
+	var is []*model.Industry
+	err := r.getDb(ctx).Order("level = 'middle'").Order("sort_order").Find(&is).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch industries: %w", err)
+	}
+	return is, nil
+}
+
+func (r *industryRepository) CountDomains(ctx context.Context) ([]*model.IndustryCount, error) {
+	return r.count(ctx, "domain_industries", "domain_id")
+}
+
+func (r *industryRepository) CountHomstas(ctx context.Context) ([]*model.IndustryCount, error) {
+	return r.count(ctx, "homsta_industries", "homsta_id")
+}
+
+// count 業種ごとに紐付く件数を数える。大分類は配下の中分類のいずれかに紐付くものを重複なく数える
+func (r *industryRepository) count(ctx context.Context, table, idColumn string) ([]*model.IndustryCount, error) {
+	var cs []*model.IndustryCount
+	err := r.getDb(ctx).
+		Table("industries i").
+		Select(fmt.Sprintf("i.code AS code, COUNT(DISTINCT t.%s) AS count", idColumn)).
+		Joins("JOIN industries c ON c.code = i.code OR c.parent_code = i.code").
+		Joins(fmt.Sprintf("JOIN %s t ON t.industry_code = c.code", table)).
+		Group("i.code").
+		Scan(&cs).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to count %s: %w", table, err)
+	}
+	return cs, nil
+}
+
+func (r *industryRepository) ReplaceDomainIndustries(ctx context.Context, domainID int, codes []string) error {
+	db := r.getDb(ctx)
+	if err := db.Where("domain_id = ?", domainID).Delete(&model.DomainIndustry{}).Error; err != nil {
+		return fmt.Errorf("failed to delete domain industries: %w", err)
+	}
+	if len(codes) == 0 {
+		return nil
+	}
+	rows := make([]*model.DomainIndustry, 0, len(codes))
+	for _, c := range codes {
+		rows = append(rows, &model.DomainIndustry{DomainID: domainID, IndustryCode: c})
+	}
+	if err := db.Create(rows).Error; err != nil {
+		return fmt.Errorf("failed to insert domain industries: %w", err)
+	}
+	return nil
+}
+
+// FindDomainIndustryCodes ドメインごとに紐付いた業種コードをコード順に返す
+func (r *industryRepository) FindDomainIndustryCodes(ctx context.Context, domainIDs []int) (map[int][]string, error) {
+	codes := make(map[int][]string, len(domainIDs))
+	if len(domainIDs) == 0 {
+		return codes, nil
+	}
+	var rows []*model.DomainIndustry
+	err := r.getDb(ctx).
+		Where("domain_id IN ?", domainIDs).
+		Order("domain_id").
+		Order("industry_code").
+		Find(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch domain industries: %w", err)
+	}
+	for _, row := range rows {
+		codes[row.DomainID] = append(codes[row.DomainID], row.IndustryCode)
+	}
+	return codes, nil
+}
+
+func (r *industryRepository) ReplaceHomstaIndustries(ctx context.Context, homstaID int, codes []string) error {
+	db := r.getDb(ctx)
+	if err := db.Where("homsta_id = ?", homstaID).Delete(&model.HomstaIndustry{}).Error; err != nil {
+		return fmt.Errorf("failed to delete homsta industries: %w", err)
+	}
+	if len(codes) == 0 {
+		return nil
+	}
+	rows := make([]*model.HomstaIndustry, 0, len(codes))
+	for _, c := range codes {
+		rows = append(rows, &model.HomstaIndustry{HomstaID: homstaID, IndustryCode: c})
+	}
+	if err := db.Create(rows).Error; err != nil {
+		return fmt.Errorf("failed to insert homsta industries: %w", err)
+	}
+	return nil
+}
+
+func (r *industryRepository) getDb(ctx context.Context) *gorm.DB {
+	if v, ok := ctx.Value(TxKey{}).(*gorm.DB); ok {
+		return v.WithContext(ctx)
+	}
+	return r.db.WithContext(ctx)
+}
@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/zuxt268/sales/internal/model"
+	"gorm.io/gorm"
+)
+
+type LLMUsageRepository interface {
+	Create(ctx context.Context, usage *model.LLMUsage) error
+	Total(ctx context.Context, f LLMUsageFilter) (*model.LLMUsageSummary, error)
+	Summarize(ctx context.Context, f LLMUsageFilter) ([]*model.LLMUsageSummary, error)
+}
+
+type llmUsageRepository struct {
+	db *gorm.DB
+}
+
+func NewLLMUsageRepository(db *gorm.DB) LLMUsageRepository {
+	return &llmUsageRepository{
+		db: db,
+	}
+}
+
+// Create 呼び出し元のトランザクションがロールバックされても使用量は残すため、トランザクション外で保存する
+func (r *llmUsageRepository) Create(ctx context.Context, usage *model.LLMUsage) error {
+	err := r.db.WithContext(ctx).Create(usage).Error
+	if err != nil {
+		return fmt.Errorf("failed to create llm usage: %w", err)
+	}
+	return nil
+}
+
+func (r *llmUsageRepository) Total(ctx context.Context, f LLMUsageFilter) (*model.LLMUsageSummary, error) {
+	s := &model.LLMUsageSummary{}
+	err := f.Apply(r.getDb(ctx).Model(&model.LLMUsage{})).
+		Select("COUNT(*) AS calls, " +
+			"COALESCE(SUM(prompt_tokens), 0) AS prompt_tokens, " +
+			"COALESCE(SUM(completion_tokens), 0) AS completion_tokens, " +
+			"COALESCE(SUM(total_tokens), 0) AS total_tokens, " +
+			"COALESCE(SUM(cost), 0) AS cost").
+		Scan(s).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to total llm usages: %w", err)
+	}
+	return s, nil
+}
+
+func (r *llmUsageRepository) Summarize(ctx context.Context, f LLMUsageFilter) ([]*model.LLMUsageSummary, error) {
+	var ss []*model.LLMUsageSummary
+	err := f.Apply(r.getDb(ctx).Model(&model.LLMUsage{})).
+		Select("DATE_FORMAT(created_at, '%Y-%m-%d') AS date, feature, COUNT(*) AS calls, " +
+			"SUM(prompt_tokens) AS prompt_tokens, " +
+			"SUM(completion_tokens) AS completion_tokens, " +
+			"SUM(total_tokens) AS total_tokens, " +
+			"SUM(cost) AS cost").
+		Group("date, feature").
+		Order("date DESC, feature").
+		Scan(&ss).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to summarize llm usages: %w", err)
+	}
+	return ss, nil
+}
+
+func (r *llmUsageRepository) getDb(ctx context.Context) *gorm.DB {
+	if v, ok := ctx.Value(TxKey{}).(*gorm.DB); ok {
+		return v.WithContext(ctx)
+	}
+	return r.db.WithContext(ctx)
+}
+
+type LLMUsageFilter struct {
+	From    *time.Time
+	To      *time.Time
+	Feature *model.LLMFeature
+}
+
+func (f *LLMUsageFilter) Apply(db *gorm.DB) *gorm.DB {
+	if f.From != nil {
+		db = db.Where("created_at >= ?", *f.From)
+	}
+	if f.To != nil {
+		db = db.Where("created_at < ?", *f.To)
+	}
+	if f.Feature != nil {
+		db = db.Where("feature = ?", *f.Feature)
+	}
+	return db
+}
@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/zuxt268/sales/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type OutboxRepository interface {
+	Create(ctx context.Context, msg *model.OutboxMessage) error
+	FindPending(ctx context.Context, now time.Time, limit int) ([]*model.OutboxMessage, error)
+	Save(ctx context.Context, msg *model.OutboxMessage) error
+}
+
+type outboxRepository struct {
+	db *gorm.DB
+}
+
+func NewOutboxRepository(db *gorm.DB) OutboxRepository {
+	return &outboxRepository{
+		db: db,
+	}
+}
+
+func (r *outboxRepository) Create(ctx context.Context, msg *model.OutboxMessage) error {
+	err := r.getDb(ctx).Create(msg).Error
+	if err != nil {
+		return fmt.Errorf("failed to create outbox message: %w", err)
+	}
+	return nil
+}
+
+// FindPending 送信日時を迎えた未送信のメッセージを古い順に取得する
+func (r *outboxRepository) FindPending(ctx context.Context, now time.Time, limit int) ([]*model.OutboxMessage, error) {
+	var ms []*model.OutboxMessage
+	err := r.getDb(ctx).
+		Where("status = ?", model.OutboxStatusPending).
+		Where("next_attempt_at <= ?", now).
+		Order("id").
+		Limit(limit).
+		Find(&ms).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch outbox messages: %w", err)
+	}
+	return ms, nil
+}
+
+func (r *outboxRepository) Save(ctx context.Context, msg *model.OutboxMessage) error {
+	err := r.getDb(ctx).Save(msg).Error
+	if err != nil {
+		return fmt.Errorf("failed to save outbox message: %w", err)
+	}
+	return nil
+}
+
+func (r *outboxRepository) getDb(ctx context.Context) *gorm.DB {
+	if v, ok := ctx.Value(TxKey{}).(*gorm.DB); ok {
+		return v.WithContext(ctx)
+	}
+	return r.db.WithContext(ctx)
+}
+
+type ProcessedMessageRepository interface {
+	Exists(ctx context.Context, messageID, consumer string) (bool, error)
+	Create(ctx context.Context, messageID, consumer string) (bool, error)
+}
+
+type processedMessageRepository struct {
+	db *gorm.DB
+}
+
+func NewProcessedMessageRepository(db *gorm.DB) ProcessedMessageRepository {
+	return &processedMessageRepository{
+		db: db,
+	}
+}
+
+func (r *processedMessageRepository) Exists(ctx context.Context, messageID, consumer string) (bool, error) {
+	var count int64
+	err := r.getDb(ctx).Model(&model.ProcessedMessage{}).
+		Where("message_id = ? AND consumer = ?", messageID, consumer).
+		Count(&count).Error
+	if err != nil {
+		return false, fmt.Errorf("failed to check processed message: %w", err)
+	}
+	return count > 0, nil
+}
+
+// Create 処理済みとして記録する。既に記録されていた場合はfalseを返す
+func (r *processedMessageRepository) Create(ctx context.Context, messageID, consumer string) (bool, error) {
+	res := r.getDb(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&model.ProcessedMessage{
+		MessageID: messageID,
+		Consumer:  consumer,
+	})
+	if res.Error != nil {
+		return false, fmt.Errorf("failed to create processed message: %w", res.Error)
+	}
+	return res.RowsAffected > 0, nil
+}
+
+func (r *processedMessageRepository) getDb(ctx context.Context) *gorm.DB {
+	if v, ok := ctx.Value(TxKey{}).(*gorm.DB); ok {
+		return v.WithContext(ctx)
+	}
+	return r.db.WithContext(ctx)
+}
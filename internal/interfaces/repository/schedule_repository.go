@@ -0,0 +1,163 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/zuxt268/sales/internal/entity"
+	"github.com/zuxt268/sales/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ScheduleRepository interface {
+	FindAll(ctx context.Context) ([]*model.Schedule, error)
+	Get(ctx context.Context, name string) (*model.Schedule, error)
+	CreateIfNotExists(ctx context.Context, s *model.Schedule) error
+	Save(ctx context.Context, s *model.Schedule) error
+	Claim(ctx context.Context, s *model.Schedule, next time.Time) (bool, error)
+}
+
+type scheduleRepository struct {
+	db *gorm.DB
+}
+
+func NewScheduleRepository(db *gorm.DB) ScheduleRepository {
+	return &scheduleRepository{
+		db: db,
+	}
+}
+
+func (r *scheduleRepository) FindAll(ctx context.Context) ([]*model.Schedule, error) {
+	var ss []*model.Schedule
+	err := r.getDb(ctx).Order("name").Find(&ss).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch schedules: %w", err)
+	}
+	return ss, nil
+}
+
+func (r *scheduleRepository) Get(ctx context.Context, name string) (*model.Schedule, error) {
+	var s model.Schedule
+	err := r.getDb(ctx).Where("name = ?", name).First(&s).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, entity.WrapNotFound("schedule")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get schedule: %w", err)
+	}
+	return &s, nil
+}
+
+// CreateIfNotExists 同じ名前のスケジュールがなければ作る。あればDBの設定を優先して何もしない
+func (r *scheduleRepository) CreateIfNotExists(ctx context.Context, s *model.Schedule) error {
+	err := r.getDb(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(s).Error
+	if err != nil {
+		return fmt.Errorf("failed to create schedule: %w", err)
+	}
+	return nil
+}
+
+func (r *scheduleRepository) Save(ctx context.Context, s *model.Schedule) error {
+	err := r.getDb(ctx).Save(s).Error
+	if err != nil {
+		return fmt.Errorf("failed to save schedule: %w", err)
+	}
+	return nil
+}
+
+// Claim 読み込んだ時から next_run_at が変わっていなければ次の実行日時を進める
+// 複数のインスタンスが同じ実行日時を見つけても、trueを返すのは1つだけになる
+func (r *scheduleRepository) Claim(ctx context.Context, s *model.Schedule, next time.Time) (bool, error) {
+	db := r.getDb(ctx).Model(&model.Schedule{}).Where("id = ?", s.ID)
+	if s.NextRunAt == nil {
+		db = db.Where("next_run_at IS NULL")
+	} else {
+		db = db.Where("next_run_at = ?", *s.NextRunAt)
+	}
+	res := db.Update("next_run_at", next)
+	if res.Error != nil {
+		return false, fmt.Errorf("failed to claim schedule: %w", res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return false, nil
+	}
+	s.NextRunAt = &next
+	return true, nil
+}
+
+func (r *scheduleRepository) getDb(ctx context.Context) *gorm.DB {
+	if v, ok := ctx.Value(TxKey{}).(*gorm.DB); ok {
+		return v.WithContext(ctx)
+	}
+	return r.db.WithContext(ctx)
+}
+
+type ScheduleRunRepository interface {
+	Create(ctx context.Context, run *model.ScheduleRun) error
+	Save(ctx context.Context, run *model.ScheduleRun) error
+	FindAll(ctx context.Context, f ScheduleRunFilter) ([]*model.ScheduleRun, error)
+}
+
+type scheduleRunRepository struct {
+	db *gorm.DB
+}
+
+func NewScheduleRunRepository(db *gorm.DB) ScheduleRunRepository {
+	return &scheduleRunRepository{
+		db: db,
+	}
+}
+
+func (r *scheduleRunRepository) Create(ctx context.Context, run *model.ScheduleRun) error {
+	err := r.getDb(ctx).Create(run).Error
+	if err != nil {
+		return fmt.Errorf("failed to create schedule run: %w", err)
+	}
+	return nil
+}
+
+func (r *scheduleRunRepository) Save(ctx context.Context, run *model.ScheduleRun) error {
+	err := r.getDb(ctx).Save(run).Error
+	if err != nil {
+		return fmt.Errorf("failed to save schedule run: %w", err)
+	}
+	return nil
+}
+
+func (r *scheduleRunRepository) FindAll(ctx context.Context, f ScheduleRunFilter) ([]*model.ScheduleRun, error) {
+	var rs []*model.ScheduleRun
+	err := f.Apply(r.getDb(ctx)).Order("started_at DESC").Order("id DESC").Find(&rs).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch schedule runs: %w", err)
+	}
+	return rs, nil
+}
+
+func (r *scheduleRunRepository) getDb(ctx context.Context) *gorm.DB {
+	if v, ok := ctx.Value(TxKey{}).(*gorm.DB); ok {
+		return v.WithContext(ctx)
+	}
+	return r.db.WithContext(ctx)
+}
+
+type ScheduleRunFilter struct {
+	ScheduleName *string
+	Limit        *int
+	Offset       *int
+}
+
+func (f *ScheduleRunFilter) Apply(db *gorm.DB) *gorm.DB {
+	if f.ScheduleName != nil {
+		db = db.Where("schedule_name = ?", *f.ScheduleName)
+	}
+	if f.Limit != nil {
+		db = db.Limit(*f.Limit)
+		if f.Offset != nil {
+			db = db.Offset(*f.Offset)
+		}
+	}
+	return db
+}
@@ -110,8 +110,11 @@ func (r *targetRepository) getDb(ctx context.Context) *gorm.DB {
 type TargetFilter struct {
 	ID                  *int
 	IP                  *string
+	IPs                 []string
 	Name                *string
 	NotName             *string
+	Platform            *string
+	NotPlatform         *string
 	Status              *model.TargetStatus
 	NotStatus           *model.TargetStatus
 	Limit               *int
@@ -127,12 +130,21 @@ func (f *TargetFilter) Apply(db *gorm.DB) *gorm.DB {
 	if f.IP != nil {
 		db = db.Where("ip = ?", *f.IP)
 	}
+	if len(f.IPs) > 0 {
+		db = db.Where("ip IN ?", f.IPs)
+	}
 	if f.Name != nil {
 		db = db.Where("name = ?", *f.Name)
 	}
 	if f.NotName != nil {
 		db = db.Where("name != ?", *f.NotName)
 	}
+	if f.Platform != nil {
+		db = db.Where("platform = ?", *f.Platform)
+	}
+	if f.NotPlatform != nil {
+		db = db.Where("platform != ?", *f.NotPlatform)
+	}
 	if f.Status != nil {
 		db = db.Where("status = ?", *f.Status)
 	}
@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/zuxt268/sales/internal/entity"
+	"github.com/zuxt268/sales/internal/model"
+	"gorm.io/gorm"
+)
+
+type TargetScanRepository interface {
+	Get(ctx context.Context, f TargetScanFilter) (*model.TargetScan, error)
+	FindAll(ctx context.Context, f TargetScanFilter) ([]*model.TargetScan, error)
+	Save(ctx context.Context, scan *model.TargetScan) error
+}
+
+type targetScanRepository struct {
+	db *gorm.DB
+}
+
+func NewTargetScanRepository(db *gorm.DB) TargetScanRepository {
+	return &targetScanRepository{
+		db: db,
+	}
+}
+
+// Get 条件に合う記録のうち最も新しいもの
+func (r *targetScanRepository) Get(ctx context.Context, f TargetScanFilter) (*model.TargetScan, error) {
+	scan := &model.TargetScan{}
+	err := f.Apply(r.getDb(ctx)).Order("started_at DESC, id DESC").First(scan).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, entity.WrapNotFound("target scan")
+		}
+		return nil, fmt.Errorf("failed to fetch target scan: %w", err)
+	}
+	return scan, nil
+}
+
+// FindAll 新しい順。PerTargetを指定した場合はターゲットごとに新しいものからその件数まで
+func (r *targetScanRepository) FindAll(ctx context.Context, f TargetScanFilter) ([]*model.TargetScan, error) {
+	var scans []*model.TargetScan
+	db := f.Apply(r.getDb(ctx).Model(&model.TargetScan{}))
+	if f.PerTarget != nil {
+		ranked := db.Select("target_scans.*, ROW_NUMBER() OVER (PARTITION BY target_id ORDER BY started_at DESC, id DESC) AS scan_rank")
+		db = r.getDb(ctx).Table("(?) AS ranked", ranked).Where("scan_rank <= ?", *f.PerTarget)
+	}
+	err := db.Order("started_at DESC, id DESC").Find(&scans).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch target scans: %w", err)
+	}
+	return scans, nil
+}
+
+func (r *targetScanRepository) Save(ctx context.Context, scan *model.TargetScan) error {
+	if err := r.getDb(ctx).Save(scan).Error; err != nil {
+		return fmt.Errorf("failed to save target scan: %w", err)
+	}
+	return nil
+}
+
+func (r *targetScanRepository) getDb(ctx context.Context) *gorm.DB {
+	if v, ok := ctx.Value(TxKey{}).(*gorm.DB); ok {
+		return v.WithContext(ctx)
+	}
+	return r.db.WithContext(ctx)
+}
+
+type TargetScanFilter struct {
+	TargetID   *int
+	TargetIDs  []int
+	Unfinished *bool
+	Limit      *int
+	PerTarget  *int // ターゲットごとの件数の上限。Applyでは使わずFindAllで絞る
+}
+
+func (f *TargetScanFilter) Apply(db *gorm.DB) *gorm.DB {
+	if f.TargetID != nil {
+		db = db.Where("target_id = ?", *f.TargetID)
+	}
+	if len(f.TargetIDs) > 0 {
+		db = db.Where("target_id IN ?", f.TargetIDs)
+	}
+	if f.Unfinished != nil {
+		if *f.Unfinished {
+			db = db.Where("finished_at IS NULL")
+		} else {
+			db = db.Where("finished_at IS NOT NULL")
+		}
+	}
+	if f.Limit != nil {
+		db = db.Limit(*f.Limit)
+	}
+	return db
+}
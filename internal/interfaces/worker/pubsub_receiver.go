@@ -0,0 +1,44 @@
+package worker
+
+import (
+	"context"
+
+	"cloud.google.com/go/pubsub"
+	"github.com/zuxt268/sales/internal/infrastructure"
+)
+
+// pubSubReceiver Pub/Subのサブスクリプションからpullで受信する
+type pubSubReceiver struct {
+	pubSubClient   infrastructure.PubSubClient
+	subscriptionID string
+	maxOutstanding int
+}
+
+func NewPubSubReceiver(
+	pubSubClient infrastructure.PubSubClient,
+	subscriptionID string,
+	maxOutstanding int,
+) Receiver {
+	return &pubSubReceiver{
+		pubSubClient:   pubSubClient,
+		subscriptionID: subscriptionID,
+		maxOutstanding: maxOutstanding,
+	}
+}
+
+func (r *pubSubReceiver) Receive(ctx context.Context, handle func(context.Context, *Message)) error {
+	return r.pubSubClient.Subscribe(ctx, r.subscriptionID, r.maxOutstanding, func(ctx context.Context, m *pubsub.Message) {
+		attempt := 0
+		if m.DeliveryAttempt != nil {
+			attempt = *m.DeliveryAttempt
+		}
+		handle(ctx, &Message{
+			ID:              m.ID,
+			Data:            m.Data,
+			Attributes:      m.Attributes,
+			DeliveryAttempt: attempt,
+			Ack:             m.Ack,
+			Nack:            m.Nack,
+		})
+	})
+}
@@ -0,0 +1,212 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/zuxt268/sales/internal/interfaces/adapter"
+)
+
+// redisStreamBlock 新しいメッセージを待つ時間。この間隔でctxのキャンセルと再配信の対象を確認する
+const redisStreamBlock = 5 * time.Second
+
+// redisStreamClaimStart XAUTOCLAIMで未Ackのメッセージを先頭から探すときの開始ID
+const redisStreamClaimStart = "0-0"
+
+// redisStreamReceiver Redis Streamsのコンシューマーグループから受信する
+// Ackされないまま visibilityTimeout を過ぎたメッセージは、Nackされたものも含めて再配信する
+type redisStreamReceiver struct {
+	client            *redis.Client
+	stream            string
+	group             string
+	consumer          string
+	maxOutstanding    int
+	visibilityTimeout time.Duration
+	block             time.Duration
+	// claimCursor 次にXAUTOCLAIMで探し始めるID。1回で未Ackのメッセージを探しきれない場合に続きから探す
+	// fetchはReceiveのループからしか呼ばないため排他しない
+	claimCursor string
+}
+
+func NewRedisStreamReceiver(
+	client *redis.Client,
+	stream string,
+	group string,
+	consumer string,
+	maxOutstanding int,
+	visibilityTimeout time.Duration,
+) Receiver {
+	if maxOutstanding < 1 {
+		maxOutstanding = 1
+	}
+	return &redisStreamReceiver{
+		client:            client,
+		stream:            stream,
+		group:             group,
+		consumer:          consumer,
+		maxOutstanding:    maxOutstanding,
+		visibilityTimeout: visibilityTimeout,
+		block:             redisStreamBlock,
+		claimCursor:       redisStreamClaimStart,
+	}
+}
+
+func (r *redisStreamReceiver) Receive(ctx context.Context, handle func(context.Context, *Message)) error {
+	if err := r.ensureGroup(ctx); err != nil {
+		return err
+	}
+	slog.Info("Starting to receive messages", "stream", r.stream, "group", r.group, "consumer", r.consumer)
+
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	slots := make(chan struct{}, r.maxOutstanding)
+
+	for {
+		// 処理中のメッセージがmaxOutstandingに達していたら空くまで待つ
+		select {
+		case slots <- struct{}{}:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		n := 1
+	acquire:
+		for n < r.maxOutstanding {
+			select {
+			case slots <- struct{}{}:
+				n++
+			default:
+				break acquire
+			}
+		}
+
+		msgs, err := r.fetch(ctx, n)
+		for i := len(msgs); i < n; i++ {
+			<-slots
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			slog.Warn("failed to read stream", "stream", r.stream, "error", err)
+			select {
+			case <-time.After(time.Second):
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+			continue
+		}
+
+		for _, m := range msgs {
+			wg.Add(1)
+			go func(m *Message) {
+				defer func() {
+					<-slots
+					wg.Done()
+				}()
+				handle(ctx, m)
+			}(m)
+		}
+	}
+}
+
+func (r *redisStreamReceiver) ensureGroup(ctx context.Context) error {
+	err := r.client.XGroupCreateMkStream(ctx, r.stream, r.group, "0").Err()
+	if err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		return fmt.Errorf("failed to create consumer group: %w", err)
+	}
+	return nil
+}
+
+// fetch 再配信の対象があればそれを優先し、なければ新しいメッセージを待つ
+func (r *redisStreamReceiver) fetch(ctx context.Context, count int) ([]*Message, error) {
+	claimed, next, err := r.client.XAutoClaim(ctx, &redis.XAutoClaimArgs{
+		Stream:   r.stream,
+		Group:    r.group,
+		Consumer: r.consumer,
+		MinIdle:  r.visibilityTimeout,
+		Start:    r.claimCursor,
+		Count:    int64(count),
+	}).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim pending messages: %w", err)
+	}
+	// 最後まで探すとRedisは0-0を返すため、次は先頭から探し直す
+	r.claimCursor = next
+	if r.claimCursor == "" {
+		r.claimCursor = redisStreamClaimStart
+	}
+	if len(claimed) > 0 {
+		msgs := make([]*Message, 0, len(claimed))
+		for _, xm := range claimed {
+			attempt, err := r.deliveryCount(ctx, xm.ID)
+			if err != nil {
+				return nil, err
+			}
+			msgs = append(msgs, r.toMessage(xm, attempt))
+		}
+		return msgs, nil
+	}
+
+	streams, err := r.client.XReadGroup(ctx, &redis.XReadGroupArgs{
+		Group:    r.group,
+		Consumer: r.consumer,
+		Streams:  []string{r.stream, ">"},
+		Count:    int64(count),
+		Block:    r.block,
+	}).Result()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read group: %w", err)
+	}
+	var msgs []*Message
+	for _, s := range streams {
+		for _, xm := range s.Messages {
+			msgs = append(msgs, r.toMessage(xm, 1))
+		}
+	}
+	return msgs, nil
+}
+
+func (r *redisStreamReceiver) deliveryCount(ctx context.Context, id string) (int, error) {
+	pending, err := r.client.XPendingExt(ctx, &redis.XPendingExtArgs{
+		Stream: r.stream,
+		Group:  r.group,
+		Start:  id,
+		End:    id,
+		Count:  1,
+	}).Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get pending message: %w", err)
+	}
+	if len(pending) == 0 {
+		return 1, nil
+	}
+	return int(pending[0].RetryCount), nil
+}
+
+func (r *redisStreamReceiver) toMessage(xm redis.XMessage, attempt int) *Message {
+	data, _ := xm.Values[adapter.RedisStreamDataField].(string)
+	return &Message{
+		ID:              xm.ID,
+		Data:            []byte(data),
+		DeliveryAttempt: attempt,
+		Ack: func() {
+			// 受信を止めた後でもAckできるようにctxは引き継がない
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			if err := r.client.XAck(ctx, r.stream, r.group, xm.ID).Err(); err != nil {
+				slog.Error("failed to ack message", "stream", r.stream, "message_id", xm.ID, "error", err)
+			}
+		},
+		// Streamsには受信の取り消しがないため、Ackせずに残してvisibilityTimeout後の再配信を待つ
+		Nack: func() {},
+	}
+}
@@ -0,0 +1,91 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/zuxt268/sales/internal/infrastructure"
+	"github.com/zuxt268/sales/internal/interfaces/adapter"
+	"github.com/zuxt268/sales/internal/interfaces/dto/external"
+)
+
+func newTestRedis(t *testing.T) *redis.Client {
+	// Dockerが使えない環境ではスキップする
+	testcontainers.SkipIfProviderIsNotHealthy(t)
+	client, cleanup, err := infrastructure.NewTestContainerRedisClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(cleanup)
+	return client
+}
+
+func newTestRedisReceiver(client *redis.Client, visibilityTimeout time.Duration) Receiver {
+	r := NewRedisStreamReceiver(client, external.DomainPipelineTopic, "test", "consumer-1", 2, visibilityTimeout)
+	r.(*redisStreamReceiver).block = 50 * time.Millisecond
+	return r
+}
+
+func runUntil(t *testing.T, w Worker, cond func() bool) {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- w.Run(ctx) }()
+	assert.Eventually(t, cond, 10*time.Second, 20*time.Millisecond)
+	cancel()
+	assert.NoError(t, <-done)
+}
+
+func TestRedisStreamReceiver_Ack(t *testing.T) {
+	client := newTestRedis(t)
+	ctx := context.Background()
+	queue := adapter.NewRedisQueueAdapter(client, 0)
+	for i := 1; i <= 3; i++ {
+		assert.NoError(t, queue.PublishPipelineMessage(ctx, &external.PipelineMessage{
+			Version: external.PipelineMessageVersion, Type: external.PipelineAnalyze, DomainID: i,
+		}))
+	}
+
+	var handled atomic.Int32
+	w := NewWorker(newTestRedisReceiver(client, time.Minute), queue, testConfig)
+	w.Handle(external.PipelineAnalyze, func(context.Context, *external.PipelineMessage) error {
+		handled.Add(1)
+		return nil
+	})
+	runUntil(t, w, func() bool { return handled.Load() == 3 })
+
+	pending, err := client.XPending(ctx, external.DomainPipelineTopic, "test").Result()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), pending.Count)
+}
+
+func TestRedisStreamReceiver_RetryAndDeadLetter(t *testing.T) {
+	client := newTestRedis(t)
+	ctx := context.Background()
+	queue := adapter.NewRedisQueueAdapter(client, 0)
+	assert.NoError(t, queue.PublishPipelineMessage(ctx, &external.PipelineMessage{
+		Version: external.PipelineMessageVersion, Type: external.PipelineAnalyze, DomainID: 1,
+	}))
+
+	var attempts []int
+	w := NewWorker(newTestRedisReceiver(client, 100*time.Millisecond), queue, testConfig)
+	w.Handle(external.PipelineAnalyze, func(context.Context, *external.PipelineMessage) error {
+		attempts = append(attempts, len(attempts)+1)
+		return errors.New("temporary error")
+	})
+	runUntil(t, w, func() bool {
+		n, _ := client.XLen(ctx, testConfig.DeadLetterTopic).Result()
+		return n == 1
+	})
+
+	// visibilityTimeoutを過ぎて再配信され、MaxAttempts回目でデッドレターに送られる
+	assert.Len(t, attempts, testConfig.MaxAttempts)
+	pending, err := client.XPending(ctx, external.DomainPipelineTopic, "test").Result()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), pending.Count)
+}
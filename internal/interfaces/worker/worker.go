@@ -0,0 +1,160 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/zuxt268/sales/internal/entity"
+	"github.com/zuxt268/sales/internal/interfaces/adapter"
+	"github.com/zuxt268/sales/internal/interfaces/dto/external"
+)
+
+// Message 受信したメッセージ。処理結果に応じてワーカーがAckかNackを1回だけ呼ぶ
+type Message struct {
+	ID              string
+	Data            []byte
+	Attributes      map[string]string
+	DeliveryAttempt int // 配信回数。サブスクリプションにデッドレターの設定がない場合は0
+	Ack             func()
+	Nack            func()
+}
+
+// Receiver メッセージの受信元。ctxがキャンセルされるまでhandleを呼び続ける
+type Receiver interface {
+	Receive(ctx context.Context, handle func(context.Context, *Message)) error
+}
+
+// HandlerFunc メッセージの種類ごとの処理
+// entity.ErrValidationを返した場合は再試行しても成功しないとみなしてデッドレターに送る
+type HandlerFunc func(ctx context.Context, msg *external.PipelineMessage) error
+
+type Worker interface {
+	Handle(msgType external.PipelineMessageType, h HandlerFunc)
+	Run(ctx context.Context) error
+}
+
+type Config struct {
+	Concurrency     int           // 同時に処理するメッセージ数
+	MaxAttempts     int           // この配信回数で失敗したらデッドレターに送る。0は無制限
+	HandlerTimeout  time.Duration // 1件の処理のタイムアウト
+	DeadLetterTopic string
+}
+
+type worker struct {
+	receiver Receiver
+	queue    adapter.Queue
+	cfg      Config
+	handlers map[external.PipelineMessageType]HandlerFunc
+}
+
+func NewWorker(
+	receiver Receiver,
+	queue adapter.Queue,
+	cfg Config,
+) Worker {
+	if cfg.Concurrency < 1 {
+		cfg.Concurrency = 1
+	}
+	return &worker{
+		receiver: receiver,
+		queue:    queue,
+		cfg:      cfg,
+		handlers: make(map[external.PipelineMessageType]HandlerFunc),
+	}
+}
+
+// Handle メッセージの種類に対する処理を登録する
+func (w *worker) Handle(msgType external.PipelineMessageType, h HandlerFunc) {
+	w.handlers[msgType] = h
+}
+
+// Run ctxがキャンセルされるまでメッセージを受信して処理する
+// キャンセル後は新しいメッセージを受け取らず、処理中のメッセージが終わってから戻る
+func (w *worker) Run(ctx context.Context) error {
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, w.cfg.Concurrency)
+
+	err := w.receiver.Receive(ctx, func(ctx context.Context, msg *Message) {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			msg.Nack()
+			return
+		}
+		wg.Add(1)
+		defer func() {
+			<-sem
+			wg.Done()
+		}()
+		w.dispatch(ctx, msg)
+	})
+
+	wg.Wait()
+	if err != nil && !errors.Is(err, context.Canceled) {
+		return err
+	}
+	return nil
+}
+
+func (w *worker) dispatch(ctx context.Context, msg *Message) {
+	// 停止の指示が来ても処理中のメッセージは最後まで処理する
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), w.cfg.HandlerTimeout)
+	defer cancel()
+
+	pm, err := external.DecodePipelineMessage(msg.Data)
+	if err != nil {
+		w.deadLetter(ctx, msg, "", fmt.Errorf("%v: %w", err, entity.ErrValidation))
+		return
+	}
+	msgType := pm.Type
+	h, ok := w.handlers[msgType]
+	if !ok {
+		// 処理がまだない種類は再配信しても処理できないため、Ackして読み捨てる
+		slog.Warn("no handler for message type, acked without processing",
+			"message_id", msg.ID, "type", msgType, "trace_id", pm.TraceID)
+		msg.Ack()
+		return
+	}
+
+	err = h(ctx, pm)
+	if err == nil {
+		msg.Ack()
+		return
+	}
+	if errors.Is(err, entity.ErrValidation) || (w.cfg.MaxAttempts > 0 && msg.DeliveryAttempt >= w.cfg.MaxAttempts) {
+		w.deadLetter(ctx, msg, msgType, err)
+		return
+	}
+	slog.Warn("failed to handle message, will be redelivered",
+		"message_id", msg.ID, "type", msgType, "trace_id", pm.TraceID, "delivery_attempt", msg.DeliveryAttempt, "error", err)
+	msg.Nack()
+}
+
+// deadLetter 処理できなかったメッセージをデッドレタートピックに送ってAckする
+// 送れなかった場合は失わないようにNackして再配信を待つ
+func (w *worker) deadLetter(ctx context.Context, msg *Message, msgType external.PipelineMessageType, cause error) {
+	slog.Error("message moved to dead letter",
+		"message_id", msg.ID, "type", msgType, "delivery_attempt", msg.DeliveryAttempt, "error", cause)
+	if w.cfg.DeadLetterTopic == "" {
+		msg.Ack()
+		return
+	}
+	err := w.queue.PublishDeadLetter(ctx, w.cfg.DeadLetterTopic, &external.DeadLetterMessage{
+		MessageID:       msg.ID,
+		Type:            string(msgType),
+		Data:            msg.Data,
+		Error:           cause.Error(),
+		DeliveryAttempt: msg.DeliveryAttempt,
+		FailedAt:        time.Now(),
+	})
+	if err != nil {
+		slog.Error("failed to publish dead letter", "message_id", msg.ID, "error", err)
+		msg.Nack()
+		return
+	}
+	msg.Ack()
+}
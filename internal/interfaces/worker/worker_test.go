@@ -0,0 +1,207 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zuxt268/sales/internal/entity"
+	"github.com/zuxt268/sales/internal/interfaces/dto/external"
+)
+
+// fakeReceiver 渡されたメッセージを並行に配信し、すべての処理が終わったら戻る
+type fakeReceiver struct {
+	msgs []*Message
+}
+
+func (r *fakeReceiver) Receive(ctx context.Context, handle func(context.Context, *Message)) error {
+	var wg sync.WaitGroup
+	for _, m := range r.msgs {
+		wg.Add(1)
+		go func(m *Message) {
+			defer wg.Done()
+			handle(ctx, m)
+		}(m)
+	}
+	wg.Wait()
+	return nil
+}
+
+type fakePubSub struct {
+	mu        sync.Mutex
+	published map[string][][]byte
+	err       error
+}
+
+func (p *fakePubSub) Publish(_ context.Context, topic string, data []byte) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.err != nil {
+		return p.err
+	}
+	if p.published == nil {
+		p.published = make(map[string][][]byte)
+	}
+	p.published[topic] = append(p.published[topic], data)
+	return nil
+}
+
+func (p *fakePubSub) PublishPipelineMessage(ctx context.Context, msg *external.PipelineMessage) error {
+	data, _ := json.Marshal(msg)
+	return p.Publish(ctx, external.DomainPipelineTopic, data)
+}
+
+func (p *fakePubSub) PublishDeadLetter(ctx context.Context, topic string, msg *external.DeadLetterMessage) error {
+	data, _ := json.Marshal(msg)
+	return p.Publish(ctx, topic, data)
+}
+
+type result struct {
+	acked  atomic.Int32
+	nacked atomic.Int32
+}
+
+func newMessage(id string, attempt int, data string, r *result) *Message {
+	return &Message{
+		ID:              id,
+		Data:            []byte(data),
+		DeliveryAttempt: attempt,
+		Ack:             func() { r.acked.Add(1) },
+		Nack:            func() { r.nacked.Add(1) },
+	}
+}
+
+var testConfig = Config{
+	Concurrency:     2,
+	MaxAttempts:     3,
+	HandlerTimeout:  time.Second,
+	DeadLetterTopic: "dead-letter",
+}
+
+func TestWorker_Dispatch(t *testing.T) {
+	var r result
+	var analyzed, checked atomic.Int32
+	receiver := &fakeReceiver{msgs: []*Message{
+		newMessage("1", 1, `{"version":1,"type":"analyze","domain_id":1}`, &r),
+		newMessage("2", 1, `{"domain_id":2}`, &r), // typeのない以前のメッセージ
+		newMessage("3", 1, `{"version":1,"type":"check_japan","domain_id":3}`, &r),
+	}}
+	pubSub := &fakePubSub{}
+	w := NewWorker(receiver, pubSub, testConfig)
+	w.Handle(external.PipelineAnalyze, func(_ context.Context, msg *external.PipelineMessage) error {
+		analyzed.Add(1)
+		return nil
+	})
+	w.Handle(external.PipelineCheckJapan, func(_ context.Context, msg *external.PipelineMessage) error {
+		assert.Equal(t, 3, msg.DomainID)
+		checked.Add(1)
+		return nil
+	})
+
+	assert.NoError(t, w.Run(context.Background()))
+	assert.Equal(t, int32(2), analyzed.Load())
+	assert.Equal(t, int32(1), checked.Load())
+	assert.Equal(t, int32(3), r.acked.Load())
+	assert.Equal(t, int32(0), r.nacked.Load())
+	assert.Empty(t, pubSub.published)
+}
+
+func TestWorker_Failures(t *testing.T) {
+	retryable := errors.New("temporary error")
+	const analyze = `{"version":1,"type":"analyze","domain_id":1}`
+	tests := []struct {
+		name       string
+		attempt    int
+		data       string
+		err        error
+		publishErr error
+		wantAck    int32
+		wantNack   int32
+		wantDead   int
+	}{
+		{"retryable error is nacked", 1, analyze, retryable, nil, 0, 1, 0},
+		{"retryable error at max attempts goes to dead letter", 3, analyze, retryable, nil, 1, 0, 1},
+		{"validation error goes to dead letter", 1, analyze, fmt.Errorf("bad: %w", entity.ErrValidation), nil, 1, 0, 1},
+		{"broken payload goes to dead letter", 1, `not json`, nil, nil, 1, 0, 1},
+		{"unknown type goes to dead letter", 1, `{"version":1,"type":"unknown","domain_id":1}`, nil, nil, 1, 0, 1},
+		{"type without handler is acked", 1, `{"version":1,"type":"export","domain_id":1}`, nil, nil, 1, 0, 0},
+		{"nacked when dead letter cannot be published", 1, `not json`, nil, errors.New("publish failed"), 0, 1, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var r result
+			receiver := &fakeReceiver{msgs: []*Message{newMessage("1", tt.attempt, tt.data, &r)}}
+			pubSub := &fakePubSub{err: tt.publishErr}
+			w := NewWorker(receiver, pubSub, testConfig)
+			w.Handle(external.PipelineAnalyze, func(context.Context, *external.PipelineMessage) error {
+				return tt.err
+			})
+
+			assert.NoError(t, w.Run(context.Background()))
+			assert.Equal(t, tt.wantAck, r.acked.Load())
+			assert.Equal(t, tt.wantNack, r.nacked.Load())
+			assert.Len(t, pubSub.published["dead-letter"], tt.wantDead)
+			if tt.wantDead > 0 {
+				var dead external.DeadLetterMessage
+				assert.NoError(t, json.Unmarshal(pubSub.published["dead-letter"][0], &dead))
+				assert.Equal(t, "1", dead.MessageID)
+				assert.Equal(t, tt.data, string(dead.Data))
+				assert.NotEmpty(t, dead.Error)
+			}
+		})
+	}
+}
+
+func TestWorker_Concurrency(t *testing.T) {
+	var r result
+	msgs := make([]*Message, 0, 10)
+	for i := 0; i < 10; i++ {
+		msgs = append(msgs, newMessage(fmt.Sprint(i), 1, `{"domain_id":1}`, &r))
+	}
+	var running, peak atomic.Int32
+	w := NewWorker(&fakeReceiver{msgs: msgs}, &fakePubSub{}, testConfig)
+	w.Handle(external.PipelineAnalyze, func(context.Context, *external.PipelineMessage) error {
+		n := running.Add(1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		running.Add(-1)
+		return nil
+	})
+
+	assert.NoError(t, w.Run(context.Background()))
+	assert.Equal(t, int32(10), r.acked.Load())
+	assert.LessOrEqual(t, peak.Load(), int32(testConfig.Concurrency))
+}
+
+func TestWorker_GracefulShutdown(t *testing.T) {
+	var r result
+	ctx, cancel := context.WithCancel(context.Background())
+	started := make(chan struct{})
+	w := NewWorker(&fakeReceiver{msgs: []*Message{
+		newMessage("1", 1, `{"domain_id":1}`, &r),
+	}}, &fakePubSub{}, testConfig)
+	w.Handle(external.PipelineAnalyze, func(ctx context.Context, _ *external.PipelineMessage) error {
+		close(started)
+		time.Sleep(20 * time.Millisecond)
+		// 停止の指示が来ても処理中のメッセージのcontextはキャンセルされない
+		return ctx.Err()
+	})
+
+	go func() {
+		<-started
+		cancel()
+	}()
+	assert.NoError(t, w.Run(ctx))
+	assert.Equal(t, int32(1), r.acked.Load())
+}
@@ -0,0 +1,16 @@
+package model
+
+import "time"
+
+// APICallLedger 外部APIを日ごとに呼び出した回数。1日の上限を超えないように使う
+type APICallLedger struct {
+	Date      time.Time `gorm:"column:date;primaryKey"`
+	API       string    `gorm:"column:api;primaryKey"`
+	Calls     int       `gorm:"column:calls"`
+	UpdatedAt time.Time `gorm:"column:updated_at;autoUpdateTime"`
+}
+
+// 呼び出し回数を記録する外部API
+const (
+	APIViewDNSReverseIP = "viewdns_reverse_ip"
+)
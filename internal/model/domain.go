@@ -1,32 +1,57 @@
 package model
 
 import (
+	"strings"
 	"time"
 )
 
 type Domain struct {
-	ID            int       `gorm:"column:id;primaryKey;autoIncrement"`
-	Name          string    `gorm:"column:name;unique"`
-	Target        string    `gorm:"column:target"`
-	CanView       bool      `gorm:"column:can_view"`
-	IsJapan       bool      `gorm:"column:is_japan"`
-	IsSend        bool      `gorm:"column:is_send"`
-	Title         string    `gorm:"column:title"`
-	OwnerID       string    `gorm:"column:owner_id"`
-	Address       string    `gorm:"column:address"`
-	Phone         string    `gorm:"column:phone"`
-	MobilePhone   string    `gorm:"column:mobile_phone"`
-	LandlinePhone string    `gorm:"column:landline_phone"`
-	Industry      string    `gorm:"column:industry"`
-	President     string    `gorm:"column:president"`
-	Company       string    `gorm:"column:company"`
-	Prefecture    string    `gorm:"column:prefecture"`
-	IsSSL         bool      `gorm:"column:is_ssl"`
-	RawPage       string    `gorm:"column:raw_page"`
-	PageNum       int       `gorm:"column:page_num"`
-	Status        Status    `gorm:"column:status"`
-	UpdatedAt     time.Time `gorm:"column:updated_at;autoUpdateTime"`
-	CreatedAt     time.Time `gorm:"column:created_at;autoCreateTime"`
+	ID              int        `gorm:"column:id;primaryKey;autoIncrement"`
+	Name            string     `gorm:"column:name;unique"`
+	Target          string     `gorm:"column:target"`
+	Source          string     `gorm:"column:source"`
+	Platform        string     `gorm:"column:platform"`
+	CMS             string     `gorm:"column:cms"`
+	CMSVersion      string     `gorm:"column:cms_version"`
+	HostingIP       string     `gorm:"column:hosting_ip"`
+	FingerprintedAt *time.Time `gorm:"column:fingerprinted_at"`
+	MovedAwayAt     *time.Time `gorm:"column:moved_away_at"`
+	CanView         bool       `gorm:"column:can_view"`
+	IsJapan         bool       `gorm:"column:is_japan"`
+	IsSend          bool       `gorm:"column:is_send"`
+	Title           string     `gorm:"column:title"`
+	OwnerID         string     `gorm:"column:owner_id"`
+	Address         string     `gorm:"column:address"`
+	Phone           string     `gorm:"column:phone"`
+	MobilePhone     string     `gorm:"column:mobile_phone"`
+	LandlinePhone   string     `gorm:"column:landline_phone"`
+	Industry        string     `gorm:"column:industry"`
+	President       string     `gorm:"column:president"`
+	Company         string     `gorm:"column:company"`
+	Prefecture      string     `gorm:"column:prefecture"`
+	IsSSL           bool       `gorm:"column:is_ssl"`
+	SSLIssuer       string     `gorm:"column:ssl_issuer"`
+	SSLExpiresAt    *time.Time `gorm:"column:ssl_expires_at"`
+	RedirectURL     string     `gorm:"column:redirect_url"`
+	IsParked        bool       `gorm:"column:is_parked"`
+	HealthCheckedAt *time.Time `gorm:"column:health_checked_at"`
+	RawPage         string     `gorm:"column:raw_page"`
+	PageNum         int        `gorm:"column:page_num"`
+	CrawledURLs     string     `gorm:"column:crawled_urls"`
+	ReviewReason    string     `gorm:"column:review_reason"`
+	Status          Status     `gorm:"column:status"`
+	StatusUpdatedAt time.Time  `gorm:"column:status_updated_at;autoCreateTime"`
+	Attempts        int        `gorm:"column:attempts"`
+	UpdatedAt       time.Time  `gorm:"column:updated_at;autoUpdateTime"`
+	CreatedAt       time.Time  `gorm:"column:created_at;autoCreateTime"`
+}
+
+// GetCrawledURLs 企業情報の取得元URL一覧
+func (d Domain) GetCrawledURLs() []string {
+	if d.CrawledURLs == "" {
+		return []string{}
+	}
+	return strings.Split(d.CrawledURLs, "\n")
 }
 
 type Status string
@@ -36,8 +61,10 @@ const (
 	StatusInitialize    Status = "initialize"
 	StatusCheckView     Status = "check_view"
 	StatusCheckJapan    Status = "check_japan"
+	StatusCheckWix      Status = "check_wix" // Wixのサイトが日本語か調べ、オーナーIDを取り出す
 	StatusCrawlCompInfo Status = "crawl_comp_info"
 	StatusPendingOutput Status = "pending_output"
+	StatusNeedsReview   Status = "needs_review"
 	StatusDone          Status = "done"
 	StatusTrash         Status = "trash"
 )
@@ -48,7 +75,10 @@ var ValidStatuses = []Status{
 	StatusInitialize,
 	StatusCheckView,
 	StatusCheckJapan,
+	StatusCheckWix,
 	StatusCrawlCompInfo,
+	StatusPendingOutput,
+	StatusNeedsReview,
 	StatusDone,
 	StatusTrash,
 }
@@ -62,3 +92,23 @@ func IsValidStatus(s Status) bool {
 	}
 	return false
 }
+
+// サイトを作ったCMS・ビルダー
+const (
+	CMSWordPress = "wordpress"
+	CMSWix       = "wix"
+	CMSJimdo     = "jimdo"
+	CMSShopify   = "shopify"
+	CMSStudio    = "studio"
+	CMSAmebaOwnd = "ameba_ownd"
+	CMSStatic    = "static"  // CMSの手がかりがないもの
+	CMSOther     = "other"   // generatorはあるが上記以外のもの
+	CMSUnknown   = "unknown" // サイトを取得できなかったもの
+)
+
+// DomainStatusCount ターゲットのIPごと・ステータスごとのドメイン数
+type DomainStatusCount struct {
+	TargetIP string `gorm:"column:target_ip"`
+	Status   Status `gorm:"column:status"`
+	Count    int64  `gorm:"column:count"`
+}
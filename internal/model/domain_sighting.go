@@ -0,0 +1,14 @@
+package model
+
+import "time"
+
+// DomainSighting ドメインをターゲットの逆引きで見つけた記録。ドメインとターゲットのIPの組ごとに1件
+type DomainSighting struct {
+	ID           int64      `gorm:"column:id;primaryKey;autoIncrement" json:"id"`
+	Domain       string     `gorm:"column:domain" json:"domain"`
+	TargetIP     string     `gorm:"column:target_ip" json:"target_ip"`
+	LastResolved *time.Time `gorm:"column:last_resolved" json:"last_resolved"`
+	FirstSeen    time.Time  `gorm:"column:first_seen" json:"first_seen"`
+	LastSeen     time.Time  `gorm:"column:last_seen" json:"last_seen"`
+	MovedAwayAt  *time.Time `gorm:"column:moved_away_at" json:"moved_away_at"`
+}
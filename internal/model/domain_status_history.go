@@ -0,0 +1,27 @@
+package model
+
+import "time"
+
+type DomainStatusHistory struct {
+	ID         int       `gorm:"column:id;primaryKey;autoIncrement" json:"id"`
+	DomainID   int       `gorm:"column:domain_id" json:"domain_id"`
+	FromStatus Status    `gorm:"column:from_status" json:"from_status"`
+	ToStatus   Status    `gorm:"column:to_status" json:"to_status"`
+	Actor      string    `gorm:"column:actor" json:"actor"`
+	Reason     string    `gorm:"column:reason" json:"reason"`
+	CreatedAt  time.Time `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+}
+
+// ステータスを変更した主体
+const (
+	ActorAPI     = "api"
+	ActorFetch   = "fetch"
+	ActorPolling = "polling"
+	ActorAnalyze = "analyze"
+	ActorWix     = "wix"
+	ActorHealth  = "health"
+	ActorJapan   = "japan"
+	ActorOutput  = "output"
+	ActorBackup  = "backup"
+	ActorReaper  = "reaper"
+)
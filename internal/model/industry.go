@@ -0,0 +1,35 @@
+package model
+
+import "time"
+
+type Industry struct {
+	Code       string        `gorm:"column:code;primaryKey"`
+	Level      IndustryLevel `gorm:"column:level"`
+	ParentCode *string       `gorm:"column:parent_code"`
+	SortOrder  int           `gorm:"column:sort_order"`
+}
+
+type IndustryLevel string
+
+const (
+	IndustryLevelMajor  IndustryLevel = "major"
+	IndustryLevelMiddle IndustryLevel = "middle"
+)
+
+type DomainIndustry struct {
+	DomainID     int       `gorm:"column:domain_id;primaryKey"`
+	IndustryCode string    `gorm:"column:industry_code;primaryKey"`
+	CreatedAt    time.Time `gorm:"column:created_at;autoCreateTime"`
+}
+
+type HomstaIndustry struct {
+	HomstaID     int       `gorm:"column:homsta_id;primaryKey"`
+	IndustryCode string    `gorm:"column:industry_code;primaryKey"`
+	CreatedAt    time.Time `gorm:"column:created_at;autoCreateTime"`
+}
+
+// IndustryCount 業種ごとの件数。大分類は配下の中分類に紐付く件数
+type IndustryCount struct {
+	Code  string `gorm:"column:code"`
+	Count int    `gorm:"column:count"`
+}
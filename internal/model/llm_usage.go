@@ -0,0 +1,35 @@
+package model
+
+import "time"
+
+type LLMUsage struct {
+	ID               int        `gorm:"column:id;primaryKey;autoIncrement"`
+	Feature          LLMFeature `gorm:"column:feature"`
+	Model            string     `gorm:"column:model"`
+	PromptTokens     int        `gorm:"column:prompt_tokens"`
+	CompletionTokens int        `gorm:"column:completion_tokens"`
+	TotalTokens      int        `gorm:"column:total_tokens"`
+	Cost             float64    `gorm:"column:cost"`
+	LatencyMs        int64      `gorm:"column:latency_ms"`
+	DomainID         *int       `gorm:"column:domain_id"`
+	HomstaID         *int       `gorm:"column:homsta_id"`
+	CreatedAt        time.Time  `gorm:"column:created_at;autoCreateTime"`
+}
+
+type LLMFeature string
+
+const (
+	LLMFeatureAnalyzeDomain       LLMFeature = "analyze_domain"
+	LLMFeatureAnalyzeSiteIndustry LLMFeature = "analyze_site_industry"
+)
+
+// LLMUsageSummary 日別・機能別の使用量の集計
+type LLMUsageSummary struct {
+	Date             string     `gorm:"column:date" json:"date"`
+	Feature          LLMFeature `gorm:"column:feature" json:"feature"`
+	Calls            int        `gorm:"column:calls" json:"calls"`
+	PromptTokens     int        `gorm:"column:prompt_tokens" json:"prompt_tokens"`
+	CompletionTokens int        `gorm:"column:completion_tokens" json:"completion_tokens"`
+	TotalTokens      int        `gorm:"column:total_tokens" json:"total_tokens"`
+	Cost             float64    `gorm:"column:cost" json:"cost"`
+}
@@ -0,0 +1,42 @@
+package model
+
+import "time"
+
+// OutboxMessage ドメインの更新と同じトランザクションで書き込み、後からPub/Subへ送信するメッセージ
+type OutboxMessage struct {
+	ID            int64        `gorm:"column:id;primaryKey;autoIncrement"`
+	MessageID     string       `gorm:"column:message_id"`
+	Topic         string       `gorm:"column:topic"`
+	Payload       string       `gorm:"column:payload"`
+	Status        OutboxStatus `gorm:"column:status"`
+	Attempts      int          `gorm:"column:attempts"`
+	LastError     string       `gorm:"column:last_error"`
+	NextAttemptAt time.Time    `gorm:"column:next_attempt_at"`
+	SentAt        *time.Time   `gorm:"column:sent_at"`
+	CreatedAt     time.Time    `gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt     time.Time    `gorm:"column:updated_at;autoUpdateTime"`
+}
+
+type OutboxStatus string
+
+const (
+	OutboxStatusPending OutboxStatus = "pending"
+	OutboxStatusSent    OutboxStatus = "sent"
+	OutboxStatusFailed  OutboxStatus = "failed"
+)
+
+// ProcessedMessage 受信側で処理済みのメッセージ。同じメッセージの再配信を無視するために使う
+type ProcessedMessage struct {
+	MessageID string    `gorm:"column:message_id;primaryKey"`
+	Consumer  string    `gorm:"column:consumer;primaryKey"`
+	CreatedAt time.Time `gorm:"column:created_at;autoCreateTime"`
+}
+
+// メッセージの受信側
+const (
+	ConsumerGrowthAnalyze = "growth_analyze"
+	ConsumerGrowthWix     = "growth_wix"
+	ConsumerGrowthView    = "growth_view"
+	ConsumerGrowthJapan   = "growth_japan"
+	ConsumerGptAnalyze    = "gpt_analyze"
+)
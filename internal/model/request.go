@@ -26,13 +26,20 @@ type GetTargetsRequest struct {
 }
 
 type UpdateTargetRequest struct {
-	IP   *string `json:"ip"`
-	Name *string `json:"name"`
+	IP       *string       `json:"ip"`
+	Name     *string       `json:"name"`
+	Source   *string       `json:"source"`
+	Platform *string       `json:"platform"`
+	Weight   *int          `json:"weight"`
+	Status   *TargetStatus `json:"status"` // init, fetched, disabled
 }
 
 type CreateTargetRequest struct {
-	IP   string `json:"ip"`
-	Name string `json:"name"`
+	IP       string `json:"ip"`
+	Name     string `json:"name"`
+	Source   string `json:"source"`   // viewdns（省略時）, passive_dns
+	Platform string `json:"platform"` // generic（省略時）, wix
+	Weight   *int   `json:"weight"`   // 省略時は1
 }
 
 type GetLogsRequest struct {
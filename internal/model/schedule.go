@@ -0,0 +1,53 @@
+package model
+
+import "time"
+
+// Schedule 定期実行するジョブ。cron_expr と paused はAPIから変更できる
+type Schedule struct {
+	ID        int        `gorm:"column:id;primaryKey;autoIncrement"`
+	Name      string     `gorm:"column:name"`
+	CronExpr  string     `gorm:"column:cron_expr"`
+	Paused    bool       `gorm:"column:paused"`
+	NextRunAt *time.Time `gorm:"column:next_run_at"`
+	CreatedAt time.Time  `gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt time.Time  `gorm:"column:updated_at;autoUpdateTime"`
+}
+
+// ScheduleRun ジョブを1回実行した記録
+type ScheduleRun struct {
+	ID           int64             `gorm:"column:id;primaryKey;autoIncrement"`
+	ScheduleName string            `gorm:"column:schedule_name"`
+	Trigger      ScheduleTrigger   `gorm:"column:trigger"`
+	Status       ScheduleRunStatus `gorm:"column:status"`
+	Error        string            `gorm:"column:error"`
+	Instance     string            `gorm:"column:instance"`
+	StartedAt    time.Time         `gorm:"column:started_at"`
+	FinishedAt   *time.Time        `gorm:"column:finished_at"`
+}
+
+type ScheduleTrigger string
+
+const (
+	ScheduleTriggerCron   ScheduleTrigger = "cron"
+	ScheduleTriggerManual ScheduleTrigger = "manual"
+)
+
+type ScheduleRunStatus string
+
+const (
+	ScheduleRunRunning   ScheduleRunStatus = "running"
+	ScheduleRunSucceeded ScheduleRunStatus = "succeeded"
+	ScheduleRunFailed    ScheduleRunStatus = "failed"
+	ScheduleRunSkipped   ScheduleRunStatus = "skipped" // 前回の実行が終わっていなかった
+)
+
+// 定期実行するジョブの名前
+const (
+	JobFetch       = "fetch"
+	JobPolling     = "polling"
+	JobOutput      = "output"
+	JobHomsta      = "homsta"
+	JobReap        = "reap"
+	JobRelay       = "relay"
+	JobFingerprint = "fingerprint"
+)
@@ -6,14 +6,65 @@ type Target struct {
 	ID             int          `gorm:"column:id;primaryKey;autoIncrement" json:"id"`
 	IP             string       `gorm:"column:ip;unique" json:"ip"`
 	Name           string       `gorm:"column:name" json:"name"`
+	Source         string       `gorm:"column:source" json:"source"`
+	Platform       string       `gorm:"column:platform" json:"platform"`
 	Status         TargetStatus `gorm:"column:status" json:"status"`
 	CurrentPage    int          `gorm:"column:current_page"`
+	MaxPage        int          `gorm:"column:max_page"`
 	LastFetchedAt  *time.Time   `gorm:"column:last_fetched_at"`
 	LastFullScanAt *time.Time   `gorm:"column:last_full_scan_at"`
+	ScanStartedAt  *time.Time   `gorm:"column:scan_started_at"`
+	Weight         int          `gorm:"column:weight" json:"weight"`
+	FailureCount   int          `gorm:"column:failure_count" json:"failure_count"`
+	NextFetchAt    *time.Time   `gorm:"column:next_fetch_at" json:"next_fetch_at"`
+	LastError      string       `gorm:"column:last_error" json:"last_error"`
 	UpdatedAt      time.Time    `gorm:"column:updated_at;autoUpdateTime" json:"updated_at"`
 	CreatedAt      time.Time    `gorm:"column:created_at;autoCreateTime" json:"created_at"`
 }
 
+// 逆引きの取得元
+const (
+	ReverseIPSourceViewDNS    = "viewdns"
+	ReverseIPSourcePassiveDNS = "passive_dns" // パッシブDNSのCSVやゾーンファイルの取り込み
+)
+
+// ValidReverseIPSources ターゲットに指定できる取得元
+var ValidReverseIPSources = []string{
+	ReverseIPSourceViewDNS,
+	ReverseIPSourcePassiveDNS,
+}
+
+// GetSource 逆引きの取得元。未設定の場合はViewDNS
+func (t *Target) GetSource() string {
+	if t.Source == "" {
+		return ReverseIPSourceViewDNS
+	}
+	return t.Source
+}
+
+// サイトの基盤。ターゲットで見つけたドメインは同じ基盤として処理する
+const (
+	PlatformGeneric = "generic"
+	PlatformWix     = "wix" // Wixのサーバー。日本語のサイトかどうかとオーナーIDを調べる
+)
+
+// ValidPlatforms ターゲットに指定できる基盤
+var ValidPlatforms = []string{
+	PlatformGeneric,
+	PlatformWix,
+}
+
+// GetPlatform サイトの基盤。未設定の場合はgeneric
+func (t *Target) GetPlatform() string {
+	if t.Platform == "" {
+		return PlatformGeneric
+	}
+	return t.Platform
+}
+
+// DefaultTargetWeight 重みを指定せずに登録したターゲットの重み
+const DefaultTargetWeight = 1
+
 type TargetStatus string
 
 const (
@@ -25,6 +76,8 @@ const (
 // ValidTargetStatuses は有効なステータスのリスト
 var ValidTargetStatuses = []TargetStatus{
 	TargetStatusInit,
+	TargetStatusFetched,
+	TargetStatusDisabled,
 }
 
 // IsValidTargetStatus は指定されたステータスが有効かどうかを判定
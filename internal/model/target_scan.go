@@ -0,0 +1,14 @@
+package model
+
+import "time"
+
+// TargetScan ターゲットを1ページ目から最後のページまで取得した1周分の記録
+type TargetScan struct {
+	ID           int64      `gorm:"column:id;primaryKey;autoIncrement" json:"id"`
+	TargetID     int        `gorm:"column:target_id" json:"target_id"`
+	StartedAt    time.Time  `gorm:"column:started_at" json:"started_at"`
+	FinishedAt   *time.Time `gorm:"column:finished_at" json:"finished_at"`
+	Pages        int        `gorm:"column:pages" json:"pages"`
+	DomainsFound int        `gorm:"column:domains_found" json:"domains_found"`
+	NewDomains   int        `gorm:"column:new_domains" json:"new_domains"`
+}
@@ -0,0 +1,73 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/zuxt268/sales/internal/entity"
+	"github.com/zuxt268/sales/internal/interfaces/repository"
+	"github.com/zuxt268/sales/internal/model"
+)
+
+// transitionDomain 遷移ルールを確認してステータスを変更し、履歴を残す
+// ドメイン自体の保存は呼び出し元で同じトランザクション内で行う
+func transitionDomain(
+	ctx context.Context,
+	historyRepo repository.DomainStatusHistoryRepository,
+	domain *model.Domain,
+	to model.Status,
+	actor, reason string,
+) error {
+	from := domain.Status
+	if err := entity.ValidateTransition(from, to); err != nil {
+		return err
+	}
+	if from == to {
+		return nil
+	}
+	domain.Status = to
+	domain.StatusUpdatedAt = time.Now()
+	domain.Attempts = 0
+	return historyRepo.Create(ctx, &model.DomainStatusHistory{
+		DomainID:   domain.ID,
+		FromStatus: from,
+		ToStatus:   to,
+		Actor:      actor,
+		Reason:     reason,
+	})
+}
+
+// bulkTransitionDomains fromステータスのドメインをまとめてtoに変更し、履歴を残す
+func bulkTransitionDomains(
+	ctx context.Context,
+	domainRepo repository.DomainRepository,
+	historyRepo repository.DomainStatusHistoryRepository,
+	domains []*model.Domain,
+	from, to model.Status,
+	actor string,
+) error {
+	if err := entity.ValidateTransition(from, to); err != nil {
+		return err
+	}
+	ids := make([]int, 0, len(domains))
+	histories := make([]*model.DomainStatusHistory, 0, len(domains))
+	for _, d := range domains {
+		if d.Status != from {
+			continue
+		}
+		ids = append(ids, d.ID)
+		histories = append(histories, &model.DomainStatusHistory{
+			DomainID:   d.ID,
+			FromStatus: from,
+			ToStatus:   to,
+			Actor:      actor,
+		})
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	if err := domainRepo.BulkUpdateStatus(ctx, ids, from, to); err != nil {
+		return err
+	}
+	return historyRepo.BulkCreate(ctx, histories)
+}
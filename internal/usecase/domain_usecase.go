@@ -15,20 +15,31 @@ type DomainUsecase interface {
 	GetDomain(ctx context.Context, id int) (*response.Domain, error)
 	UpdateDomain(ctx context.Context, id int, req request.UpdateDomain) (*response.Domain, error)
 	DeleteDomain(ctx context.Context, id int) error
+	GetStatusHistories(ctx context.Context, id int, req request.GetDomainStatusHistories) ([]*response.DomainStatusHistory, error)
+	GetSightings(ctx context.Context, id int) ([]*model.DomainSighting, error)
 }
 
 type domainUsecase struct {
-	baseRepo   repository.BaseRepository
-	domainRepo repository.DomainRepository
+	baseRepo     repository.BaseRepository
+	domainRepo   repository.DomainRepository
+	industryRepo repository.IndustryRepository
+	historyRepo  repository.DomainStatusHistoryRepository
+	sightingRepo repository.DomainSightingRepository
 }
 
 func NewDomainUsecase(
 	baseRepo repository.BaseRepository,
 	domainRepo repository.DomainRepository,
+	industryRepo repository.IndustryRepository,
+	historyRepo repository.DomainStatusHistoryRepository,
+	sightingRepo repository.DomainSightingRepository,
 ) DomainUsecase {
 	return &domainUsecase{
-		baseRepo:   baseRepo,
-		domainRepo: domainRepo,
+		baseRepo:     baseRepo,
+		domainRepo:   domainRepo,
+		industryRepo: industryRepo,
+		historyRepo:  historyRepo,
+		sightingRepo: sightingRepo,
 	}
 }
 
@@ -37,22 +48,27 @@ func (u *domainUsecase) GetDomain(ctx context.Context, id int) (*response.Domain
 	if err != nil {
 		return nil, err
 	}
-	return response.GetDomain(d), nil
+	return domainResponse(ctx, u.industryRepo, d)
 }
 
 func (u *domainUsecase) GetDomains(ctx context.Context, req request.GetDomains) (*response.Domains, error) {
 	filter := repository.DomainFilter{
-		PartialName: req.Name,
-		Target:      req.Target,
-		CanView:     req.CanView,
-		IsJapan:     req.IsJapan,
-		IsSend:      req.IsSend,
-		OwnerID:     req.OwnerID,
-		Industry:    req.Industry,
-		IsSSL:       req.IsSSL,
-		Status:      req.Status,
-		Limit:       req.Limit,
-		Offset:      req.Offset,
+		PartialName:  req.Name,
+		Target:       req.Target,
+		CanView:      req.CanView,
+		IsJapan:      req.IsJapan,
+		IsSend:       req.IsSend,
+		OwnerID:      req.OwnerID,
+		Industry:     req.Industry,
+		IndustryCode: req.IndustryCode,
+		IsSSL:        req.IsSSL,
+		MovedAway:    req.MovedAway,
+		Platform:     req.Platform,
+		CMS:          req.CMS,
+		HostingIP:    req.HostingIP,
+		Status:       req.Status,
+		Limit:        req.Limit,
+		Offset:       req.Offset,
 	}
 	domains, err := u.domainRepo.FindAll(ctx, filter)
 	if err != nil {
@@ -62,7 +78,15 @@ func (u *domainUsecase) GetDomains(ctx context.Context, req request.GetDomains)
 	if err != nil {
 		return nil, err
 	}
-	return response.GetDomains(domains, total), nil
+	ids := make([]int, 0, len(domains))
+	for _, d := range domains {
+		ids = append(ids, d.ID)
+	}
+	codes, err := u.industryRepo.FindDomainIndustryCodes(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	return response.GetDomains(domains, codes, total), nil
 }
 
 func (u *domainUsecase) UpdateDomain(ctx context.Context, id int, req request.UpdateDomain) (*response.Domain, error) {
@@ -76,7 +100,9 @@ func (u *domainUsecase) UpdateDomain(ctx context.Context, id int, req request.Up
 			return err
 		}
 		if req.Status != nil {
-			target.Status = *req.Status
+			if err := transitionDomain(ctx, u.historyRepo, target, *req.Status, model.ActorAPI, ""); err != nil {
+				return err
+			}
 		}
 		if req.IsSend != nil {
 			target.IsSend = *req.IsSend
@@ -143,13 +169,18 @@ func (u *domainUsecase) UpdateDomain(ctx context.Context, id int, req request.Up
 		if err := u.domainRepo.Save(ctx, target); err != nil {
 			return err
 		}
+		if req.Industry != nil {
+			if err := saveDomainIndustries(ctx, u.industryRepo, target); err != nil {
+				return err
+			}
+		}
 		return nil
 	})
 	if err != nil {
 		return nil, err
 	}
 
-	return response.GetDomain(target), nil
+	return domainResponse(ctx, u.industryRepo, target)
 }
 
 func (u *domainUsecase) DeleteDomain(ctx context.Context, id int) error {
@@ -157,3 +188,27 @@ func (u *domainUsecase) DeleteDomain(ctx context.Context, id int) error {
 		ID: &id,
 	})
 }
+
+func (u *domainUsecase) GetStatusHistories(ctx context.Context, id int, req request.GetDomainStatusHistories) ([]*response.DomainStatusHistory, error) {
+	if _, err := u.domainRepo.Get(ctx, repository.DomainFilter{ID: &id}); err != nil {
+		return nil, err
+	}
+	histories, err := u.historyRepo.FindAll(ctx, repository.DomainStatusHistoryFilter{
+		DomainID: &id,
+		Limit:    req.Limit,
+		Offset:   req.Offset,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return response.GetDomainStatusHistories(histories), nil
+}
+
+// GetSightings ドメインを逆引きで見つけたターゲットごとの記録。最後に見つけたものから
+func (u *domainUsecase) GetSightings(ctx context.Context, id int) ([]*model.DomainSighting, error) {
+	d, err := u.domainRepo.Get(ctx, repository.DomainFilter{ID: &id})
+	if err != nil {
+		return nil, err
+	}
+	return u.sightingRepo.FindAll(ctx, repository.DomainSightingFilter{Domain: &d.Name})
+}
@@ -4,12 +4,11 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
-	"strconv"
 	"sync"
+	"time"
 
-	"github.com/zuxt268/sales/internal/config"
+	"github.com/zuxt268/sales/internal/entity"
 	"github.com/zuxt268/sales/internal/interfaces/adapter"
-	"github.com/zuxt268/sales/internal/interfaces/dto/external"
 	"github.com/zuxt268/sales/internal/interfaces/repository"
 	"github.com/zuxt268/sales/internal/model"
 	"github.com/zuxt268/sales/internal/util"
@@ -21,26 +20,41 @@ type FetchUsecase interface {
 }
 
 type fetchUsecase struct {
-	viewDnsAdapter adapter.ViewDNSAdapter
-	slackAdapter   adapter.SlackAdapter
-	pubSubAdapter  adapter.PubSubAdapter
-	domainRepo     repository.DomainRepository
-	targetRepo     repository.TargetRepository
+	sources      adapter.ReverseIPSources
+	slackAdapter adapter.SlackAdapter
+	baseRepo     repository.BaseRepository
+	domainRepo   repository.DomainRepository
+	targetRepo   repository.TargetRepository
+	historyRepo  repository.DomainStatusHistoryRepository
+	outboxRepo   repository.OutboxRepository
+	ledgerRepo   repository.APICallLedgerRepository
+	sightingRepo repository.DomainSightingRepository
+	scanRepo     repository.TargetScanRepository
 }
 
 func NewFetchUsecase(
-	viewDnsAdapter adapter.ViewDNSAdapter,
+	sources adapter.ReverseIPSources,
 	slackAdapter adapter.SlackAdapter,
-	pubSubAdapter adapter.PubSubAdapter,
+	baseRepo repository.BaseRepository,
 	domainRepo repository.DomainRepository,
 	targetRepo repository.TargetRepository,
+	historyRepo repository.DomainStatusHistoryRepository,
+	outboxRepo repository.OutboxRepository,
+	ledgerRepo repository.APICallLedgerRepository,
+	sightingRepo repository.DomainSightingRepository,
+	scanRepo repository.TargetScanRepository,
 ) FetchUsecase {
 	return &fetchUsecase{
-		viewDnsAdapter: viewDnsAdapter,
-		slackAdapter:   slackAdapter,
-		pubSubAdapter:  pubSubAdapter,
-		domainRepo:     domainRepo,
-		targetRepo:     targetRepo,
+		sources:      sources,
+		slackAdapter: slackAdapter,
+		baseRepo:     baseRepo,
+		domainRepo:   domainRepo,
+		targetRepo:   targetRepo,
+		historyRepo:  historyRepo,
+		outboxRepo:   outboxRepo,
+		ledgerRepo:   ledgerRepo,
+		sightingRepo: sightingRepo,
+		scanRepo:     scanRepo,
 	}
 }
 
@@ -79,25 +93,24 @@ func (u *fetchUsecase) Polling(ctx context.Context) {
 }
 
 func (u *fetchUsecase) handleDomain(ctx context.Context, domain *model.Domain) error {
-	if err := u.pubSubAdapter.PushDomain(ctx, &external.DomainMessage{
-		DomainId: domain.ID,
-	}); err != nil {
-		return fmt.Errorf("pubsub publish failed: %w", err)
-	}
-
-	domain.Status = model.StatusCheckView
-	if err := u.domainRepo.Save(ctx, domain); err != nil {
-		return fmt.Errorf("failed to update status: %w", err)
-	}
-	return nil
+	return u.baseRepo.WithTransaction(ctx, func(ctx context.Context) error {
+		next := entity.FirstCheckStatus(domain)
+		if err := transitionDomain(ctx, u.historyRepo, domain, next, model.ActorFetch, ""); err != nil {
+			return err
+		}
+		if err := u.domainRepo.Save(ctx, domain); err != nil {
+			return fmt.Errorf("failed to update status: %w", err)
+		}
+		return enqueuePipelineMessage(ctx, u.outboxRepo, pipelineMessageTypeOf(next), domain.ID, 0)
+	})
 }
 
 func (u *fetchUsecase) Fetch(ctx context.Context) {
 	slog.Info("fetch is invoked")
 
 	targets, err := u.targetRepo.FindAll(ctx, repository.TargetFilter{
-		NotName: util.Pointer("WIX"),
-		Status:  util.Pointer(model.TargetStatusInit),
+		NotPlatform: util.Pointer(model.PlatformWix),
+		Status:      util.Pointer(model.TargetStatusInit),
 	})
 	if err != nil {
 		slog.Error("failed to fetch target", "error", err)
@@ -108,41 +121,28 @@ func (u *fetchUsecase) Fetch(ctx context.Context) {
 	for _, target := range targets {
 		page := 1
 		maxPage := 0
+		scanStartedAt := time.Now()
 		for {
-			resp, err := u.viewDnsAdapter.GetReverseIP(ctx, &external.ReverseIpRequest{
-				Host:   target.IP,
-				ApiKey: config.Env.ApiKey,
-				Page:   page,
-			})
+			result, err := lookupReverseIP(ctx, u.ledgerRepo, u.sources, target, page)
 			if err != nil {
 				slog.Error("failed get reverse ip", "error", err)
 				return
 			}
 
-			domains := make([]*model.Domain, 0, len(resp.Response.Domains))
-			for _, d := range resp.Response.Domains {
-				domains = append(domains, &model.Domain{
-					Name:   d.Name,
-					Target: target.Name,
-					Status: model.StatusInitialize,
-				})
-			}
-
-			err = u.domainRepo.BulkInsert(ctx, domains)
+			newDomains, err := saveReverseIPPage(ctx, u.domainRepo, u.sightingRepo, target, result.Domains, time.Now())
 			if err != nil {
 				slog.Error("failed to insert domains", "error", err)
 				return
 			}
-			slog.Info("insert domains", "domain_count", len(domains), "name", target.Name)
+			slog.Info("insert domains", "domain_count", len(result.Domains), "new_domain_count", newDomains, "name", target.Name)
 
 			if maxPage == 0 {
-				domainCount := resp.Response.DomainCount
-				count, err := strconv.Atoi(domainCount)
-				if err != nil {
-					slog.Error("failed to parse domain count", "error", err)
-					return
-				}
-				maxPage = (count + 9999) / 10000 // ceil 計算
+				maxPage = result.TotalPages
+			}
+			err = recordTargetScan(ctx, u.scanRepo, target, page, len(result.Domains), newDomains, page >= maxPage, time.Now())
+			if err != nil {
+				slog.Error("failed to record target scan", "error", err)
+				return
 			}
 			if page >= maxPage {
 				break
@@ -150,7 +150,13 @@ func (u *fetchUsecase) Fetch(ctx context.Context) {
 			page++
 		}
 
+		if err := markMovedAway(ctx, u.domainRepo, u.sightingRepo, target, scanStartedAt, time.Now()); err != nil {
+			slog.Error("failed to mark moved away domains", "error", err)
+			return
+		}
+
 		target.Status = model.TargetStatusFetched
+		target.MaxPage = maxPage
 		err = u.targetRepo.Save(ctx, target)
 		if err != nil {
 			slog.Error("failed to save target", "error", err)
@@ -0,0 +1,147 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/netip"
+	"sync"
+	"time"
+
+	"github.com/zuxt268/sales/internal/config"
+	"github.com/zuxt268/sales/internal/entity"
+	"github.com/zuxt268/sales/internal/interfaces/adapter"
+	"github.com/zuxt268/sales/internal/interfaces/dto/response"
+	"github.com/zuxt268/sales/internal/interfaces/repository"
+	"github.com/zuxt268/sales/internal/model"
+	"github.com/zuxt268/sales/internal/util"
+	"golang.org/x/sync/errgroup"
+)
+
+// FingerprintUsecase ドメインのサイトを作ったCMS・ビルダーとホスティング先のIPを調べる
+type FingerprintUsecase interface {
+	Fingerprint(ctx context.Context) (*response.Fingerprint, error)
+	FingerprintDomain(ctx context.Context, id int) (*response.Domain, error)
+}
+
+// maxConcurrentFingerprint 判定時の最大並行処理数
+const maxConcurrentFingerprint = 20
+
+type fingerprintUsecase struct {
+	domainRepo      repository.DomainRepository
+	industryRepo    repository.IndustryRepository
+	crawlerAdapter  adapter.CrawlerAdapter
+	resolverAdapter adapter.ResolverAdapter
+}
+
+func NewFingerprintUsecase(
+	domainRepo repository.DomainRepository,
+	industryRepo repository.IndustryRepository,
+	crawlerAdapter adapter.CrawlerAdapter,
+	resolverAdapter adapter.ResolverAdapter,
+) FingerprintUsecase {
+	return &fingerprintUsecase{
+		domainRepo:      domainRepo,
+		industryRepo:    industryRepo,
+		crawlerAdapter:  crawlerAdapter,
+		resolverAdapter: resolverAdapter,
+	}
+}
+
+// Fingerprint まだ判定していないドメインをまとめて判定する。trashのドメインは対象外
+// 取得できずに判定が残ったドメインは、更新日時の古い順に取り出すことで後回しにする
+// 保存できなかったドメインは数えるだけにして、ほかのドメインの判定は続ける
+func (u *fingerprintUsecase) Fingerprint(ctx context.Context) (*response.Fingerprint, error) {
+	domains, err := u.domainRepo.FindAll(ctx, repository.DomainFilter{
+		Fingerprinted: util.Pointer(false),
+		NotStatuses:   []model.Status{model.StatusTrash},
+		OrderBy:       []string{"updated_at"},
+		Limit:         util.Pointer(config.Env.FingerprintBatchSize),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch domains: %w", err)
+	}
+
+	res := &response.Fingerprint{Counts: map[string]int{}}
+	if len(domains) == 0 {
+		return res, nil
+	}
+
+	var mu sync.Mutex
+	var g errgroup.Group
+	g.SetLimit(maxConcurrentFingerprint)
+	for _, domain := range domains {
+		d := domain
+		g.Go(func() error {
+			err := u.fingerprint(ctx, d)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				slog.Error("failed to fingerprint domain", "domain_id", d.ID, "error", err)
+				res.Failed++
+				return nil
+			}
+			res.Processed++
+			res.Counts[d.CMS]++
+			return nil
+		})
+	}
+	_ = g.Wait()
+
+	slog.Info("fingerprint completed", "processed", res.Processed, "failed", res.Failed, "counts", res.Counts)
+	return res, nil
+}
+
+// FingerprintDomain 指定したドメインを判定し直す
+func (u *fingerprintUsecase) FingerprintDomain(ctx context.Context, id int) (*response.Domain, error) {
+	domain, err := u.domainRepo.Get(ctx, repository.DomainFilter{ID: &id})
+	if err != nil {
+		return nil, err
+	}
+	if err := u.fingerprint(ctx, domain); err != nil {
+		return nil, err
+	}
+	return domainResponse(ctx, u.industryRepo, domain)
+}
+
+// fingerprint サイトのヘッダーとHTMLからCMSを判定し、名前解決したIPと一緒に保存する
+// 取得できなかったサイトは一時的なエラーかもしれないため、unknownとして記録したうえで判定した日時を残さず、次の判定でも対象にする
+func (u *fingerprintUsecase) fingerprint(ctx context.Context, domain *model.Domain) error {
+	page, fetchErr := u.crawlerAdapter.FetchPage(ctx, domain.Name)
+	if fetchErr != nil {
+		slog.Warn("failed to fetch page for fingerprint", "domain", domain.Name, "error", fetchErr)
+		domain.CMS = model.CMSUnknown
+		domain.CMSVersion = ""
+	} else {
+		fp := entity.FingerprintSite(page.Header, page.HTML)
+		domain.CMS = fp.CMS
+		domain.CMSVersion = fp.Version
+	}
+
+	domain.HostingIP = ""
+	if ips, err := u.resolverAdapter.LookupIP(ctx, domain.Name); err != nil {
+		slog.Warn("failed to resolve domain for fingerprint", "domain", domain.Name, "error", err)
+	} else {
+		domain.HostingIP = preferIPv4(ips)
+	}
+
+	domain.FingerprintedAt = nil
+	if fetchErr == nil {
+		now := time.Now()
+		domain.FingerprintedAt = &now
+	}
+	return u.domainRepo.UpdateFingerprint(ctx, domain)
+}
+
+// preferIPv4 IPv4のアドレスがあればそれを、なければ先頭のアドレスを返す
+func preferIPv4(ips []string) string {
+	for _, ip := range ips {
+		if addr, err := netip.ParseAddr(ip); err == nil && addr.Is4() {
+			return ip
+		}
+	}
+	if len(ips) > 0 {
+		return ips[0]
+	}
+	return ""
+}
@@ -15,15 +15,20 @@ import (
 )
 
 type GptUsecase interface {
-	AnalyzeDomain(ctx context.Context, domainMessage *external.DomainMessage) error
+	AnalyzeDomain(ctx context.Context, msg *external.PipelineMessage) error
 	AnalyzeDomains(ctx context.Context) error
 }
 
 type gptUsecase struct {
-	baseRepo     repository.BaseRepository
-	domainRepo   repository.DomainRepository
-	slackAdapter adapter.SlackAdapter
-	gptRepo      adapter.GptAdapter
+	baseRepo      repository.BaseRepository
+	domainRepo    repository.DomainRepository
+	slackAdapter  adapter.SlackAdapter
+	gptRepo       adapter.GptAdapter
+	crawler       adapter.CrawlerAdapter
+	llmUsageRepo  repository.LLMUsageRepository
+	industryRepo  repository.IndustryRepository
+	historyRepo   repository.DomainStatusHistoryRepository
+	processedRepo repository.ProcessedMessageRepository
 }
 
 func NewGptUsecase(
@@ -31,20 +36,48 @@ func NewGptUsecase(
 	domainRepo repository.DomainRepository,
 	slackAdapter adapter.SlackAdapter,
 	gptRepo adapter.GptAdapter,
+	crawler adapter.CrawlerAdapter,
+	llmUsageRepo repository.LLMUsageRepository,
+	industryRepo repository.IndustryRepository,
+	historyRepo repository.DomainStatusHistoryRepository,
+	processedRepo repository.ProcessedMessageRepository,
 ) GptUsecase {
 	return &gptUsecase{
-		baseRepo:     baseRepo,
-		domainRepo:   domainRepo,
-		slackAdapter: slackAdapter,
-		gptRepo:      gptRepo,
+		baseRepo:      baseRepo,
+		domainRepo:    domainRepo,
+		slackAdapter:  slackAdapter,
+		gptRepo:       gptRepo,
+		crawler:       crawler,
+		llmUsageRepo:  llmUsageRepo,
+		industryRepo:  industryRepo,
+		historyRepo:   historyRepo,
+		processedRepo: processedRepo,
 	}
 }
 
-func (u *gptUsecase) AnalyzeDomain(ctx context.Context, domainMessage *external.DomainMessage) error {
-	slog.Info("analyzing domain", "domainMessage", domainMessage)
+func (u *gptUsecase) AnalyzeDomain(ctx context.Context, msg *external.PipelineMessage) error {
+	slog.Info("analyzing domain", "domain_id", msg.DomainID, "message_id", msg.MessageID, "trace_id", msg.TraceID)
+
+	domain, err := u.domainRepo.Get(ctx, repository.DomainFilter{ID: &msg.DomainID})
+	if err != nil {
+		if errors.Is(err, entity.ErrNotFound) {
+			return nil
+		}
+		return err
+	}
+	if domain.Status != model.StatusCrawlCompInfo {
+		return nil
+	}
+	if processed, err := isProcessedMessage(ctx, u.processedRepo, msg, model.ConsumerGptAnalyze); err != nil || processed {
+		return err
+	}
+	if err := checkLLMBudget(ctx, u.llmUsageRepo); err != nil {
+		return err
+	}
+	info := crawlCompanyInfo(ctx, u.crawler, domain)
 
 	return u.baseRepo.WithTransaction(ctx, func(ctx context.Context) error {
-		domain, err := u.domainRepo.GetForUpdate(ctx, repository.DomainFilter{ID: &domainMessage.DomainId})
+		domain, err := u.domainRepo.GetForUpdate(ctx, repository.DomainFilter{ID: &msg.DomainID})
 		if err != nil {
 			if errors.Is(err, entity.ErrNotFound) {
 				return nil
@@ -54,14 +87,26 @@ func (u *gptUsecase) AnalyzeDomain(ctx context.Context, domainMessage *external.
 		if domain.Status != model.StatusCrawlCompInfo {
 			return nil
 		}
-		if err := u.gptRepo.Analyze(ctx, domain); err != nil {
+		if ok, err := markMessageProcessed(ctx, u.processedRepo, msg, model.ConsumerGptAnalyze); err != nil || !ok {
+			return err
+		}
+		applyCompanyInfo(domain, info)
+		hints := applyContactInfo(domain)
+		analysis, usage, err := u.gptRepo.Analyze(ctx, domain.RawPage, hints)
+		recordLLMUsage(ctx, u.llmUsageRepo, model.LLMFeatureAnalyzeDomain, usage, &domain.ID, nil)
+		if err != nil {
+			return err
+		}
+		status := applyAnalysis(domain, analysis, hints)
+		if err := transitionDomain(ctx, u.historyRepo, domain, status, model.ActorAnalyze, domain.ReviewReason); err != nil {
 			return err
 		}
-		domain.MobilePhone, domain.LandlinePhone = entity.SplitPhone(domain.Phone)
-		domain.Status = model.StatusPendingOutput
 		if err := u.domainRepo.Save(ctx, domain); err != nil {
 			return err
 		}
+		if err := saveDomainIndustries(ctx, u.industryRepo, domain); err != nil {
+			return err
+		}
 		slog.Info("analyzed", "domain", domain)
 		return nil
 	})
@@ -81,20 +126,38 @@ func (u *gptUsecase) AnalyzeDomains(ctx context.Context) error {
 	var wg sync.WaitGroup
 
 	for _, d := range domains {
-		wg.Add(1)
 		semaphore <- struct{}{}
+		// 予算を超えたら新しい解析を始めずに終了する
+		if err := checkLLMBudget(ctx, u.llmUsageRepo); err != nil {
+			<-semaphore
+			slog.Warn("analyze paused", "error", err)
+			_ = u.slackAdapter.Send(ctx, "analyze 中断: LLMの1日の予算を超えました")
+			break
+		}
+		wg.Add(1)
 
 		go func(d *model.Domain) {
 			defer wg.Done()
 			defer func() { <-semaphore }()
 
-			if err := u.gptRepo.Analyze(ctx, d); err != nil {
+			applyCompanyInfo(d, crawlCompanyInfo(ctx, u.crawler, d))
+			hints := applyContactInfo(d)
+			analysis, usage, err := u.gptRepo.Analyze(ctx, d.RawPage, hints)
+			recordLLMUsage(ctx, u.llmUsageRepo, model.LLMFeatureAnalyzeDomain, usage, &d.ID, nil)
+			if err != nil {
 				slog.Error("gpt repo analyze error", "error", err)
 				return
 			}
-			d.MobilePhone, d.LandlinePhone = entity.SplitPhone(d.Phone)
-			d.Status = model.StatusPendingOutput
-			if err := u.domainRepo.Save(ctx, d); err != nil {
+			status := applyAnalysis(d, analysis, hints)
+			if err := u.baseRepo.WithTransaction(ctx, func(ctx context.Context) error {
+				if err := transitionDomain(ctx, u.historyRepo, d, status, model.ActorAnalyze, d.ReviewReason); err != nil {
+					return err
+				}
+				if err := u.domainRepo.Save(ctx, d); err != nil {
+					return err
+				}
+				return saveDomainIndustries(ctx, u.industryRepo, d)
+			}); err != nil {
 				slog.Error("gpt repo save error", "error", err)
 				return
 			}
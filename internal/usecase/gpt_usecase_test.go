@@ -0,0 +1,43 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zuxt268/sales/internal/config"
+	"github.com/zuxt268/sales/internal/interfaces/adapter"
+	"github.com/zuxt268/sales/internal/model"
+)
+
+// LLM_PROVIDER=stub ならAPIキーなしで解析から出力待ちまで進められる
+func Test_analyzeWithStubProvider(t *testing.T) {
+	provider, key := config.Env.LLMProvider, config.Env.OpenaiApiKey
+	t.Cleanup(func() {
+		config.Env.LLMProvider, config.Env.OpenaiApiKey = provider, key
+	})
+	config.Env.LLMProvider = adapter.LLMProviderStub
+	config.Env.OpenaiApiKey = ""
+
+	gpt, err := adapter.NewGptAdapter(nil)
+	assert.NoError(t, err)
+
+	domain := &model.Domain{
+		RawPage: "会社概要\n会社名 株式会社タロウ\n代表取締役 山田太郎\n〒530-0001 大阪府大阪市北区梅田1-1\nTEL 06-1234-5678\n事業内容 自動車整備業",
+	}
+	hints := applyContactInfo(domain)
+	assert.Equal(t, "大阪府", hints.Prefecture)
+
+	analysis, usage, err := gpt.Analyze(context.Background(), domain.RawPage, hints)
+	assert.NoError(t, err)
+	assert.Equal(t, "stub", usage.Model)
+
+	status := applyAnalysis(domain, analysis, hints)
+	assert.Equal(t, model.StatusPendingOutput, status)
+	assert.Equal(t, "自動車整備業", domain.Industry)
+	assert.Equal(t, "株式会社タロウ", domain.Company)
+	assert.Equal(t, "山田太郎", domain.President)
+	assert.Equal(t, "大阪府", domain.Prefecture)
+	assert.Equal(t, "06-1234-5678", domain.LandlinePhone)
+	assert.Empty(t, domain.ReviewReason)
+}
@@ -4,14 +4,16 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"sort"
-	"strconv"
+	"net"
+	"net/url"
+	"strings"
 	"time"
 
 	"github.com/zuxt268/sales/internal/config"
 	"github.com/zuxt268/sales/internal/entity"
 	"github.com/zuxt268/sales/internal/interfaces/adapter"
 	"github.com/zuxt268/sales/internal/interfaces/dto/external"
+	"github.com/zuxt268/sales/internal/interfaces/dto/response"
 	"github.com/zuxt268/sales/internal/interfaces/repository"
 	"github.com/zuxt268/sales/internal/model"
 	"github.com/zuxt268/sales/internal/util"
@@ -22,120 +24,173 @@ import (
 type GrowthUsecase interface {
 	Fetch(ctx context.Context) error
 	Polling(ctx context.Context) error
-	Analyze(ctx context.Context, domainMessage *external.DomainMessage) error
+	Reap(ctx context.Context) (*response.ReapDomains, error)
+	Analyze(ctx context.Context, msg *external.PipelineMessage) error
+	CheckWix(ctx context.Context, msg *external.PipelineMessage) error
+	CheckView(ctx context.Context, msg *external.PipelineMessage) error
+	CheckJapan(ctx context.Context, msg *external.PipelineMessage) error
 	Output(ctx context.Context) error
 	FetchWix(ctx context.Context) error
+	GetFetchPlan(ctx context.Context) (*response.FetchPlan, error)
 }
 
 type growthUsecase struct {
 	baseRepo       repository.BaseRepository
 	domainRepo     repository.DomainRepository
 	targetRepo     repository.TargetRepository
-	pubSubAdapter  adapter.PubSubAdapter
-	viewDnsAdapter adapter.ViewDNSAdapter
+	sources        adapter.ReverseIPSources
 	sheetAdapter   adapter.SheetAdapter
 	gptAdapter     adapter.GptAdapter
+	crawlerAdapter adapter.CrawlerAdapter
+	healthAdapter  adapter.SiteHealthAdapter
+	llmUsageRepo   repository.LLMUsageRepository
+	industryRepo   repository.IndustryRepository
+	historyRepo    repository.DomainStatusHistoryRepository
+	outboxRepo     repository.OutboxRepository
+	processedRepo  repository.ProcessedMessageRepository
+	ledgerRepo     repository.APICallLedgerRepository
+	sightingRepo   repository.DomainSightingRepository
+	scanRepo       repository.TargetScanRepository
 }
 
 func NewGrowthUsecase(
 	baseRepo repository.BaseRepository,
 	domainRepo repository.DomainRepository,
 	targetRepo repository.TargetRepository,
-	pubSubAdapter adapter.PubSubAdapter,
-	viewDnsAdapter adapter.ViewDNSAdapter,
+	sources adapter.ReverseIPSources,
 	sheetAdapter adapter.SheetAdapter,
 	gptAdapter adapter.GptAdapter,
+	crawlerAdapter adapter.CrawlerAdapter,
+	healthAdapter adapter.SiteHealthAdapter,
+	llmUsageRepo repository.LLMUsageRepository,
+	industryRepo repository.IndustryRepository,
+	historyRepo repository.DomainStatusHistoryRepository,
+	outboxRepo repository.OutboxRepository,
+	processedRepo repository.ProcessedMessageRepository,
+	ledgerRepo repository.APICallLedgerRepository,
+	sightingRepo repository.DomainSightingRepository,
+	scanRepo repository.TargetScanRepository,
 ) GrowthUsecase {
 	return &growthUsecase{
 		baseRepo:       baseRepo,
 		domainRepo:     domainRepo,
 		targetRepo:     targetRepo,
-		pubSubAdapter:  pubSubAdapter,
-		viewDnsAdapter: viewDnsAdapter,
+		sources:        sources,
 		sheetAdapter:   sheetAdapter,
 		gptAdapter:     gptAdapter,
+		crawlerAdapter: crawlerAdapter,
+		healthAdapter:  healthAdapter,
+		llmUsageRepo:   llmUsageRepo,
+		industryRepo:   industryRepo,
+		historyRepo:    historyRepo,
+		outboxRepo:     outboxRepo,
+		processedRepo:  processedRepo,
+		ledgerRepo:     ledgerRepo,
+		sightingRepo:   sightingRepo,
+		scanRepo:       scanRepo,
 	}
 }
 
 const (
-	totalCallsPerRun     = 100   // プランに合わせて調整。1日上限より少なく。
-	nonWixRatio          = 0.8   // 80% 非WIX, 20% WIX など
-	pollingBatchSize     = 300   // Pollingで一度に処理するドメイン数
-	maxConcurrentPolling = 20    // Polling時の最大並行処理数
-	reverseIPPageSize    = 10000 // viewdns の 1 ページあたり件数
+	pollingBatchSize     = 300 // Pollingで一度に処理するドメイン数
+	maxConcurrentPolling = 20  // Polling時の最大並行処理数
 )
 
-// sortTargetsByLastFetched sorts targets by LastFetchedAt (NULL first, then oldest first)
-func sortTargetsByLastFetched(targets []*model.Target) {
-	sort.Slice(targets, func(i, j int) bool {
-		ti, tj := targets[i], targets[j]
-		if ti.LastFetchedAt == nil && tj.LastFetchedAt != nil {
-			return true
-		}
-		if ti.LastFetchedAt != nil && tj.LastFetchedAt == nil {
-			return false
-		}
-		if ti.LastFetchedAt == nil && tj.LastFetchedAt == nil {
-			return ti.ID < tj.ID
-		}
-		return ti.LastFetchedAt.Before(*tj.LastFetchedAt)
-	})
-}
-
+// Fetch 計画に沿ってReverseIPを呼び、各ターゲットのページを進める
+// 呼び出し回数は FETCH_CALLS_PER_RUN と当日の残り回数の少ない方までで、枠の配分は entity.PlanFetch に従う
 func (u *growthUsecase) Fetch(ctx context.Context) error {
-
-	// 1. ターゲット全取得（ソートは Filter でもいいし、Go 側でもよい）
-	targets, err := u.targetRepo.FindAll(ctx, repository.TargetFilter{
-		// OrderLastFetchedAsc は無くてもOK。Go側で sort.Slice しているので。
-	})
+	targets, err := u.targetRepo.FindAll(ctx, repository.TargetFilter{})
 	if err != nil {
 		return err
 	}
+	calls, err := u.planFetch(ctx, targets, config.Env.FetchCallsPerRun, config.Env.FetchNonWixRatio)
+	if err != nil {
+		return err
+	}
+	return u.runFetchPlan(ctx, calls)
+}
 
-	var nonWixTargets, wixTargets []*model.Target
-	for _, t := range targets {
-		if t.Name == "WIX" {
-			wixTargets = append(wixTargets, t)
-		} else {
-			nonWixTargets = append(nonWixTargets, t)
+// planFetch FETCH_CALLS_PER_RUN の枠で計画し、ViewDNSの呼び出しは当日の残り回数までにする
+func (u *growthUsecase) planFetch(ctx context.Context, targets []*model.Target, callsPerRun int, nonWixRatio float64) ([]*model.Target, error) {
+	_, remaining, err := viewDNSRemaining(ctx, u.ledgerRepo)
+	if err != nil {
+		return nil, err
+	}
+	calls := entity.PlanFetch(targets, time.Now(), callsPerRun, nonWixRatio)
+	if remaining < 0 {
+		return calls, nil
+	}
+	planned := make([]*model.Target, 0, len(calls))
+	for _, t := range calls {
+		if t.GetSource() == model.ReverseIPSourceViewDNS {
+			if remaining == 0 {
+				continue
+			}
+			remaining--
 		}
+		planned = append(planned, t)
 	}
+	return planned, nil
+}
 
-	// 2. 最近叩いていないものから優先（LastFetchedAt が NULL のものを先）
-	sortTargetsByLastFetched(nonWixTargets)
-	sortTargetsByLastFetched(wixTargets)
-
-	// 3. レート制限に合わせて「非WIX枠」「WIX枠」を決める
-	nonWixQuota := int(float64(totalCallsPerRun) * nonWixRatio)
-	wixQuota := totalCallsPerRun - nonWixQuota
-
-	// 4. 非WIXから優先して 1IP = 最大1ページずつ進める
-	for _, t := range nonWixTargets {
-		if nonWixQuota <= 0 {
-			break
+// runFetchPlan 計画の順にページを進める
+// 失敗したターゲットは待機時間を記録してこの回では以降も飛ばし、ViewDNSの1日の上限に達したらViewDNSのターゲットを飛ばす
+func (u *growthUsecase) runFetchPlan(ctx context.Context, calls []*model.Target) error {
+	failed := make(map[int]error)
+	quotaExceeded := false
+	for _, t := range calls {
+		if _, ok := failed[t.ID]; ok {
+			continue
+		}
+		if quotaExceeded && t.GetSource() == model.ReverseIPSourceViewDNS {
+			continue
 		}
-		if err := u.fetchOnePage(ctx, t); err != nil {
+		if err := ctx.Err(); err != nil {
 			return err
 		}
-		nonWixQuota--
-	}
-
-	// 5. 余った枠で WIX も 1IP = 最大1ページずつ進める
-	for _, t := range wixTargets {
-		if wixQuota <= 0 {
-			break
+		err := u.fetchOnePage(ctx, t)
+		if err == nil {
+			continue
 		}
-		if err := u.fetchOnePage(ctx, t); err != nil {
+		if errors.Is(err, entity.ErrViewDNSQuotaExceeded) {
+			slog.Warn("viewdns daily quota exceeded, skip viewdns targets", "error", err)
+			quotaExceeded = true
+			continue
+		}
+		slog.Error("failed to fetch target", "target_id", t.ID, "ip", t.IP, "error", err)
+		failed[t.ID] = err
+		if err := u.recordFetchFailure(ctx, t, err); err != nil {
 			return err
 		}
-		wixQuota--
 	}
 
-	return nil
+	if len(failed) == 0 {
+		return nil
+	}
+	errs := make([]error, 0, len(failed))
+	for _, err := range failed {
+		errs = append(errs, err)
+	}
+	return fmt.Errorf("failed to fetch %d targets: %w", len(failed), errors.Join(errs...))
+}
+
+// recordFetchFailure 連続失敗回数を数え、次に取得するまでの待機時間を決める
+// fetchOnePage の途中で書き換えたページ位置を保存しないよう、DBから読み直して更新する
+func (u *growthUsecase) recordFetchFailure(ctx context.Context, target *model.Target, fetchErr error) error {
+	return u.baseRepo.WithTransaction(ctx, func(ctx context.Context) error {
+		t, err := u.targetRepo.GetForUpdate(ctx, repository.TargetFilter{ID: &target.ID})
+		if err != nil {
+			return err
+		}
+		t.FailureCount++
+		next := time.Now().Add(entity.TargetBackoff(t.FailureCount))
+		t.NextFetchAt = &next
+		t.LastError = fetchErr.Error()
+		return u.targetRepo.Save(ctx, t)
+	})
 }
 
 // 1つの target(IP) について、現在のページを1つだけ進める
-// 戻り値 bool は「実際に API を叩いて進捗があったかどうか」です。
 func (u *growthUsecase) fetchOnePage(ctx context.Context, target *model.Target) error {
 	// 次に叩くページ番号（current_page が 0 以下なら 1 から）
 	page := target.CurrentPage
@@ -143,52 +198,25 @@ func (u *growthUsecase) fetchOnePage(ctx context.Context, target *model.Target)
 		page = 1
 	}
 
-	// ViewDNS ReverseIP API 呼び出し
-	resp, err := u.viewDnsAdapter.GetReverseIP(ctx, &external.ReverseIpRequest{
-		Host:   target.IP,
-		ApiKey: config.Env.ApiKey,
-		Page:   page,
-	})
+	result, err := lookupReverseIP(ctx, u.ledgerRepo, u.sources, target, page)
 	if err != nil {
 		return fmt.Errorf("get reverse ip (ip=%s, page=%d): %w", target.IP, page, err)
 	}
 
 	// トランザクション内でドメイン保存とターゲット更新を行う
 	if err := u.baseRepo.WithTransaction(ctx, func(ctx context.Context) error {
-		// ドメイン保存（domains 側に UNIQUE 制約を貼っておいて、BulkInsert 内部で
-		// INSERT IGNORE / ON DUPLICATE KEY UPDATE を使う前提）
-		if len(resp.Response.Domains) > 0 {
-			for _, d := range resp.Response.Domains {
-				exists, err := u.domainRepo.Exists(ctx, repository.DomainFilter{
-					Name: &d.Name,
-				})
-				if err != nil {
-					return err
-				}
-				if exists {
-					continue
-				}
-				if err := u.domainRepo.Save(ctx, &model.Domain{
-					Name:   d.Name,
-					Target: target.Name,
-					Status: model.StatusInitialize,
-				}); err != nil {
-					return err
-				}
-			}
-		}
-
-		// このタイミングでの DomainCount から「現在の maxPage」を計算（DB には保存しない）
-		count, err := strconv.Atoi(resp.Response.DomainCount)
+		now := time.Now()
+		newDomains, err := saveReverseIPPage(ctx, u.domainRepo, u.sightingRepo, target, result.Domains, now)
 		if err != nil {
-			return fmt.Errorf("failed to parse domain count (ip=%s): %w", target.IP, err)
+			return err
 		}
-		maxPageNow := (count + reverseIPPageSize - 1) / reverseIPPageSize
 
-		now := time.Now()
+		// このタイミングでの「現在の maxPage」（統計用に保存するが、ページ進行には使わない）
+		maxPageNow := result.TotalPages
+		target.MaxPage = maxPageNow
 
 		// --- ページ進行ロジック ---
-		if target.Name == "WIX" {
+		if target.Platform == model.PlatformWix {
 			// WIX は「最初からやり直さない」方針：
 			// ・とにかく CurrentPage を前に進めていく
 			// ・末尾まで来ている場合は maxPageNow に張り付く
@@ -204,6 +232,21 @@ func (u *growthUsecase) fetchOnePage(ctx context.Context, target *model.Target)
 
 		} else {
 			// 非WIX は「なるべく1周取り切る」モード
+			// 1ページ目から取り直し始めた日時以降に見つからなかったドメインは、1周したところで移転したとみなす
+			if page == 1 {
+				t := now
+				target.ScanStartedAt = &t
+			}
+			fullScan := maxPageNow <= 0 || page >= maxPageNow
+			if err := recordTargetScan(ctx, u.scanRepo, target, page, len(result.Domains), newDomains, fullScan, now); err != nil {
+				return err
+			}
+			if fullScan && target.ScanStartedAt != nil {
+				if err := markMovedAway(ctx, u.domainRepo, u.sightingRepo, target, *target.ScanStartedAt, now); err != nil {
+					return err
+				}
+			}
+
 			if maxPageNow <= 0 {
 				// 0件なら一応「1周完了」とみなして CurrentPage を 1 にリセット
 				target.CurrentPage = 1
@@ -220,6 +263,9 @@ func (u *growthUsecase) fetchOnePage(ctx context.Context, target *model.Target)
 		}
 
 		target.LastFetchedAt = &now
+		target.FailureCount = 0
+		target.NextFetchAt = nil
+		target.LastError = ""
 		if err := u.targetRepo.Save(ctx, target); err != nil {
 			return fmt.Errorf("save target (ip=%s): %w", target.IP, err)
 		}
@@ -270,25 +316,114 @@ func (u *growthUsecase) Polling(ctx context.Context) error {
 	return nil
 }
 
+// handleDomain ステータスの変更と送信待ちのメッセージを同じトランザクションで書き込む
 func (u *growthUsecase) handleDomain(ctx context.Context, domain *model.Domain) error {
-	if err := u.pubSubAdapter.PushDomain(ctx, &external.DomainMessage{
-		DomainId: domain.ID,
-	}); err != nil {
-		return fmt.Errorf("pubsub publish failed: %w", err)
+	return u.baseRepo.WithTransaction(ctx, func(ctx context.Context) error {
+		next := entity.FirstCheckStatus(domain)
+		if err := transitionDomain(ctx, u.historyRepo, domain, next, model.ActorPolling, ""); err != nil {
+			return err
+		}
+		if err := u.domainRepo.Save(ctx, domain); err != nil {
+			return fmt.Errorf("failed to update status: %w", err)
+		}
+		return enqueuePipelineMessage(ctx, u.outboxRepo, pipelineMessageTypeOf(next), domain.ID, 0)
+	})
+}
+
+// Reap ワーカーの報告がないまま止まっているドメインを再送する
+// 再送回数がDomainMaxAttemptsに達したドメインはunknownにして処理対象から外す
+func (u *growthUsecase) Reap(ctx context.Context) (*response.ReapDomains, error) {
+	now := time.Now()
+	domains, err := u.domainRepo.FindAll(ctx, repository.DomainFilter{
+		Statuses:            entity.StuckStatuses,
+		StatusUpdatedBefore: util.Pointer(now.Add(-config.Env.DomainStuckAfter)),
+		Limit:               util.Pointer(pollingBatchSize),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("fetch stuck domains: %w", err)
+	}
+
+	result := &response.ReapDomains{}
+	for _, d := range domains {
+		abandoned, err := u.reapDomain(ctx, d.ID, now)
+		if err != nil {
+			return result, fmt.Errorf("failed to reap domain (domain_id=%d): %w", d.ID, err)
+		}
+		if abandoned == nil {
+			continue
+		}
+		if *abandoned {
+			result.Abandoned++
+		} else {
+			result.Retried++
+		}
 	}
 
-	domain.Status = model.StatusCheckView
-	if err := u.domainRepo.Save(ctx, domain); err != nil {
-		return fmt.Errorf("failed to update status: %w", err)
+	slog.Info("reap completed", "retried", result.Retried, "abandoned", result.Abandoned)
+	return result, nil
+}
+
+// reapDomain 1件のドメインを再送またはunknownにする。処理中に状態が変わっていた場合はnilを返す
+func (u *growthUsecase) reapDomain(ctx context.Context, id int, now time.Time) (*bool, error) {
+	var abandoned *bool
+	err := u.baseRepo.WithTransaction(ctx, func(ctx context.Context) error {
+		domain, err := u.domainRepo.GetForUpdate(ctx, repository.DomainFilter{ID: &id})
+		if err != nil {
+			return err
+		}
+		if !entity.IsStuck(domain, now, config.Env.DomainStuckAfter) {
+			return nil
+		}
+		if domain.Attempts >= config.Env.DomainMaxAttempts {
+			reason := fmt.Sprintf("%sのまま%d回再送しても応答がありません", domain.Status, domain.Attempts)
+			if err := transitionDomain(ctx, u.historyRepo, domain, model.StatusUnknown, model.ActorReaper, reason); err != nil {
+				return err
+			}
+			abandoned = util.Pointer(true)
+		} else {
+			domain.Attempts++
+			domain.StatusUpdatedAt = now
+			if err := enqueuePipelineMessage(ctx, u.outboxRepo, pipelineMessageTypeOf(domain.Status), domain.ID, domain.Attempts); err != nil {
+				return err
+			}
+			abandoned = util.Pointer(false)
+		}
+		if err := u.domainRepo.Save(ctx, domain); err != nil {
+			return fmt.Errorf("failed to update status: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
-	return nil
+	return abandoned, nil
 }
 
-func (u *growthUsecase) Analyze(ctx context.Context, domainMessage *external.DomainMessage) error {
-	slog.Info("analyzing domain", "domainMessage", domainMessage)
+func (u *growthUsecase) Analyze(ctx context.Context, msg *external.PipelineMessage) error {
+	slog.Info("analyzing domain", "domain_id", msg.DomainID, "message_id", msg.MessageID, "trace_id", msg.TraceID)
+
+	domain, err := u.domainRepo.Get(ctx, repository.DomainFilter{ID: &msg.DomainID})
+	if err != nil {
+		if errors.Is(err, entity.ErrNotFound) {
+			return nil
+		}
+		return err
+	}
+	if domain.Status != model.StatusCrawlCompInfo {
+		return nil
+	}
+	if processed, err := isProcessedMessage(ctx, u.processedRepo, msg, model.ConsumerGrowthAnalyze); err != nil || processed {
+		return err
+	}
+	// 予算を超えている場合はエラーを返してPub/Subに再配信させる
+	if err := checkLLMBudget(ctx, u.llmUsageRepo); err != nil {
+		return err
+	}
+	// 巡回はロックを取る前に行う
+	info := crawlCompanyInfo(ctx, u.crawlerAdapter, domain)
 
 	return u.baseRepo.WithTransaction(ctx, func(ctx context.Context) error {
-		domain, err := u.domainRepo.GetForUpdate(ctx, repository.DomainFilter{ID: &domainMessage.DomainId})
+		domain, err := u.domainRepo.GetForUpdate(ctx, repository.DomainFilter{ID: &msg.DomainID})
 		if err != nil {
 			if errors.Is(err, entity.ErrNotFound) {
 				return nil
@@ -298,19 +433,366 @@ func (u *growthUsecase) Analyze(ctx context.Context, domainMessage *external.Dom
 		if domain.Status != model.StatusCrawlCompInfo {
 			return nil
 		}
-		if err := u.gptAdapter.Analyze(ctx, domain); err != nil {
+		if ok, err := markMessageProcessed(ctx, u.processedRepo, msg, model.ConsumerGrowthAnalyze); err != nil || !ok {
+			return err
+		}
+		applyCompanyInfo(domain, info)
+		hints := applyContactInfo(domain)
+		analysis, usage, err := u.gptAdapter.Analyze(ctx, domain.RawPage, hints)
+		recordLLMUsage(ctx, u.llmUsageRepo, model.LLMFeatureAnalyzeDomain, usage, &domain.ID, nil)
+		if err != nil {
+			return err
+		}
+		status := applyAnalysis(domain, analysis, hints)
+		if err := transitionDomain(ctx, u.historyRepo, domain, status, model.ActorAnalyze, domain.ReviewReason); err != nil {
 			return err
 		}
-		domain.MobilePhone, domain.LandlinePhone = entity.SplitPhone(domain.Phone)
-		domain.Status = model.StatusPendingOutput
 		if err := u.domainRepo.Save(ctx, domain); err != nil {
 			return err
 		}
+		if err := saveDomainIndustries(ctx, u.industryRepo, domain); err != nil {
+			return err
+		}
 		slog.Info("analyzed", "domain", domain)
 		return nil
 	})
 }
 
+// CheckWix Wixのサイトが日本語のものか調べてオーナーIDを取り出す
+// 日本語のサイトでオーナーIDがあれば企業情報の解析に進め、それ以外とドメインが存在しないものは trash にする
+func (u *growthUsecase) CheckWix(ctx context.Context, msg *external.PipelineMessage) error {
+	domain, err := u.domainRepo.Get(ctx, repository.DomainFilter{ID: &msg.DomainID})
+	if err != nil {
+		if errors.Is(err, entity.ErrNotFound) {
+			return nil
+		}
+		return err
+	}
+	if domain.Status != model.StatusCheckWix {
+		return nil
+	}
+	if processed, err := isProcessedMessage(ctx, u.processedRepo, msg, model.ConsumerGrowthWix); err != nil || processed {
+		return err
+	}
+	// 取得はロックを取る前に行う
+	// ドメインが存在しない場合を除き、取得できないときは一時的なエラーかもしれないため、エラーを返して再配信に任せる
+	page, fetchErr := fetchTopPage(ctx, u.crawlerAdapter, domain.Name)
+	if fetchErr != nil && !isHostNotFound(fetchErr) {
+		return fmt.Errorf("failed to fetch top page (domain=%s): %w", domain.Name, fetchErr)
+	}
+
+	return u.baseRepo.WithTransaction(ctx, func(ctx context.Context) error {
+		domain, err := u.domainRepo.GetForUpdate(ctx, repository.DomainFilter{ID: &msg.DomainID})
+		if err != nil {
+			if errors.Is(err, entity.ErrNotFound) {
+				return nil
+			}
+			return err
+		}
+		if domain.Status != model.StatusCheckWix {
+			return nil
+		}
+		if ok, err := markMessageProcessed(ctx, u.processedRepo, msg, model.ConsumerGrowthWix); err != nil || !ok {
+			return err
+		}
+
+		var reason string
+		switch {
+		case fetchErr != nil:
+			reason = "host not found: " + fetchErr.Error()
+		case !entity.ScoreJapanesePage(domain.Name, page.HTML).IsJapanese():
+			domain.CanView = true
+			reason = "not japanese"
+		default:
+			domain.CanView = true
+			domain.IsJapan = true
+			if ownerID := entity.ExtractWixOwnerID(page.HTML); ownerID != "" {
+				domain.OwnerID = ownerID
+			} else if !entity.HasWixOwnerID(domain.OwnerID) {
+				reason = "wix owner id not found"
+			}
+		}
+		if reason != "" {
+			if err := transitionDomain(ctx, u.historyRepo, domain, model.StatusTrash, model.ActorWix, reason); err != nil {
+				return err
+			}
+			return u.domainRepo.Save(ctx, domain)
+		}
+
+		if err := transitionDomain(ctx, u.historyRepo, domain, model.StatusCrawlCompInfo, model.ActorWix, ""); err != nil {
+			return err
+		}
+		if err := u.domainRepo.Save(ctx, domain); err != nil {
+			return err
+		}
+		slog.Info("wix site checked", "domain", domain.Name, "owner_id", domain.OwnerID)
+		return enqueuePipelineMessage(ctx, u.outboxRepo, external.PipelineAnalyze, domain.ID, 0)
+	})
+}
+
+// siteHealth サイトの死活・SSLの確認結果
+type siteHealth struct {
+	page     *external.SitePage // 取得できたトップページ。httpsで取得できなければhttpで取得したもの
+	fetchErr error
+	cert     *external.SiteCertificate // TLSハンドシェイクができなかった場合はnil
+	pageNum  int                       // sitemap.xmlに載っているページ数。読めなかった場合は0
+}
+
+// CheckView トップページを取得して閲覧できるかを調べ、SSL証明書・リダイレクト・パーキング・ページ数を記録する
+// 閲覧できれば check_japan に進め、取得できないものとパーキングのページは trash にする
+func (u *growthUsecase) CheckView(ctx context.Context, msg *external.PipelineMessage) error {
+	domain, err := u.domainRepo.Get(ctx, repository.DomainFilter{ID: &msg.DomainID})
+	if err != nil {
+		if errors.Is(err, entity.ErrNotFound) {
+			return nil
+		}
+		return err
+	}
+	if domain.Status != model.StatusCheckView {
+		return nil
+	}
+	if processed, err := isProcessedMessage(ctx, u.processedRepo, msg, model.ConsumerGrowthView); err != nil || processed {
+		return err
+	}
+	// 取得はロックを取る前に行う
+	health := u.checkSiteHealth(ctx, domain.Name)
+
+	return u.baseRepo.WithTransaction(ctx, func(ctx context.Context) error {
+		domain, err := u.domainRepo.GetForUpdate(ctx, repository.DomainFilter{ID: &msg.DomainID})
+		if err != nil {
+			if errors.Is(err, entity.ErrNotFound) {
+				return nil
+			}
+			return err
+		}
+		if domain.Status != model.StatusCheckView {
+			return nil
+		}
+		if ok, err := markMessageProcessed(ctx, u.processedRepo, msg, model.ConsumerGrowthView); err != nil || !ok {
+			return err
+		}
+
+		applySiteHealth(domain, health, time.Now())
+		var reason string
+		switch {
+		case health.page == nil:
+			reason = "unreachable: " + health.fetchErr.Error()
+		case domain.IsParked:
+			reason = "parked page"
+		}
+		if reason != "" {
+			if err := transitionDomain(ctx, u.historyRepo, domain, model.StatusTrash, model.ActorHealth, reason); err != nil {
+				return err
+			}
+			return u.domainRepo.Save(ctx, domain)
+		}
+
+		if err := transitionDomain(ctx, u.historyRepo, domain, model.StatusCheckJapan, model.ActorHealth, ""); err != nil {
+			return err
+		}
+		if err := u.domainRepo.Save(ctx, domain); err != nil {
+			return err
+		}
+		slog.Info("site health checked", "domain", domain.Name, "is_ssl", domain.IsSSL, "page_num", domain.PageNum)
+		return enqueuePipelineMessage(ctx, u.outboxRepo, external.PipelineCheckJapan, domain.ID, 0)
+	})
+}
+
+// checkSiteHealth トップページをhttps、だめならhttpで取得し、TLSハンドシェイクとsitemap.xmlのページ数を調べる
+func (u *growthUsecase) checkSiteHealth(ctx context.Context, name string) *siteHealth {
+	h := &siteHealth{}
+	page, err := fetchTopPage(ctx, u.crawlerAdapter, name)
+	h.page, h.fetchErr = page, err
+
+	cert, err := u.healthAdapter.CheckTLS(ctx, name)
+	if err != nil {
+		slog.Info("tls handshake failed", "domain", name, "error", err)
+	}
+	h.cert = cert
+
+	if page != nil {
+		base := page.URL
+		if pu, err := url.Parse(page.URL); err == nil {
+			base = pu.Scheme + "://" + pu.Host
+		}
+		n, err := u.healthAdapter.CountSitemapPages(ctx, base)
+		if err != nil {
+			slog.Info("failed to count sitemap pages", "domain", name, "error", err)
+		}
+		h.pageNum = n
+	}
+	return h
+}
+
+// fetchTopPage トップページをhttpsで取得し、取得できなければhttpで取得する
+func fetchTopPage(ctx context.Context, crawlerAdapter adapter.CrawlerAdapter, name string) (*external.SitePage, error) {
+	page, httpsErr := crawlerAdapter.FetchPage(ctx, "https://"+name)
+	if httpsErr == nil {
+		return page, nil
+	}
+	page, httpErr := crawlerAdapter.FetchPage(ctx, "http://"+name)
+	if httpErr != nil {
+		return nil, errors.Join(httpsErr, httpErr)
+	}
+	return page, nil
+}
+
+// isHostNotFound ドメインの名前解決でホストが存在しないと返されたか
+func isHostNotFound(err error) bool {
+	var dnsErr *net.DNSError
+	return errors.As(err, &dnsErr) && dnsErr.IsNotFound
+}
+
+// applySiteHealth 確認結果をドメインに書き込む
+// 有効期限内で検証に通った証明書がある場合だけSSL対応とみなす
+func applySiteHealth(domain *model.Domain, h *siteHealth, now time.Time) {
+	domain.HealthCheckedAt = &now
+	domain.IsSSL = h.cert != nil && h.cert.Verified && h.cert.NotAfter.After(now)
+	domain.SSLIssuer = ""
+	domain.SSLExpiresAt = nil
+	if h.cert != nil {
+		notAfter := h.cert.NotAfter
+		domain.SSLIssuer = h.cert.Issuer
+		domain.SSLExpiresAt = &notAfter
+	}
+
+	domain.RedirectURL = ""
+	domain.IsParked = false
+	domain.CanView = false
+	if h.page == nil {
+		return
+	}
+	if entity.IsRedirectedAway(domain.Name, h.page.URL) {
+		domain.RedirectURL = h.page.URL
+	}
+	domain.IsParked = entity.IsParkedPage(h.page.URL, h.page.HTML)
+	domain.CanView = !domain.IsParked
+	if h.pageNum > 0 {
+		domain.PageNum = h.pageNum
+	}
+}
+
+// CheckJapan トップページの本文・lang・ドメイン・連絡先から日本のサイトかを判定する
+// 日本のサイトなら crawl_comp_info に進め、それ以外と取得できないものは trash にする
+func (u *growthUsecase) CheckJapan(ctx context.Context, msg *external.PipelineMessage) error {
+	domain, err := u.domainRepo.Get(ctx, repository.DomainFilter{ID: &msg.DomainID})
+	if err != nil {
+		if errors.Is(err, entity.ErrNotFound) {
+			return nil
+		}
+		return err
+	}
+	if domain.Status != model.StatusCheckJapan {
+		return nil
+	}
+	if processed, err := isProcessedMessage(ctx, u.processedRepo, msg, model.ConsumerGrowthJapan); err != nil || processed {
+		return err
+	}
+	// 取得はロックを取る前に行う
+	// 取得できない場合は一時的なエラーかもしれないため、エラーを返して再配信に任せる（止まったままなら再送の対象になる）
+	page, err := fetchTopPage(ctx, u.crawlerAdapter, domain.Name)
+	if err != nil {
+		return fmt.Errorf("failed to fetch top page (domain=%s): %w", domain.Name, err)
+	}
+	score := entity.ScoreJapanesePage(domain.Name, page.HTML)
+
+	return u.baseRepo.WithTransaction(ctx, func(ctx context.Context) error {
+		domain, err := u.domainRepo.GetForUpdate(ctx, repository.DomainFilter{ID: &msg.DomainID})
+		if err != nil {
+			if errors.Is(err, entity.ErrNotFound) {
+				return nil
+			}
+			return err
+		}
+		if domain.Status != model.StatusCheckJapan {
+			return nil
+		}
+		if ok, err := markMessageProcessed(ctx, u.processedRepo, msg, model.ConsumerGrowthJapan); err != nil || !ok {
+			return err
+		}
+
+		domain.IsJapan = score.IsJapanese()
+		if !domain.IsJapan {
+			reason := fmt.Sprintf("not japanese (score=%d)", score.Score)
+			if err := transitionDomain(ctx, u.historyRepo, domain, model.StatusTrash, model.ActorJapan, reason); err != nil {
+				return err
+			}
+			return u.domainRepo.Save(ctx, domain)
+		}
+
+		if err := transitionDomain(ctx, u.historyRepo, domain, model.StatusCrawlCompInfo, model.ActorJapan, ""); err != nil {
+			return err
+		}
+		if err := u.domainRepo.Save(ctx, domain); err != nil {
+			return err
+		}
+		slog.Info("japanese site checked", "domain", domain.Name)
+		return enqueuePipelineMessage(ctx, u.outboxRepo, external.PipelineAnalyze, domain.ID, 0)
+	})
+}
+
+// crawlCompanyInfo サイトを巡回して企業情報のテキストを集める
+// 取得できなかった場合は既存のRawPageをそのまま使うためnilを返す
+func crawlCompanyInfo(ctx context.Context, crawlerAdapter adapter.CrawlerAdapter, domain *model.Domain) *external.CompanyInfo {
+	info, err := crawlerAdapter.CrawlCompanyInfo(ctx, "https://"+domain.Name)
+	if err != nil {
+		slog.Warn("failed to crawl company info", "domain", domain.Name, "error", err)
+		return nil
+	}
+	if info.Text == "" {
+		return nil
+	}
+	return info
+}
+
+func applyCompanyInfo(domain *model.Domain, info *external.CompanyInfo) {
+	if info == nil {
+		return
+	}
+	domain.RawPage = info.Text
+	domain.CrawledURLs = strings.Join(info.URLs, "\n")
+}
+
+// applyAnalysis GPTの解析結果をドメインに反映し、次のステータスを返す
+// 住所や郵便番号から判定できた項目（hints）はGPTの推定より優先する
+// 解析結果が検証に通らない場合は出力せず要確認にする
+func applyAnalysis(domain *model.Domain, analysis *entity.CompanyAnalysis, hints entity.AnalysisHints) model.Status {
+	analysis.ApplyHints(hints)
+	if analysis.Industry != "" {
+		domain.Industry = analysis.Industry
+	}
+	if analysis.President != "" {
+		domain.President = analysis.President
+	}
+	if analysis.Company != "" {
+		domain.Company = analysis.Company
+	}
+	if analysis.Prefecture != "" {
+		domain.Prefecture = analysis.Prefecture
+	}
+
+	if reasons := analysis.Validate(config.Env.GptMinConfidence); len(reasons) > 0 {
+		domain.ReviewReason = strings.Join(reasons, ",")
+		return model.StatusNeedsReview
+	}
+	domain.ReviewReason = ""
+	return model.StatusPendingOutput
+}
+
+// applyContactInfo GPTに尋ねる前に、ページから機械的に抽出した連絡先で電話番号・住所を補完する
+// 判定できた項目をGPTに尋ねないためのヒントとして返す
+func applyContactInfo(domain *model.Domain) entity.AnalysisHints {
+	contact := entity.ExtractContactInfo(domain.RawPage)
+	if domain.Phone == "" {
+		domain.Phone = entity.JoinPhones(contact.Phones, 50)
+	}
+	domain.MobilePhone, domain.LandlinePhone = entity.SplitPhone(domain.Phone)
+	if domain.Address == "" && len(contact.Addresses) > 0 {
+		domain.Address = contact.Addresses[0]
+	}
+	return contact.AnalysisHints()
+}
+
 func (u *growthUsecase) Output(ctx context.Context) error {
 
 	domains, err := u.domainRepo.FindAll(ctx, repository.DomainFilter{
@@ -333,35 +815,59 @@ func (u *growthUsecase) Output(ctx context.Context) error {
 		return fmt.Errorf("failed to backup domains to Google Drive: %w", err)
 	}
 
-	if updateErr := u.domainRepo.BulkUpdateStatus(ctx, model.StatusPendingOutput, model.StatusDone); updateErr != nil {
-		return fmt.Errorf("failed to bulk update domain status: %w", updateErr)
+	if err := u.baseRepo.WithTransaction(ctx, func(ctx context.Context) error {
+		return bulkTransitionDomains(ctx, u.domainRepo, u.historyRepo, domains, model.StatusPendingOutput, model.StatusDone, model.ActorOutput)
+	}); err != nil {
+		return fmt.Errorf("failed to bulk update domain status: %w", err)
 	}
 	return nil
 }
 
+// FetchWix Wixのターゲットだけを FETCH_WIX_CALLS_PER_RUN 回まで進める
 func (u *growthUsecase) FetchWix(ctx context.Context) error {
-	// 1. ターゲット全取得（ソートは Filter でもいいし、Go 側でもよい）
 	targets, err := u.targetRepo.FindAll(ctx, repository.TargetFilter{
-		Name: util.Pointer("WIX"),
+		Platform: util.Pointer(model.PlatformWix),
 	})
 	if err != nil {
 		return err
 	}
+	calls, err := u.planFetch(ctx, targets, config.Env.FetchWixCallsPerRun, 0)
+	if err != nil {
+		return err
+	}
+	return u.runFetchPlan(ctx, calls)
+}
 
-	sortTargetsByLastFetched(targets)
-
-	wixQuota := 500
-	if len(targets) == 0 {
-		return nil
+// GetFetchPlan 次のFetchで呼び出す予定と、当日のViewDNSの残り回数
+func (u *growthUsecase) GetFetchPlan(ctx context.Context) (*response.FetchPlan, error) {
+	targets, err := u.targetRepo.FindAll(ctx, repository.TargetFilter{})
+	if err != nil {
+		return nil, err
+	}
+	used, remaining, err := viewDNSRemaining(ctx, u.ledgerRepo)
+	if err != nil {
+		return nil, err
+	}
+	calls, err := u.planFetch(ctx, targets, config.Env.FetchCallsPerRun, config.Env.FetchNonWixRatio)
+	if err != nil {
+		return nil, err
 	}
 
-	for wixQuota >= 0 {
-		for _, t := range targets {
-			if err := u.fetchOnePage(ctx, t); err != nil {
-				return err
-			}
-			wixQuota = wixQuota - 1
+	now := time.Now()
+	var backingOff []*model.Target
+	for _, t := range targets {
+		if t.NextFetchAt != nil && t.NextFetchAt.After(now) {
+			backingOff = append(backingOff, t)
 		}
 	}
-	return nil
+	return &response.FetchPlan{
+		Date:        startOfDay(now).Format(llmUsageDateLayout),
+		DailyLimit:  config.Env.ViewDnsDailyLimit,
+		Used:        used,
+		Remaining:   remaining,
+		CallsPerRun: config.Env.FetchCallsPerRun,
+		NonWixRatio: config.Env.FetchNonWixRatio,
+		Calls:       response.GetFetchPlanTargets(calls),
+		BackingOff:  response.GetBackingOffTargets(backingOff),
+	}, nil
 }
@@ -5,13 +5,10 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"net/http"
 	"net/url"
 	"strings"
 	"sync"
-	"time"
 
-	"github.com/PuerkitoBio/goquery"
 	"github.com/zuxt268/sales/internal/config"
 	"github.com/zuxt268/sales/internal/entity"
 	"github.com/zuxt268/sales/internal/interfaces/adapter"
@@ -39,6 +36,9 @@ type homstaUsecase struct {
 	gptAdapter   adapter.GptAdapter
 	sheetAdapter adapter.SheetAdapter
 	slackAdapter adapter.SlackAdapter
+	crawler      adapter.CrawlerAdapter
+	llmUsageRepo repository.LLMUsageRepository
+	industryRepo repository.IndustryRepository
 }
 
 func NewHomstaUsecase(
@@ -48,6 +48,9 @@ func NewHomstaUsecase(
 	gptAdapter adapter.GptAdapter,
 	sheetAdapter adapter.SheetAdapter,
 	slackAdapter adapter.SlackAdapter,
+	crawler adapter.CrawlerAdapter,
+	llmUsageRepo repository.LLMUsageRepository,
+	industryRepo repository.IndustryRepository,
 ) HomstaUsecase {
 	return &homstaUsecase{
 		baseRepo:     baseRepo,
@@ -56,6 +59,9 @@ func NewHomstaUsecase(
 		gptAdapter:   gptAdapter,
 		sheetAdapter: sheetAdapter,
 		slackAdapter: slackAdapter,
+		crawler:      crawler,
+		llmUsageRepo: llmUsageRepo,
+		industryRepo: industryRepo,
 	}
 }
 
@@ -124,58 +130,6 @@ func (u *homstaUsecase) GetHomsta(ctx context.Context, name string) (*model.Homs
 	return u.homstaRepo.Get(ctx, filter)
 }
 
-func getCompInfo(siteUrl string) (string, error) {
-	u, err := url.Parse(siteUrl)
-	if err != nil {
-		return "", err
-	}
-
-	targetUrl := u.String()
-	if get(targetUrl + "/service") {
-		targetUrl = targetUrl + "/service"
-	} else if get(siteUrl) {
-		// nothing to do
-	} else {
-		return "", errors.New("site is unavailable")
-	}
-
-	resp, err := http.Get(targetUrl)
-	if err != nil {
-		return "", err
-	}
-	defer resp.Body.Close()
-
-	doc, err := goquery.NewDocumentFromReader(resp.Body)
-	if err != nil {
-		return "", err
-	}
-
-	body := doc.Find("body")
-	body.Find("script,style,link,noscript").Remove()
-
-	text := strings.Join(strings.Fields(body.Text()), " ")
-	return text, nil
-}
-
-func get(u string) bool {
-	req, err := http.NewRequest("GET", u, nil)
-	if err != nil {
-		return false
-	}
-	client := &http.Client{
-		Timeout: 10 * time.Second,
-	}
-	resp, err := client.Do(req)
-	if err != nil {
-		return false
-	}
-	defer resp.Body.Close()
-	if resp.StatusCode != 200 {
-		return false
-	}
-	return true
-}
-
 func (u *homstaUsecase) AnalyzeIndustry(ctx context.Context) error {
 	domains, err := u.homstaRepo.FindAll(ctx, repository.HomstaFilter{
 		Industry:       util.Pointer(""),
@@ -186,19 +140,33 @@ func (u *homstaUsecase) AnalyzeIndustry(ctx context.Context) error {
 	}
 	fmt.Println("対象ドメイン", len(domains))
 	for _, domain := range domains {
-		text, err := getCompInfo(domain.SiteURL)
+		if err := checkLLMBudget(ctx, u.llmUsageRepo); err != nil {
+			return err
+		}
+		info, err := u.crawler.CrawlCompanyInfo(ctx, domain.SiteURL)
 		if err != nil {
 			fmt.Println(domain.SiteURL, err)
 			continue
 		}
-		text = fmt.Sprintf("サイト名: %s, ディスクリプション: %s", domain.BlogName, domain.Description) + text
-		industry, err := u.gptAdapter.AnalyzeSiteIndustry(ctx, text)
+		text := fmt.Sprintf("サイト名: %s, ディスクリプション: %s", domain.BlogName, domain.Description) + info.Text
+		industry, usage, err := u.gptAdapter.AnalyzeSiteIndustry(ctx, text)
+		recordLLMUsage(ctx, u.llmUsageRepo, model.LLMFeatureAnalyzeSiteIndustry, usage, nil, &domain.ID)
 		if err != nil {
 			fmt.Println(domain.SiteURL, err)
 			continue
 		}
+		// 業種リストに一致したものは正式名称に揃え、中分類コードで紐付ける
+		codes := entity.NormalizeIndustries(industry)
+		if len(codes) > 0 {
+			industry = entity.IndustryNames(codes)
+		}
 		domain.Industry = industry
-		if err := u.homstaRepo.Save(ctx, domain); err != nil {
+		if err := u.baseRepo.WithTransaction(ctx, func(ctx context.Context) error {
+			if err := u.homstaRepo.Save(ctx, domain); err != nil {
+				return err
+			}
+			return u.industryRepo.ReplaceHomstaIndustries(ctx, domain.ID, codes)
+		}); err != nil {
 			return err
 		}
 		fmt.Println(domain.Domain, domain.Industry)
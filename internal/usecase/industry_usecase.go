@@ -0,0 +1,57 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/zuxt268/sales/internal/entity"
+	"github.com/zuxt268/sales/internal/interfaces/dto/response"
+	"github.com/zuxt268/sales/internal/interfaces/repository"
+	"github.com/zuxt268/sales/internal/model"
+)
+
+type IndustryUsecase interface {
+	GetIndustries(ctx context.Context) (*response.Industries, error)
+}
+
+type industryUsecase struct {
+	industryRepo repository.IndustryRepository
+}
+
+func NewIndustryUsecase(
+	industryRepo repository.IndustryRepository,
+) IndustryUsecase {
+	return &industryUsecase{
+		industryRepo: industryRepo,
+	}
+}
+
+// GetIndustries 業種ごとのドメイン数・Homsta数を返す
+func (u *industryUsecase) GetIndustries(ctx context.Context) (*response.Industries, error) {
+	industries, err := u.industryRepo.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	domainCounts, err := u.industryRepo.CountDomains(ctx)
+	if err != nil {
+		return nil, err
+	}
+	homstaCounts, err := u.industryRepo.CountHomstas(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return response.GetIndustries(industries, domainCounts, homstaCounts), nil
+}
+
+// domainResponse domain_industriesに紐付いた業種コードを含めたレスポンス
+func domainResponse(ctx context.Context, industryRepo repository.IndustryRepository, d *model.Domain) (*response.Domain, error) {
+	codes, err := industryRepo.FindDomainIndustryCodes(ctx, []int{d.ID})
+	if err != nil {
+		return nil, err
+	}
+	return response.GetDomain(d, codes[d.ID]), nil
+}
+
+// saveDomainIndustries ドメインの業種を中分類コードで紐付け直す
+func saveDomainIndustries(ctx context.Context, industryRepo repository.IndustryRepository, domain *model.Domain) error {
+	return industryRepo.ReplaceDomainIndustries(ctx, domain.ID, entity.NormalizeIndustries(domain.Industry))
+}
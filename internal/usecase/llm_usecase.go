@@ -0,0 +1,159 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/zuxt268/sales/internal/config"
+	"github.com/zuxt268/sales/internal/entity"
+	"github.com/zuxt268/sales/internal/interfaces/adapter"
+	"github.com/zuxt268/sales/internal/interfaces/dto/external"
+	"github.com/zuxt268/sales/internal/interfaces/dto/request"
+	"github.com/zuxt268/sales/internal/interfaces/dto/response"
+	"github.com/zuxt268/sales/internal/interfaces/repository"
+	"github.com/zuxt268/sales/internal/model"
+)
+
+type LLMUsecase interface {
+	GetUsage(ctx context.Context, req request.GetLLMUsage) (*response.LLMUsage, error)
+	ClearCache(ctx context.Context) (*response.LLMCacheCleared, error)
+}
+
+type llmUsecase struct {
+	llmUsageRepo    repository.LLMUsageRepository
+	llmCacheAdapter adapter.LLMCacheAdapter
+}
+
+func NewLLMUsecase(
+	llmUsageRepo repository.LLMUsageRepository,
+	llmCacheAdapter adapter.LLMCacheAdapter,
+) LLMUsecase {
+	return &llmUsecase{
+		llmUsageRepo:    llmUsageRepo,
+		llmCacheAdapter: llmCacheAdapter,
+	}
+}
+
+const (
+	llmUsageDateLayout  = "2006-01-02"
+	llmUsageDefaultDays = 30
+)
+
+// GetUsage 日別・機能別の使用量と当日の予算の消化状況を返す
+// 期間の指定がない場合は直近30日分
+func (u *llmUsecase) GetUsage(ctx context.Context, req request.GetLLMUsage) (*response.LLMUsage, error) {
+	today := startOfDay(time.Now())
+	from := today.AddDate(0, 0, -(llmUsageDefaultDays - 1))
+	to := today.AddDate(0, 0, 1)
+	if req.From != nil {
+		t, err := time.ParseInLocation(llmUsageDateLayout, *req.From, time.Local)
+		if err != nil {
+			return nil, fmt.Errorf("invalid from %q: %w", *req.From, entity.ErrInvalidInput)
+		}
+		from = t
+	}
+	if req.To != nil {
+		t, err := time.ParseInLocation(llmUsageDateLayout, *req.To, time.Local)
+		if err != nil {
+			return nil, fmt.Errorf("invalid to %q: %w", *req.To, entity.ErrInvalidInput)
+		}
+		to = t.AddDate(0, 0, 1)
+	}
+
+	usages, err := u.llmUsageRepo.Summarize(ctx, repository.LLMUsageFilter{
+		From:    &from,
+		To:      &to,
+		Feature: req.Feature,
+	})
+	if err != nil {
+		return nil, err
+	}
+	budget, err := todayLLMBudget(ctx, u.llmUsageRepo)
+	if err != nil {
+		return nil, err
+	}
+	return &response.LLMUsage{
+		Today:  *budget,
+		Usages: usages,
+	}, nil
+}
+
+// ClearCache LLMの応答キャッシュをすべて削除する
+func (u *llmUsecase) ClearCache(ctx context.Context) (*response.LLMCacheCleared, error) {
+	deleted, err := u.llmCacheAdapter.Clear(ctx)
+	if err != nil {
+		return nil, err
+	}
+	slog.Info("llm cache cleared", "deleted", deleted)
+	return &response.LLMCacheCleared{Deleted: deleted}, nil
+}
+
+func startOfDay(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
+
+func todayLLMBudget(ctx context.Context, llmUsageRepo repository.LLMUsageRepository) (*response.LLMBudget, error) {
+	today := startOfDay(time.Now())
+	total, err := llmUsageRepo.Total(ctx, repository.LLMUsageFilter{From: &today})
+	if err != nil {
+		return nil, err
+	}
+	return &response.LLMBudget{
+		Date:        today.Format(llmUsageDateLayout),
+		TotalTokens: total.TotalTokens,
+		Cost:        total.Cost,
+		TokenBudget: config.Env.LLMDailyTokenBudget,
+		CostBudget:  config.Env.LLMDailyCostBudget,
+		Exceeded: entity.ExceedsLLMBudget(
+			total.TotalTokens, total.Cost,
+			config.Env.LLMDailyTokenBudget, config.Env.LLMDailyCostBudget,
+		),
+	}, nil
+}
+
+// checkLLMBudget 当日の使用量が予算に達している場合はErrLLMBudgetExceededを返す
+func checkLLMBudget(ctx context.Context, llmUsageRepo repository.LLMUsageRepository) error {
+	if config.Env.LLMDailyTokenBudget <= 0 && config.Env.LLMDailyCostBudget <= 0 {
+		return nil
+	}
+	budget, err := todayLLMBudget(ctx, llmUsageRepo)
+	if err != nil {
+		return err
+	}
+	if budget.Exceeded {
+		return fmt.Errorf("tokens=%d cost=%.4f: %w", budget.TotalTokens, budget.Cost, entity.ErrLLMBudgetExceeded)
+	}
+	return nil
+}
+
+// recordLLMUsage LLMの使用量を記録する。記録に失敗しても解析は止めない
+func recordLLMUsage(
+	ctx context.Context,
+	llmUsageRepo repository.LLMUsageRepository,
+	feature model.LLMFeature,
+	usage *external.LLMUsage,
+	domainID, homstaID *int,
+) {
+	if usage == nil {
+		return
+	}
+	if err := llmUsageRepo.Create(ctx, &model.LLMUsage{
+		Feature:          feature,
+		Model:            usage.Model,
+		PromptTokens:     usage.PromptTokens,
+		CompletionTokens: usage.CompletionTokens,
+		TotalTokens:      usage.PromptTokens + usage.CompletionTokens,
+		Cost: entity.LLMCost(
+			usage.PromptTokens, usage.CompletionTokens,
+			config.Env.LLMInputPricePerMTok, config.Env.LLMOutputPricePerMTok,
+		),
+		LatencyMs: usage.Latency.Milliseconds(),
+		DomainID:  domainID,
+		HomstaID:  homstaID,
+	}); err != nil {
+		slog.Error("failed to record llm usage", "feature", feature, "error", err)
+	}
+}
@@ -0,0 +1,140 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/zuxt268/sales/internal/config"
+	"github.com/zuxt268/sales/internal/entity"
+	"github.com/zuxt268/sales/internal/interfaces/adapter"
+	"github.com/zuxt268/sales/internal/interfaces/dto/external"
+	"github.com/zuxt268/sales/internal/interfaces/dto/response"
+	"github.com/zuxt268/sales/internal/interfaces/repository"
+	"github.com/zuxt268/sales/internal/model"
+)
+
+type OutboxUsecase interface {
+	Relay(ctx context.Context) (*response.RelayOutbox, error)
+}
+
+type outboxUsecase struct {
+	outboxRepo repository.OutboxRepository
+	queue      adapter.Queue
+}
+
+func NewOutboxUsecase(
+	outboxRepo repository.OutboxRepository,
+	queue adapter.Queue,
+) OutboxUsecase {
+	return &outboxUsecase{
+		outboxRepo: outboxRepo,
+		queue:      queue,
+	}
+}
+
+const outboxRelayBatchSize = 500
+
+// Relay 未送信のメッセージをキューに送信する
+// 送信に失敗したメッセージは間隔を空けて再送し、OutboxMaxAttempts回失敗したらfailedにする
+// 送信後に記録が失敗すると同じメッセージが再送されることがあるため、受信側はMessageIDで重複を除く
+func (u *outboxUsecase) Relay(ctx context.Context) (*response.RelayOutbox, error) {
+	now := time.Now()
+	msgs, err := u.outboxRepo.FindPending(ctx, now, outboxRelayBatchSize)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &response.RelayOutbox{}
+	for _, m := range msgs {
+		m.Attempts++
+		if err := u.queue.Publish(ctx, m.Topic, []byte(m.Payload)); err != nil {
+			m.LastError = err.Error()
+			if m.Attempts >= config.Env.OutboxMaxAttempts {
+				m.Status = model.OutboxStatusFailed
+				result.Failed++
+			} else {
+				m.NextAttemptAt = now.Add(entity.OutboxBackoff(m.Attempts))
+				result.Retrying++
+			}
+			slog.Warn("failed to publish outbox message", "message_id", m.MessageID, "attempts", m.Attempts, "error", err)
+		} else {
+			m.Status = model.OutboxStatusSent
+			m.SentAt = &now
+			m.LastError = ""
+			result.Sent++
+		}
+		if err := u.outboxRepo.Save(ctx, m); err != nil {
+			return result, err
+		}
+	}
+
+	if len(msgs) > 0 {
+		slog.Info("outbox relayed", "sent", result.Sent, "retrying", result.Retrying, "failed", result.Failed)
+	}
+	return result, nil
+}
+
+// enqueuePipelineMessage ドメインの処理の依頼を送信待ちとしてoutboxに書き込む
+// ステータスの変更と同じトランザクション内で呼び、送信はRelayに任せる
+func enqueuePipelineMessage(
+	ctx context.Context,
+	outboxRepo repository.OutboxRepository,
+	msgType external.PipelineMessageType,
+	domainID, attempt int,
+) error {
+	msg := &external.PipelineMessage{
+		Version:    external.PipelineMessageVersion,
+		MessageID:  uuid.NewString(),
+		Type:       msgType,
+		DomainID:   domainID,
+		Attempt:    attempt,
+		TraceID:    uuid.NewString(),
+		EnqueuedAt: time.Now(),
+	}
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("failed to marshal pipeline message: %w", err)
+	}
+	return outboxRepo.Create(ctx, &model.OutboxMessage{
+		MessageID:     msg.MessageID,
+		Topic:         external.DomainPipelineTopic,
+		Payload:       string(data),
+		Status:        model.OutboxStatusPending,
+		NextAttemptAt: time.Now(),
+	})
+}
+
+// isProcessedMessage 受信したメッセージが処理済みかどうか。MessageIDのない古いメッセージは未処理とみなす
+func isProcessedMessage(ctx context.Context, processedRepo repository.ProcessedMessageRepository, msg *external.PipelineMessage, consumer string) (bool, error) {
+	if msg.MessageID == "" {
+		return false, nil
+	}
+	return processedRepo.Exists(ctx, msg.MessageID, consumer)
+}
+
+// markMessageProcessed 受信したメッセージを処理済みにする。処理結果と同じトランザクション内で呼ぶ
+// 他の配信で既に処理済みになっていた場合はfalseを返す
+func markMessageProcessed(ctx context.Context, processedRepo repository.ProcessedMessageRepository, msg *external.PipelineMessage, consumer string) (bool, error) {
+	if msg.MessageID == "" {
+		return true, nil
+	}
+	return processedRepo.Create(ctx, msg.MessageID, consumer)
+}
+
+// pipelineMessageTypeOf ステータスに対応するワーカーの処理の種類
+func pipelineMessageTypeOf(status model.Status) external.PipelineMessageType {
+	switch status {
+	case model.StatusCheckJapan:
+		return external.PipelineCheckJapan
+	case model.StatusCheckWix:
+		return external.PipelineCheckWix
+	case model.StatusCrawlCompInfo:
+		return external.PipelineAnalyze
+	default:
+		return external.PipelineCheckView
+	}
+}
@@ -0,0 +1,189 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/zuxt268/sales/internal/config"
+	"github.com/zuxt268/sales/internal/entity"
+	"github.com/zuxt268/sales/internal/interfaces/adapter"
+	"github.com/zuxt268/sales/internal/interfaces/repository"
+	"github.com/zuxt268/sales/internal/model"
+	"github.com/zuxt268/sales/internal/util"
+)
+
+// viewDNSRemaining 当日のReverseIPの呼び出し回数と残り回数。上限が0の場合は残りを-1（無制限）とする
+func viewDNSRemaining(ctx context.Context, ledgerRepo repository.APICallLedgerRepository) (used, remaining int, err error) {
+	used, err = ledgerRepo.GetCalls(ctx, model.APIViewDNSReverseIP, startOfDay(time.Now()))
+	if err != nil {
+		return 0, 0, err
+	}
+	if config.Env.ViewDnsDailyLimit <= 0 {
+		return used, -1, nil
+	}
+	return used, max(config.Env.ViewDnsDailyLimit-used, 0), nil
+}
+
+// lookupReverseIP ターゲットの取得元で逆引きする
+// ViewDNSの場合は1日の上限を確認し、失敗した呼び出しも上限に数えられるため呼び出す前に1回分を記録する
+// 再試行した分は呼び出した後に足す
+func lookupReverseIP(
+	ctx context.Context,
+	ledgerRepo repository.APICallLedgerRepository,
+	sources adapter.ReverseIPSources,
+	target *model.Target,
+	page int,
+) (*adapter.ReverseIPPage, error) {
+	name := target.GetSource()
+	source, ok := sources[name]
+	if !ok {
+		return nil, fmt.Errorf("reverse ip source %q is not configured: %w", name, entity.ErrValidation)
+	}
+
+	if name == model.ReverseIPSourceViewDNS {
+		used, remaining, err := viewDNSRemaining(ctx, ledgerRepo)
+		if err != nil {
+			return nil, err
+		}
+		if remaining == 0 {
+			return nil, fmt.Errorf("calls=%d limit=%d: %w", used, config.Env.ViewDnsDailyLimit, entity.ErrViewDNSQuotaExceeded)
+		}
+		if err := ledgerRepo.Increment(ctx, model.APIViewDNSReverseIP, startOfDay(time.Now()), 1); err != nil {
+			return nil, err
+		}
+	}
+	result, requests, err := source.Lookup(ctx, target.IP, page)
+	if name == model.ReverseIPSourceViewDNS && requests > 1 {
+		if incErr := ledgerRepo.Increment(ctx, model.APIViewDNSReverseIP, startOfDay(time.Now()), requests-1); incErr != nil && err == nil {
+			return nil, incErr
+		}
+	}
+	return result, err
+}
+
+// saveReverseIPPage 逆引きで見つけたドメインを登録し、ターゲットで見つけた記録を更新する。初めて登録したドメイン数を返す
+// 既にあるドメインは登録し直さない（ターゲットと取得元は最初に見つけたものを残す）
+func saveReverseIPPage(
+	ctx context.Context,
+	domainRepo repository.DomainRepository,
+	sightingRepo repository.DomainSightingRepository,
+	target *model.Target,
+	found []adapter.ReverseIPDomain,
+	now time.Time,
+) (int, error) {
+	domains := make([]*model.Domain, 0, len(found))
+	sightings := make([]*model.DomainSighting, 0, len(found))
+	names := make([]string, 0, len(found))
+	for _, d := range found {
+		domains = append(domains, &model.Domain{
+			Name:     d.Name,
+			Target:   target.Name,
+			Source:   target.GetSource(),
+			Platform: target.GetPlatform(),
+			Status:   model.StatusInitialize,
+		})
+		sightings = append(sightings, &model.DomainSighting{
+			Domain:       d.Name,
+			TargetIP:     target.IP,
+			LastResolved: d.LastResolved,
+			FirstSeen:    now,
+			LastSeen:     now,
+		})
+		names = append(names, d.Name)
+	}
+
+	if len(names) == 0 {
+		return 0, nil
+	}
+	existing, err := domainRepo.Count(ctx, repository.DomainFilter{Names: names})
+	if err != nil {
+		return 0, err
+	}
+	if err := domainRepo.BulkInsert(ctx, domains); err != nil {
+		return 0, err
+	}
+	if err := sightingRepo.Upsert(ctx, sightings); err != nil {
+		return 0, err
+	}
+	// 移転したとみなしていたドメインがまた見つかった
+	if err := domainRepo.UpdateMovedAway(ctx, names, nil); err != nil {
+		return 0, err
+	}
+	return len(names) - int(existing), nil
+}
+
+// recordTargetScan ターゲットの1周分の記録に取得したページを加える
+// 1ページ目なら新しい周の記録を作り、最後のページならその周を終える
+func recordTargetScan(
+	ctx context.Context,
+	scanRepo repository.TargetScanRepository,
+	target *model.Target,
+	page int,
+	domainsFound int,
+	newDomains int,
+	finished bool,
+	now time.Time,
+) error {
+	var scan *model.TargetScan
+	if page > 1 {
+		var err error
+		scan, err = scanRepo.Get(ctx, repository.TargetScanFilter{
+			TargetID:   &target.ID,
+			Unfinished: util.Pointer(true),
+		})
+		if err != nil && !errors.Is(err, entity.ErrNotFound) {
+			return err
+		}
+	}
+	if scan == nil {
+		// 途中のページから記録を始めた場合は、取り直し始めた日時を周の開始とする
+		startedAt := now
+		if page > 1 && target.ScanStartedAt != nil {
+			startedAt = *target.ScanStartedAt
+		}
+		scan = &model.TargetScan{TargetID: target.ID, StartedAt: startedAt}
+	}
+	scan.Pages++
+	scan.DomainsFound += domainsFound
+	scan.NewDomains += newDomains
+	if finished {
+		t := now
+		scan.FinishedAt = &t
+	}
+	return scanRepo.Save(ctx, scan)
+}
+
+// markMovedAway ターゲットを1周取り直して scanStartedAt 以降に見つからなかったドメインを、そのターゲットから移転したとみなす
+// ほかのターゲットでも見つかっていないドメインは、ドメインにも移転した日時を記録する
+func markMovedAway(
+	ctx context.Context,
+	domainRepo repository.DomainRepository,
+	sightingRepo repository.DomainSightingRepository,
+	target *model.Target,
+	scanStartedAt time.Time,
+	now time.Time,
+) error {
+	names, err := sightingRepo.MarkMovedAway(ctx, target.IP, scanStartedAt, now)
+	if err != nil || len(names) == 0 {
+		return err
+	}
+	active, err := sightingRepo.FindActiveDomains(ctx, names)
+	if err != nil {
+		return err
+	}
+	activeSet := make(map[string]struct{}, len(active))
+	for _, n := range active {
+		activeSet[n] = struct{}{}
+	}
+	movedAway := make([]string, 0, len(names))
+	for _, n := range names {
+		if _, ok := activeSet[n]; !ok {
+			movedAway = append(movedAway, n)
+		}
+	}
+	slog.Info("domains moved away from target", "target", target.Name, "ip", target.IP, "count", len(names), "moved_away", len(movedAway))
+	return domainRepo.UpdateMovedAway(ctx, movedAway, &now)
+}
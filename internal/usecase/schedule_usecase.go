@@ -0,0 +1,320 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/zuxt268/sales/internal/entity"
+	"github.com/zuxt268/sales/internal/interfaces/adapter"
+	"github.com/zuxt268/sales/internal/interfaces/dto/request"
+	"github.com/zuxt268/sales/internal/interfaces/dto/response"
+	"github.com/zuxt268/sales/internal/interfaces/repository"
+	"github.com/zuxt268/sales/internal/model"
+)
+
+// ErrScheduleRunning 同じジョブがどこかのインスタンスで実行中
+var ErrScheduleRunning = fmt.Errorf("schedule is already running: %w", entity.ErrConflict)
+
+// scheduleTick 実行日時を迎えたジョブを確認する間隔
+const scheduleTick = 15 * time.Second
+
+// Job 定期実行する処理
+type Job func(ctx context.Context) error
+
+type ScheduleUsecase interface {
+	Register(name, defaultCron string, job Job)
+	Run(ctx context.Context) error
+	GetSchedules(ctx context.Context) ([]*response.Schedule, error)
+	UpdateSchedule(ctx context.Context, name string, req request.UpdateSchedule) (*response.Schedule, error)
+	Trigger(ctx context.Context, name string) (*response.ScheduleRun, error)
+	GetScheduleRuns(ctx context.Context, name string, req request.GetScheduleRuns) ([]*response.ScheduleRun, error)
+}
+
+type registeredJob struct {
+	defaultCron string
+	job         Job
+}
+
+type scheduleUsecase struct {
+	scheduleRepo repository.ScheduleRepository
+	runRepo      repository.ScheduleRunRepository
+	lockAdapter  adapter.LockAdapter
+	slackAdapter adapter.SlackAdapter
+	jobTimeout   time.Duration
+	instance     string
+	jobs         map[string]registeredJob
+	wg           sync.WaitGroup
+	// 手動で実行したジョブもRunの終了時にキャンセルするためのctx
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+func NewScheduleUsecase(
+	scheduleRepo repository.ScheduleRepository,
+	runRepo repository.ScheduleRunRepository,
+	lockAdapter adapter.LockAdapter,
+	slackAdapter adapter.SlackAdapter,
+	jobTimeout time.Duration,
+) ScheduleUsecase {
+	hostname, _ := os.Hostname()
+	ctx, cancel := context.WithCancel(context.Background())
+	return &scheduleUsecase{
+		scheduleRepo: scheduleRepo,
+		runRepo:      runRepo,
+		lockAdapter:  lockAdapter,
+		slackAdapter: slackAdapter,
+		jobTimeout:   jobTimeout,
+		instance:     fmt.Sprintf("%s-%d", hostname, os.Getpid()),
+		jobs:         make(map[string]registeredJob),
+		ctx:          ctx,
+		cancel:       cancel,
+	}
+}
+
+// Register ジョブを登録する。Runの前に呼ぶ
+// defaultCronはDBにスケジュールがない場合だけ使い、以降はDBの設定（PUT /api/schedules/:name）に従う
+func (u *scheduleUsecase) Register(name, defaultCron string, job Job) {
+	u.jobs[name] = registeredJob{defaultCron: defaultCron, job: job}
+}
+
+// Run ctxがキャンセルされるまで実行日時を迎えたジョブを実行する
+// 複数のインスタンスで動かしても、同じ実行日時のジョブを実行するのは1つだけになる
+// キャンセル後は手動で実行したものも含めて実行中のジョブをキャンセルし、終了を待ってから戻る
+func (u *scheduleUsecase) Run(ctx context.Context) error {
+	if err := u.sync(ctx); err != nil {
+		return err
+	}
+	slog.Info("Scheduler started", "instance", u.instance, "jobs", len(u.jobs))
+
+	ticker := time.NewTicker(scheduleTick)
+	defer ticker.Stop()
+	for {
+		if err := u.runDue(ctx, time.Now()); err != nil {
+			slog.Error("failed to run scheduled jobs", "error", err)
+		}
+		select {
+		case <-ctx.Done():
+			u.cancel()
+			u.wg.Wait()
+			slog.Info("Scheduler stopped")
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// sync 登録されたジョブのうちDBにないものを作る
+func (u *scheduleUsecase) sync(ctx context.Context) error {
+	now := time.Now()
+	for name, j := range u.jobs {
+		cron, err := entity.ParseCron(j.defaultCron)
+		if err != nil {
+			return fmt.Errorf("invalid schedule %s: %w", name, err)
+		}
+		next := cron.Next(now)
+		if err := u.scheduleRepo.CreateIfNotExists(ctx, &model.Schedule{
+			Name:      name,
+			CronExpr:  j.defaultCron,
+			NextRunAt: &next,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (u *scheduleUsecase) runDue(ctx context.Context, now time.Time) error {
+	schedules, err := u.scheduleRepo.FindAll(ctx)
+	if err != nil {
+		return err
+	}
+	for _, s := range schedules {
+		if _, ok := u.jobs[s.Name]; !ok || s.Paused {
+			continue
+		}
+		if s.NextRunAt != nil && s.NextRunAt.After(now) {
+			continue
+		}
+		cron, err := entity.ParseCron(s.CronExpr)
+		if err != nil {
+			slog.Error("invalid cron expression", "schedule", s.Name, "cron_expr", s.CronExpr, "error", err)
+			continue
+		}
+		// 次の実行日時が決まっていない場合は決めるだけにする
+		// 停止中に過ぎた実行日時はまとめて1回だけ実行する
+		scheduled := s.NextRunAt != nil
+		claimed, err := u.scheduleRepo.Claim(ctx, s, cron.Next(now))
+		if err != nil {
+			return err
+		}
+		if !claimed || !scheduled {
+			continue
+		}
+		if _, err := u.start(ctx, s.Name, model.ScheduleTriggerCron); err != nil && !errors.Is(err, ErrScheduleRunning) {
+			slog.Error("failed to start scheduled job", "schedule", s.Name, "error", err)
+		}
+	}
+	return nil
+}
+
+// start ロックを取ってジョブを別のgoroutineで実行する。前回の実行が終わっていなければ skipped を記録する
+func (u *scheduleUsecase) start(ctx context.Context, name string, trigger model.ScheduleTrigger) (*model.ScheduleRun, error) {
+	run := &model.ScheduleRun{
+		ScheduleName: name,
+		Trigger:      trigger,
+		Status:       model.ScheduleRunRunning,
+		Instance:     u.instance,
+		StartedAt:    time.Now(),
+	}
+
+	// ロックはジョブのタイムアウトより少し長く持つ
+	unlock, ok, err := u.lockAdapter.TryLock(ctx, "schedule:"+name, u.jobTimeout+time.Minute)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		if trigger == model.ScheduleTriggerCron {
+			now := time.Now()
+			run.Status = model.ScheduleRunSkipped
+			run.FinishedAt = &now
+			if err := u.runRepo.Create(ctx, run); err != nil {
+				return nil, err
+			}
+			slog.Warn("scheduled job skipped because previous run is still running", "schedule", name)
+		}
+		return nil, ErrScheduleRunning
+	}
+	if err := u.runRepo.Create(ctx, run); err != nil {
+		unlock()
+		return nil, err
+	}
+
+	u.wg.Add(1)
+	go func() {
+		defer u.wg.Done()
+		defer unlock()
+		u.execute(ctx, name, run)
+	}()
+	return run, nil
+}
+
+func (u *scheduleUsecase) execute(ctx context.Context, name string, run *model.ScheduleRun) {
+	jobCtx, cancel := context.WithTimeout(ctx, u.jobTimeout)
+	defer cancel()
+
+	slog.Info("scheduled job started", "schedule", name, "trigger", run.Trigger, "run_id", run.ID)
+	err := func() (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = fmt.Errorf("panic: %v", r)
+			}
+		}()
+		return u.jobs[name].job(jobCtx)
+	}()
+
+	// 停止によるキャンセル後でも結果は記録する
+	ctx = context.WithoutCancel(ctx)
+	now := time.Now()
+	run.FinishedAt = &now
+	run.Status = model.ScheduleRunSucceeded
+	if err != nil {
+		run.Status = model.ScheduleRunFailed
+		run.Error = err.Error()
+		slog.Error("scheduled job failed", "schedule", name, "run_id", run.ID, "error", err)
+		if err := u.slackAdapter.Send(ctx, fmt.Sprintf("[%s]\n%s", name, err.Error())); err != nil {
+			slog.Error("failed to send slack", "error", err)
+		}
+	} else {
+		slog.Info("scheduled job finished", "schedule", name, "run_id", run.ID, "elapsed", now.Sub(run.StartedAt))
+	}
+	if err := u.runRepo.Save(ctx, run); err != nil {
+		slog.Error("failed to save schedule run", "schedule", name, "run_id", run.ID, "error", err)
+	}
+}
+
+func (u *scheduleUsecase) GetSchedules(ctx context.Context) ([]*response.Schedule, error) {
+	schedules, err := u.scheduleRepo.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]*response.Schedule, 0, len(schedules))
+	for _, s := range schedules {
+		lastRun, err := u.lastRun(ctx, s.Name)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, response.GetSchedule(s, lastRun))
+	}
+	return res, nil
+}
+
+// UpdateSchedule cron式の変更と一時停止・再開。変更した場合は次の実行日時を現在から計算し直す
+func (u *scheduleUsecase) UpdateSchedule(ctx context.Context, name string, req request.UpdateSchedule) (*response.Schedule, error) {
+	s, err := u.scheduleRepo.Get(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if req.CronExpr != nil {
+		if _, err := entity.ParseCron(*req.CronExpr); err != nil {
+			return nil, err
+		}
+		s.CronExpr = *req.CronExpr
+	}
+	if req.Paused != nil {
+		s.Paused = *req.Paused
+	}
+	cron, err := entity.ParseCron(s.CronExpr)
+	if err != nil {
+		return nil, err
+	}
+	next := cron.Next(time.Now())
+	s.NextRunAt = &next
+	if err := u.scheduleRepo.Save(ctx, s); err != nil {
+		return nil, err
+	}
+
+	lastRun, err := u.lastRun(ctx, s.Name)
+	if err != nil {
+		return nil, err
+	}
+	return response.GetSchedule(s, lastRun), nil
+}
+
+// Trigger スケジュールを待たずにジョブを実行する。一時停止中でも実行できる
+// ジョブはリクエストが終わっても続けるため、リクエストのctxは引き継がない
+func (u *scheduleUsecase) Trigger(_ context.Context, name string) (*response.ScheduleRun, error) {
+	if _, ok := u.jobs[name]; !ok {
+		return nil, entity.WrapNotFound("schedule")
+	}
+	run, err := u.start(u.ctx, name, model.ScheduleTriggerManual)
+	if err != nil {
+		return nil, err
+	}
+	return response.GetScheduleRun(run), nil
+}
+
+func (u *scheduleUsecase) GetScheduleRuns(ctx context.Context, name string, req request.GetScheduleRuns) ([]*response.ScheduleRun, error) {
+	runs, err := u.runRepo.FindAll(ctx, repository.ScheduleRunFilter{
+		ScheduleName: &name,
+		Limit:        req.Limit,
+		Offset:       req.Offset,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return response.GetScheduleRuns(runs), nil
+}
+
+func (u *scheduleUsecase) lastRun(ctx context.Context, name string) (*model.ScheduleRun, error) {
+	limit := 1
+	runs, err := u.runRepo.FindAll(ctx, repository.ScheduleRunFilter{ScheduleName: &name, Limit: &limit})
+	if err != nil || len(runs) == 0 {
+		return nil, err
+	}
+	return runs[0], nil
+}
@@ -22,6 +22,7 @@ type SheetUsecase interface {
 type sheetUsecase struct {
 	baseRepo     repository.BaseRepository
 	domainRepo   repository.DomainRepository
+	historyRepo  repository.DomainStatusHistoryRepository
 	sheetAdapter adapter.SheetAdapter
 	sshAdapter   adapter.SSHAdapter
 }
@@ -29,12 +30,14 @@ type sheetUsecase struct {
 func NewSheetUsecase(
 	baseRepo repository.BaseRepository,
 	domainRepo repository.DomainRepository,
+	historyRepo repository.DomainStatusHistoryRepository,
 	sheetAdapter adapter.SheetAdapter,
 	sshAdapter adapter.SSHAdapter,
 ) SheetUsecase {
 	return &sheetUsecase{
 		baseRepo:     baseRepo,
 		domainRepo:   domainRepo,
+		historyRepo:  historyRepo,
 		sheetAdapter: sheetAdapter,
 		sshAdapter:   sshAdapter,
 	}
@@ -149,7 +152,7 @@ func (u *sheetUsecase) BackupDomainsDirectly(ctx context.Context) error {
 	}
 
 	err = u.baseRepo.WithTransaction(ctx, func(ctx context.Context) error {
-		if updateErr := u.domainRepo.BulkUpdateStatus(ctx, model.StatusPendingOutput, model.StatusDone); updateErr != nil {
+		if updateErr := bulkTransitionDomains(ctx, u.domainRepo, u.historyRepo, domains, model.StatusPendingOutput, model.StatusDone, model.ActorBackup); updateErr != nil {
 			return fmt.Errorf("failed to bulk update domain status: %w", updateErr)
 		}
 		return nil
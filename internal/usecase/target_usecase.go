@@ -2,7 +2,14 @@ package usecase
 
 import (
 	"context"
+	"fmt"
+	"slices"
+	"strings"
 
+	"github.com/zuxt268/sales/internal/entity"
+	"github.com/zuxt268/sales/internal/interfaces/adapter"
+	"github.com/zuxt268/sales/internal/interfaces/dto/request"
+	"github.com/zuxt268/sales/internal/interfaces/dto/response"
 	"github.com/zuxt268/sales/internal/interfaces/repository"
 	"github.com/zuxt268/sales/internal/model"
 )
@@ -12,20 +19,41 @@ type TargetUsecase interface {
 	CreateTarget(ctx context.Context, req model.CreateTargetRequest) (*model.Target, error)
 	UpdateTarget(ctx context.Context, id int, req model.UpdateTargetRequest) (*model.Target, error)
 	DeleteTarget(ctx context.Context, id int) error
+	ImportTargets(ctx context.Context, req request.ImportTargets) (*response.TargetImport, error)
+	GetTargetStats(ctx context.Context, id int, req request.GetTargetStats) (*response.TargetStats, error)
+	GetTargetsStats(ctx context.Context, req request.GetTargetStats) ([]*response.TargetStats, error)
 }
 
+// maxImportAddresses 1回の取り込みで登録できるアドレスの上限
+const maxImportAddresses = 4096
+
+// 統計でターゲットごとに返す周回の記録の数
+const (
+	defaultStatsScans = 10
+	maxStatsScans     = 100
+)
+
 type targetUsecase struct {
-	baseRepo   repository.BaseRepository
-	targetRepo repository.TargetRepository
+	baseRepo        repository.BaseRepository
+	targetRepo      repository.TargetRepository
+	sightingRepo    repository.DomainSightingRepository
+	scanRepo        repository.TargetScanRepository
+	resolverAdapter adapter.ResolverAdapter
 }
 
 func NewTargetUsecase(
 	baseRepo repository.BaseRepository,
 	targetRepo repository.TargetRepository,
+	sightingRepo repository.DomainSightingRepository,
+	scanRepo repository.TargetScanRepository,
+	resolverAdapter adapter.ResolverAdapter,
 ) TargetUsecase {
 	return &targetUsecase{
-		baseRepo:   baseRepo,
-		targetRepo: targetRepo,
+		baseRepo:        baseRepo,
+		targetRepo:      targetRepo,
+		sightingRepo:    sightingRepo,
+		scanRepo:        scanRepo,
+		resolverAdapter: resolverAdapter,
 	}
 }
 
@@ -37,10 +65,35 @@ func (u *targetUsecase) GetTargets(ctx context.Context, req model.GetTargetsRequ
 }
 
 func (u *targetUsecase) CreateTarget(ctx context.Context, req model.CreateTargetRequest) (*model.Target, error) {
+	ip, ok := entity.NormalizeIP(req.IP)
+	if !ok {
+		return nil, fmt.Errorf("invalid ip %q: %w", req.IP, entity.ErrValidation)
+	}
 	target := &model.Target{
-		IP:     req.IP,
-		Name:   req.Name,
-		Status: model.TargetStatusInit,
+		IP:       ip,
+		Name:     req.Name,
+		Source:   model.ReverseIPSourceViewDNS,
+		Platform: model.PlatformGeneric,
+		Status:   model.TargetStatusInit,
+		Weight:   model.DefaultTargetWeight,
+	}
+	if req.Source != "" {
+		if err := validateReverseIPSource(req.Source); err != nil {
+			return nil, err
+		}
+		target.Source = req.Source
+	}
+	if req.Platform != "" {
+		if err := validatePlatform(req.Platform); err != nil {
+			return nil, err
+		}
+		target.Platform = req.Platform
+	}
+	if req.Weight != nil {
+		if err := validateTargetWeight(*req.Weight); err != nil {
+			return nil, err
+		}
+		target.Weight = *req.Weight
 	}
 
 	err := u.targetRepo.Save(ctx, target)
@@ -63,11 +116,39 @@ func (u *targetUsecase) UpdateTarget(ctx context.Context, id int, req model.Upda
 		}
 
 		if req.IP != nil {
-			target.IP = *req.IP
+			ip, ok := entity.NormalizeIP(*req.IP)
+			if !ok {
+				return fmt.Errorf("invalid ip %q: %w", *req.IP, entity.ErrValidation)
+			}
+			target.IP = ip
 		}
 		if req.Name != nil {
 			target.Name = *req.Name
 		}
+		if req.Source != nil {
+			if err := validateReverseIPSource(*req.Source); err != nil {
+				return err
+			}
+			target.Source = *req.Source
+		}
+		if req.Platform != nil {
+			if err := validatePlatform(*req.Platform); err != nil {
+				return err
+			}
+			target.Platform = *req.Platform
+		}
+		if req.Weight != nil {
+			if err := validateTargetWeight(*req.Weight); err != nil {
+				return err
+			}
+			target.Weight = *req.Weight
+		}
+		if req.Status != nil {
+			if !model.IsValidTargetStatus(*req.Status) {
+				return fmt.Errorf("unknown status %q: %w", *req.Status, entity.ErrValidation)
+			}
+			target.Status = *req.Status
+		}
 		if err := u.targetRepo.Save(ctx, target); err != nil {
 			return err
 		}
@@ -85,3 +166,249 @@ func (u *targetUsecase) DeleteTarget(ctx context.Context, id int) error {
 		ID: &id,
 	})
 }
+
+// ImportTargets IPアドレス・ホスト名・CIDRの一覧からターゲットをまとめて登録する
+// ホスト名は名前解決したアドレス、CIDRは範囲のアドレスをそれぞれ登録する
+func (u *targetUsecase) ImportTargets(ctx context.Context, req request.ImportTargets) (*response.TargetImport, error) {
+	result := &response.TargetImport{
+		Created:    []*response.TargetImportRow{},
+		Duplicates: []*response.TargetImportRow{},
+		Invalid:    []*response.TargetImportRow{},
+	}
+
+	type candidate struct {
+		row    *response.TargetImportRow
+		target *model.Target
+	}
+	var candidates []candidate
+	seen := make(map[string]bool)
+	for i, t := range req.Targets {
+		rowNum := i + 1
+		invalid := func(err error) {
+			result.Invalid = append(result.Invalid, &response.TargetImportRow{
+				Row: rowNum, Value: t.Value, Error: err.Error(),
+			})
+		}
+
+		source := model.ReverseIPSourceViewDNS
+		if t.Source != "" {
+			if err := validateReverseIPSource(t.Source); err != nil {
+				invalid(err)
+				continue
+			}
+			source = t.Source
+		}
+		platform := model.PlatformGeneric
+		if t.Platform != "" {
+			if err := validatePlatform(t.Platform); err != nil {
+				invalid(err)
+				continue
+			}
+			platform = t.Platform
+		}
+		weight := model.DefaultTargetWeight
+		if t.Weight != nil {
+			if err := validateTargetWeight(*t.Weight); err != nil {
+				invalid(err)
+				continue
+			}
+			weight = *t.Weight
+		}
+
+		ips, err := u.resolveImportValue(ctx, t.Value, maxImportAddresses-len(candidates))
+		if err != nil {
+			invalid(err)
+			continue
+		}
+
+		for _, ip := range ips {
+			row := &response.TargetImportRow{Row: rowNum, Value: t.Value, IP: ip}
+			if seen[ip] {
+				result.Duplicates = append(result.Duplicates, row)
+				continue
+			}
+			seen[ip] = true
+			name := strings.TrimSpace(t.Name)
+			if name == "" {
+				name = ip
+			}
+			candidates = append(candidates, candidate{
+				row: row,
+				target: &model.Target{
+					IP:       ip,
+					Name:     name,
+					Source:   source,
+					Platform: platform,
+					Status:   model.TargetStatusInit,
+					Weight:   weight,
+				},
+			})
+		}
+	}
+	if len(candidates) == 0 {
+		return result, nil
+	}
+
+	ips := make([]string, 0, len(candidates))
+	for _, c := range candidates {
+		ips = append(ips, c.target.IP)
+	}
+	existing, err := u.targetRepo.FindAll(ctx, repository.TargetFilter{IPs: ips})
+	if err != nil {
+		return nil, err
+	}
+	registered := make(map[string]bool, len(existing))
+	for _, t := range existing {
+		registered[t.IP] = true
+	}
+
+	var targets []*model.Target
+	for _, c := range candidates {
+		if registered[c.target.IP] {
+			result.Duplicates = append(result.Duplicates, c.row)
+			continue
+		}
+		targets = append(targets, c.target)
+		result.Created = append(result.Created, c.row)
+	}
+	if len(targets) > 0 {
+		if err := u.targetRepo.BulkInsert(ctx, targets); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// resolveImportValue 取り込む値をIPアドレスの一覧にする。limitは登録できる残りのアドレス数
+func (u *targetUsecase) resolveImportValue(ctx context.Context, value string, limit int) ([]string, error) {
+	value = strings.TrimSpace(value)
+	var ips []string
+	switch {
+	case value == "":
+		return nil, fmt.Errorf("value is empty: %w", entity.ErrValidation)
+	case strings.Contains(value, "/"):
+		expanded, err := entity.ExpandCIDR(value, limit)
+		if err != nil {
+			return nil, err
+		}
+		ips = expanded
+	case entity.IsHostname(value):
+		resolved, err := u.resolverAdapter.LookupIP(ctx, value)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range resolved {
+			if ip, ok := entity.NormalizeIP(r); ok && !slices.Contains(ips, ip) {
+				ips = append(ips, ip)
+			}
+		}
+		if len(ips) == 0 {
+			return nil, fmt.Errorf("no address found for %s", value)
+		}
+	default:
+		ip, ok := entity.NormalizeIP(value)
+		if !ok {
+			return nil, fmt.Errorf("invalid ip, hostname or cidr %q: %w", value, entity.ErrValidation)
+		}
+		ips = []string{ip}
+	}
+	if len(ips) > limit {
+		return nil, fmt.Errorf("import is limited to %d addresses: %w", maxImportAddresses, entity.ErrValidation)
+	}
+	return ips, nil
+}
+
+func (u *targetUsecase) GetTargetStats(ctx context.Context, id int, req request.GetTargetStats) (*response.TargetStats, error) {
+	target, err := u.targetRepo.Get(ctx, repository.TargetFilter{ID: &id})
+	if err != nil {
+		return nil, err
+	}
+	stats, err := u.buildTargetStats(ctx, []*model.Target{target}, req.Scans)
+	if err != nil {
+		return nil, err
+	}
+	return stats[0], nil
+}
+
+func (u *targetUsecase) GetTargetsStats(ctx context.Context, req request.GetTargetStats) ([]*response.TargetStats, error) {
+	targets, err := u.targetRepo.FindAll(ctx, repository.TargetFilter{
+		Limit:  req.Limit,
+		Offset: req.Offset,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return u.buildTargetStats(ctx, targets, req.Scans)
+}
+
+// buildTargetStats ターゲットごとのドメイン数と直近の周回の記録をまとめる
+func (u *targetUsecase) buildTargetStats(ctx context.Context, targets []*model.Target, scans *int) ([]*response.TargetStats, error) {
+	res := make([]*response.TargetStats, 0, len(targets))
+	if len(targets) == 0 {
+		return res, nil
+	}
+	scanLimit := defaultStatsScans
+	if scans != nil {
+		scanLimit = max(0, min(*scans, maxStatsScans))
+	}
+
+	ips := make([]string, 0, len(targets))
+	ids := make([]int, 0, len(targets))
+	for _, t := range targets {
+		ips = append(ips, t.IP)
+		ids = append(ids, t.ID)
+	}
+
+	// 名前は複数のターゲットで同じことがあるので、ドメインを見つけたIPで数える
+	counts, err := u.sightingRepo.CountByTargetIPAndStatus(ctx, ips)
+	if err != nil {
+		return nil, err
+	}
+	countsByIP := make(map[string]map[model.Status]int64)
+	for _, c := range counts {
+		if countsByIP[c.TargetIP] == nil {
+			countsByIP[c.TargetIP] = make(map[model.Status]int64)
+		}
+		countsByIP[c.TargetIP][c.Status] = c.Count
+	}
+
+	scansByID := make(map[int][]*model.TargetScan)
+	if scanLimit > 0 {
+		all, err := u.scanRepo.FindAll(ctx, repository.TargetScanFilter{
+			TargetIDs: ids,
+			PerTarget: &scanLimit,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, s := range all {
+			scansByID[s.TargetID] = append(scansByID[s.TargetID], s)
+		}
+	}
+
+	for _, t := range targets {
+		res = append(res, response.GetTargetStats(t, countsByIP[t.IP], scansByID[t.ID]))
+	}
+	return res, nil
+}
+
+func validateTargetWeight(weight int) error {
+	if weight < 0 {
+		return fmt.Errorf("weight must not be negative: %d: %w", weight, entity.ErrValidation)
+	}
+	return nil
+}
+
+func validateReverseIPSource(source string) error {
+	if !slices.Contains(model.ValidReverseIPSources, source) {
+		return fmt.Errorf("unknown source %q: %w", source, entity.ErrValidation)
+	}
+	return nil
+}
+
+func validatePlatform(platform string) error {
+	if !slices.Contains(model.ValidPlatforms, platform) {
+		return fmt.Errorf("unknown platform %q: %w", platform, entity.ErrValidation)
+	}
+	return nil
+}
@@ -0,0 +1,7 @@
+-- +migrate Up
+ALTER TABLE domains
+    ADD COLUMN crawled_urls TEXT NOT NULL COMMENT '企業情報の取得元URL（改行区切り）' AFTER page_num;
+
+-- +migrate Down
+ALTER TABLE domains
+    DROP COLUMN crawled_urls;
@@ -0,0 +1,7 @@
+-- +migrate Up
+ALTER TABLE domains
+    ADD COLUMN review_reason VARCHAR(500) NOT NULL DEFAULT '' COMMENT '要確認になった理由' AFTER crawled_urls;
+
+-- +migrate Down
+ALTER TABLE domains
+    DROP COLUMN review_reason;
@@ -0,0 +1,23 @@
+-- +migrate Up
+CREATE TABLE llm_usages (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    feature VARCHAR(50) NOT NULL COMMENT '機能',
+    model VARCHAR(100) NOT NULL DEFAULT '' COMMENT 'モデル名',
+    prompt_tokens INT NOT NULL DEFAULT 0 COMMENT '入力トークン数',
+    completion_tokens INT NOT NULL DEFAULT 0 COMMENT '出力トークン数',
+    total_tokens INT NOT NULL DEFAULT 0 COMMENT '合計トークン数',
+    cost DECIMAL(12, 6) NOT NULL DEFAULT 0 COMMENT '費用（USD）',
+    latency_ms BIGINT NOT NULL DEFAULT 0 COMMENT 'レイテンシ（ミリ秒）',
+    domain_id INT NULL COMMENT 'ドメインID',
+    homsta_id INT NULL COMMENT 'HomstaID',
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '作成日時',
+
+    -- インデックス
+    INDEX idx_llm_usages_created_at (created_at),
+    INDEX idx_llm_usages_feature (feature),
+    INDEX idx_llm_usages_domain_id (domain_id),
+    INDEX idx_llm_usages_homsta_id (homsta_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='LLM使用量テーブル';
+
+-- +migrate Down
+DROP TABLE IF EXISTS llm_usages;
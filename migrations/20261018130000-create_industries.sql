@@ -0,0 +1,170 @@
+-- +migrate Up
+CREATE TABLE industries (
+    code VARCHAR(2) NOT NULL PRIMARY KEY COMMENT '産業分類コード（大分類はA〜T、中分類は01〜99）',
+    name VARCHAR(255) NOT NULL COMMENT '名称',
+    level VARCHAR(10) NOT NULL COMMENT '分類の階層（major: 大分類, middle: 中分類）',
+    parent_code VARCHAR(2) NULL COMMENT '大分類コード（中分類のみ）',
+    sort_order INT NOT NULL DEFAULT 0 COMMENT '表示順',
+
+    -- インデックス
+    INDEX idx_industries_parent_code (parent_code),
+    INDEX idx_industries_name (name)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='業種（日本標準産業分類）テーブル';
+
+INSERT INTO industries (code, name, level, parent_code, sort_order) VALUES
+    ('A', '農業，林業', 'major', NULL, 1),
+    ('B', '漁業', 'major', NULL, 2),
+    ('C', '鉱業，採石業，砂利採取業', 'major', NULL, 3),
+    ('D', '建設業', 'major', NULL, 4),
+    ('E', '製造業', 'major', NULL, 5),
+    ('F', '電気・ガス・熱供給・水道業', 'major', NULL, 6),
+    ('G', '情報通信業', 'major', NULL, 7),
+    ('H', '運輸業，郵便業', 'major', NULL, 8),
+    ('I', '卸売業，小売業', 'major', NULL, 9),
+    ('J', '金融業，保険業', 'major', NULL, 10),
+    ('K', '不動産業，物品賃貸業', 'major', NULL, 11),
+    ('L', '学術研究，専門・技術サービス業', 'major', NULL, 12),
+    ('M', '宿泊業，飲食サービス業', 'major', NULL, 13),
+    ('N', '生活関連サービス業，娯楽業', 'major', NULL, 14),
+    ('O', '教育，学習支援業', 'major', NULL, 15),
+    ('P', '医療，福祉', 'major', NULL, 16),
+    ('Q', '複合サービス事業', 'major', NULL, 17),
+    ('R', 'サービス業（他に分類されないもの）', 'major', NULL, 18),
+    ('S', '公務（他に分類されるものを除く）', 'major', NULL, 19),
+    ('T', '分類不能の産業', 'major', NULL, 20),
+    ('01', '農業', 'middle', 'A', 1),
+    ('02', '林業', 'middle', 'A', 2),
+    ('03', '漁業（水産養殖業を除く）', 'middle', 'B', 3),
+    ('04', '水産養殖業', 'middle', 'B', 4),
+    ('05', '鉱業，採石業，砂利採取業', 'middle', 'C', 5),
+    ('06', '総合工事業', 'middle', 'D', 6),
+    ('07', '職別工事業(設備工事業を除く)', 'middle', 'D', 7),
+    ('08', '設備工事業', 'middle', 'D', 8),
+    ('09', '食料品製造業', 'middle', 'E', 9),
+    ('10', '飲料・たばこ・飼料製造業', 'middle', 'E', 10),
+    ('11', '繊維工業', 'middle', 'E', 11),
+    ('12', '木材・木製品製造業（家具を除く）', 'middle', 'E', 12),
+    ('13', '家具・装備品製造業', 'middle', 'E', 13),
+    ('14', 'パルプ・紙・紙加工品製造業', 'middle', 'E', 14),
+    ('15', '印刷・同関連業', 'middle', 'E', 15),
+    ('16', '化学工業', 'middle', 'E', 16),
+    ('17', '石油製品・石炭製品製造業', 'middle', 'E', 17),
+    ('18', 'プラスチック製品製造業（別掲を除く）', 'middle', 'E', 18),
+    ('19', 'ゴム製品製造業', 'middle', 'E', 19),
+    ('20', 'なめし革・同製品・毛皮製造業', 'middle', 'E', 20),
+    ('21', '窯業・土石製品製造業', 'middle', 'E', 21),
+    ('22', '鉄鋼業', 'middle', 'E', 22),
+    ('23', '非鉄金属製造業', 'middle', 'E', 23),
+    ('24', '金属製品製造業', 'middle', 'E', 24),
+    ('25', 'はん用機械器具製造業', 'middle', 'E', 25),
+    ('26', '生産用機械器具製造業', 'middle', 'E', 26),
+    ('27', '業務用機械器具製造業', 'middle', 'E', 27),
+    ('28', '電子部品・デバイス・電子回路製造業', 'middle', 'E', 28),
+    ('29', '電気機械器具製造業', 'middle', 'E', 29),
+    ('30', '情報通信機械器具製造業', 'middle', 'E', 30),
+    ('31', '輸送用機械器具製造業', 'middle', 'E', 31),
+    ('32', 'その他の製造業', 'middle', 'E', 32),
+    ('33', '電気業', 'middle', 'F', 33),
+    ('34', 'ガス業', 'middle', 'F', 34),
+    ('35', '熱供給業', 'middle', 'F', 35),
+    ('36', '水道業', 'middle', 'F', 36),
+    ('37', '通信業', 'middle', 'G', 37),
+    ('38', '放送業', 'middle', 'G', 38),
+    ('39', '情報サービス業', 'middle', 'G', 39),
+    ('40', 'インターネット附随サービス業', 'middle', 'G', 40),
+    ('41', '映像・音声・文字情報制作業', 'middle', 'G', 41),
+    ('42', '鉄道業', 'middle', 'H', 42),
+    ('43', '道路旅客運送業', 'middle', 'H', 43),
+    ('44', '道路貨物運送業', 'middle', 'H', 44),
+    ('45', '水運業', 'middle', 'H', 45),
+    ('46', '航空運輸業', 'middle', 'H', 46),
+    ('47', '倉庫業', 'middle', 'H', 47),
+    ('48', '運輸に附帯するサービス業', 'middle', 'H', 48),
+    ('49', '郵便業（信書便事業を含む）', 'middle', 'H', 49),
+    ('50', '各種商品卸売業', 'middle', 'I', 50),
+    ('51', '繊維・衣服等卸売業', 'middle', 'I', 51),
+    ('52', '飲食料品卸売業', 'middle', 'I', 52),
+    ('53', '建築材料，鉱物・金属材料等卸売業', 'middle', 'I', 53),
+    ('54', '機械器具卸売業', 'middle', 'I', 54),
+    ('55', 'その他の卸売業', 'middle', 'I', 55),
+    ('56', '各種商品小売業', 'middle', 'I', 56),
+    ('57', '織物・衣服・身の回り品小売業', 'middle', 'I', 57),
+    ('58', '飲食料品小売業', 'middle', 'I', 58),
+    ('59', '機械器具小売業', 'middle', 'I', 59),
+    ('60', 'その他の小売業', 'middle', 'I', 60),
+    ('61', '無店舗小売業', 'middle', 'I', 61),
+    ('62', '銀行業', 'middle', 'J', 62),
+    ('63', '協同組織金融業', 'middle', 'J', 63),
+    ('64', '貸金業，クレジットカード業等非預金信用機関', 'middle', 'J', 64),
+    ('65', '金融商品取引業，商品先物取引業', 'middle', 'J', 65),
+    ('66', '補助的金融業等', 'middle', 'J', 66),
+    ('67', '保険業（保険媒介代理業，保険サービス業を含む）', 'middle', 'J', 67),
+    ('68', '不動産取引業', 'middle', 'K', 68),
+    ('69', '不動産賃貸業・管理業', 'middle', 'K', 69),
+    ('70', '物品賃貸業', 'middle', 'K', 70),
+    ('71', '学術・開発研究機関', 'middle', 'L', 71),
+    ('72', '専門サービス業（他に分類されないもの）', 'middle', 'L', 72),
+    ('73', '広告業', 'middle', 'L', 73),
+    ('74', '技術サービス業（他に分類されないもの）', 'middle', 'L', 74),
+    ('75', '宿泊業', 'middle', 'M', 75),
+    ('76', '飲食店', 'middle', 'M', 76),
+    ('77', '持ち帰り・配達飲食サービス業', 'middle', 'M', 77),
+    ('78', '洗濯・理容・美容・浴場業', 'middle', 'N', 78),
+    ('79', 'その他の生活関連サービス業', 'middle', 'N', 79),
+    ('80', '娯楽業', 'middle', 'N', 80),
+    ('81', '学校教育', 'middle', 'O', 81),
+    ('82', 'その他の教育，学習支援業', 'middle', 'O', 82),
+    ('83', '医療業', 'middle', 'P', 83),
+    ('84', '保健衛生', 'middle', 'P', 84),
+    ('85', '社会保険・社会福祉・介護事業', 'middle', 'P', 85),
+    ('86', '郵便局', 'middle', 'Q', 86),
+    ('87', '協同組合（他に分類されないもの）', 'middle', 'Q', 87),
+    ('88', '廃棄物処理業', 'middle', 'R', 88),
+    ('89', '自動車整備業', 'middle', 'R', 89),
+    ('90', '機械等修理業（別掲を除く）', 'middle', 'R', 90),
+    ('91', '職業紹介・労働者派遣業', 'middle', 'R', 91),
+    ('92', 'その他の事業サービス業', 'middle', 'R', 92),
+    ('93', '政治・経済・文化団体', 'middle', 'R', 93),
+    ('94', '宗教', 'middle', 'R', 94),
+    ('95', 'その他のサービス業', 'middle', 'R', 95),
+    ('96', '外国公務', 'middle', 'R', 96),
+    ('97', '国家公務', 'middle', 'S', 97),
+    ('98', '地方公務', 'middle', 'S', 98),
+    ('99', '分類不能の産業', 'middle', 'T', 99);
+
+CREATE TABLE domain_industries (
+    domain_id INT NOT NULL COMMENT 'ドメインID',
+    industry_code VARCHAR(2) NOT NULL COMMENT '中分類コード',
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '作成日時',
+
+    PRIMARY KEY (domain_id, industry_code),
+    -- インデックス
+    INDEX idx_domain_industries_industry_code (industry_code)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='ドメインと業種の紐付けテーブル';
+
+CREATE TABLE homsta_industries (
+    homsta_id INT NOT NULL COMMENT 'HomstaID',
+    industry_code VARCHAR(2) NOT NULL COMMENT '中分類コード',
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '作成日時',
+
+    PRIMARY KEY (homsta_id, industry_code),
+    -- インデックス
+    INDEX idx_homsta_industries_industry_code (industry_code)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Homstaと業種の紐付けテーブル';
+
+-- 既存の業種（自由記述）のうち、業種名と一致するものを紐付ける
+INSERT IGNORE INTO domain_industries (domain_id, industry_code)
+SELECT d.id, i.code
+FROM domains d
+JOIN industries i ON i.level = 'middle' AND i.name = TRIM(d.industry);
+
+INSERT IGNORE INTO homsta_industries (homsta_id, industry_code)
+SELECT h.id, i.code
+FROM homstas h
+JOIN industries i ON i.level = 'middle'
+    AND FIND_IN_SET(i.name, REPLACE(REPLACE(h.industry, ' ', ''), '、', ',')) > 0;
+
+-- +migrate Down
+DROP TABLE IF EXISTS homsta_industries;
+DROP TABLE IF EXISTS domain_industries;
+DROP TABLE IF EXISTS industries;
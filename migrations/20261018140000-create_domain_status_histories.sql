@@ -0,0 +1,17 @@
+-- +migrate Up
+CREATE TABLE domain_status_histories (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    domain_id INT NOT NULL COMMENT 'ドメインID',
+    from_status VARCHAR(50) NOT NULL COMMENT '変更前ステータス',
+    to_status VARCHAR(50) NOT NULL COMMENT '変更後ステータス',
+    actor VARCHAR(100) NOT NULL DEFAULT '' COMMENT '変更した主体（api, polling, analyzeなど）',
+    reason VARCHAR(500) NOT NULL DEFAULT '' COMMENT '理由',
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '作成日時',
+
+    -- インデックス
+    INDEX idx_domain_status_histories_domain_id (domain_id, created_at),
+    INDEX idx_domain_status_histories_to_status (to_status)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='ドメインのステータス変更履歴テーブル';
+
+-- +migrate Down
+DROP TABLE IF EXISTS domain_status_histories;
@@ -0,0 +1,13 @@
+-- +migrate Up
+ALTER TABLE domains
+    ADD COLUMN status_updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT 'ステータス更新日時' AFTER status,
+    ADD COLUMN attempts INT NOT NULL DEFAULT 0 COMMENT '現在のステータスでの再送回数' AFTER status_updated_at,
+    ADD INDEX idx_domains_status_status_updated_at (status, status_updated_at);
+
+UPDATE domains SET status_updated_at = updated_at;
+
+-- +migrate Down
+ALTER TABLE domains
+    DROP INDEX idx_domains_status_status_updated_at,
+    DROP COLUMN attempts,
+    DROP COLUMN status_updated_at;
@@ -0,0 +1,27 @@
+-- +migrate Up
+CREATE TABLE outbox_messages (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    message_id VARCHAR(36) NOT NULL COMMENT 'メッセージID（受信側の重複排除に使う）',
+    topic VARCHAR(255) NOT NULL COMMENT '送信先トピック',
+    payload TEXT NOT NULL COMMENT '送信するJSON',
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' COMMENT 'pending / sent / failed',
+    attempts INT NOT NULL DEFAULT 0 COMMENT '送信を試みた回数',
+    last_error TEXT COMMENT '最後の送信エラー',
+    next_attempt_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '次に送信を試みる日時',
+    sent_at DATETIME NULL COMMENT '送信日時',
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE KEY uq_outbox_messages_message_id (message_id),
+    INDEX idx_outbox_messages_status_next_attempt_at (status, next_attempt_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Pub/Subへの送信待ちメッセージ';
+
+CREATE TABLE processed_messages (
+    message_id VARCHAR(36) NOT NULL COMMENT 'メッセージID',
+    consumer VARCHAR(100) NOT NULL COMMENT '処理した受信側',
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (message_id, consumer)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='処理済みメッセージ';
+
+-- +migrate Down
+DROP TABLE IF EXISTS processed_messages;
+DROP TABLE IF EXISTS outbox_messages;
@@ -0,0 +1,27 @@
+-- +migrate Up
+CREATE TABLE schedules (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    name VARCHAR(100) NOT NULL COMMENT 'ジョブ名',
+    cron_expr VARCHAR(100) NOT NULL COMMENT 'cron式（分 時 日 月 曜日）',
+    paused BOOLEAN NOT NULL DEFAULT FALSE COMMENT '一時停止中',
+    next_run_at DATETIME NULL COMMENT '次に実行する日時',
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE KEY uq_schedules_name (name)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='定期実行するジョブ';
+
+CREATE TABLE schedule_runs (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    schedule_name VARCHAR(100) NOT NULL COMMENT 'ジョブ名',
+    `trigger` VARCHAR(20) NOT NULL COMMENT 'cron / manual',
+    status VARCHAR(20) NOT NULL COMMENT 'running / succeeded / failed / skipped',
+    error TEXT COMMENT '失敗した場合のエラー',
+    instance VARCHAR(255) NOT NULL DEFAULT '' COMMENT '実行したインスタンス',
+    started_at DATETIME NOT NULL COMMENT '開始日時',
+    finished_at DATETIME NULL COMMENT '終了日時',
+    INDEX idx_schedule_runs_schedule_name_started_at (schedule_name, started_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='ジョブの実行履歴';
+
+-- +migrate Down
+DROP TABLE IF EXISTS schedule_runs;
+DROP TABLE IF EXISTS schedules;
@@ -0,0 +1,22 @@
+-- +migrate Up
+ALTER TABLE targets
+    ADD COLUMN weight INT NOT NULL DEFAULT 1 COMMENT '1回のFetchで進めるページ数の上限（0は取得しない）',
+    ADD COLUMN failure_count INT NOT NULL DEFAULT 0 COMMENT '連続して取得に失敗した回数',
+    ADD COLUMN next_fetch_at DATETIME NULL DEFAULT NULL COMMENT '失敗後、次に取得を試みる日時',
+    ADD COLUMN last_error TEXT COMMENT '最後の取得エラー';
+
+CREATE TABLE api_call_ledgers (
+    date DATE NOT NULL COMMENT '日付',
+    api VARCHAR(50) NOT NULL COMMENT '外部API',
+    calls INT NOT NULL DEFAULT 0 COMMENT '呼び出し回数',
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (date, api)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='外部APIの日別呼び出し回数';
+
+-- +migrate Down
+DROP TABLE IF EXISTS api_call_ledgers;
+ALTER TABLE targets
+    DROP COLUMN last_error,
+    DROP COLUMN next_fetch_at,
+    DROP COLUMN failure_count,
+    DROP COLUMN weight;
@@ -0,0 +1,13 @@
+-- +migrate Up
+ALTER TABLE targets
+    ADD COLUMN source VARCHAR(50) NOT NULL DEFAULT 'viewdns' COMMENT '逆引きの取得元（viewdns, passive_dns）' AFTER name;
+
+ALTER TABLE domains
+    ADD COLUMN source VARCHAR(50) NOT NULL DEFAULT 'viewdns' COMMENT 'ドメインを取得した逆引きの取得元' AFTER target;
+
+-- +migrate Down
+ALTER TABLE domains
+    DROP COLUMN source;
+
+ALTER TABLE targets
+    DROP COLUMN source;
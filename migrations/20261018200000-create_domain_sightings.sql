@@ -0,0 +1,29 @@
+-- +migrate Up
+CREATE TABLE domain_sightings (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    domain VARCHAR(255) NOT NULL COMMENT 'ドメイン名',
+    target_ip VARCHAR(45) NOT NULL COMMENT '逆引きで見つけたターゲットのIP',
+    last_resolved DATE NULL DEFAULT NULL COMMENT '取得元が最後に名前解決を確認した日',
+    first_seen DATETIME NOT NULL COMMENT '最初に見つけた日時',
+    last_seen DATETIME NOT NULL COMMENT '最後に見つけた日時',
+    moved_away_at DATETIME NULL DEFAULT NULL COMMENT 'ターゲットを1周取り直して見つからなかった日時',
+
+    -- インデックス
+    UNIQUE KEY uq_domain_sightings_domain_target_ip (domain, target_ip),
+    INDEX idx_domain_sightings_target_ip (target_ip, last_seen)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='ドメインを逆引きで見つけた記録';
+
+ALTER TABLE domains
+    ADD COLUMN moved_away_at DATETIME NULL DEFAULT NULL COMMENT 'すべてのターゲットで見つからなくなった日時' AFTER source;
+
+ALTER TABLE targets
+    ADD COLUMN scan_started_at DATETIME NULL DEFAULT NULL COMMENT '1ページ目から取り直し始めた日時' AFTER last_full_scan_at;
+
+-- +migrate Down
+ALTER TABLE targets
+    DROP COLUMN scan_started_at;
+
+ALTER TABLE domains
+    DROP COLUMN moved_away_at;
+
+DROP TABLE IF EXISTS domain_sightings;
@@ -0,0 +1,22 @@
+-- +migrate Up
+CREATE TABLE target_scans (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    target_id INT NOT NULL COMMENT 'ターゲットID',
+    started_at DATETIME NOT NULL COMMENT '1ページ目を取得した日時',
+    finished_at DATETIME NULL DEFAULT NULL COMMENT '最後のページまで取得した日時（取得中はNULL）',
+    pages INT NOT NULL DEFAULT 0 COMMENT '取得したページ数',
+    domains_found INT NOT NULL DEFAULT 0 COMMENT '逆引きで見つけたドメイン数',
+    new_domains INT NOT NULL DEFAULT 0 COMMENT 'そのうち初めて登録したドメイン数',
+
+    -- インデックス
+    INDEX idx_target_scans_target_id (target_id, started_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='ターゲットを1周取得した記録';
+
+ALTER TABLE targets
+    ADD COLUMN max_page INT NOT NULL DEFAULT 0 COMMENT '最後に取得したときの総ページ数' AFTER current_page;
+
+-- +migrate Down
+ALTER TABLE targets
+    DROP COLUMN max_page;
+
+DROP TABLE IF EXISTS target_scans;
@@ -0,0 +1,80 @@
+-- +migrate Up
+ALTER TABLE targets
+    ADD COLUMN platform VARCHAR(32) NOT NULL DEFAULT 'generic' COMMENT 'サイトの基盤（generic, wix）' AFTER source;
+
+ALTER TABLE domains
+    ADD COLUMN platform VARCHAR(32) NOT NULL DEFAULT 'generic' COMMENT 'サイトの基盤（generic, wix）' AFTER source,
+    ADD INDEX idx_domains_platform (platform);
+
+UPDATE targets SET platform = 'wix' WHERE name = 'WIX';
+UPDATE domains SET platform = 'wix' WHERE target = 'WIX';
+
+-- Downで元に戻せるように、移す前の wixes とドメインへの反映のしかたを残す
+CREATE TABLE wixes_merged (
+    name VARCHAR(255) NOT NULL PRIMARY KEY COMMENT '名前',
+    owner_id VARCHAR(255) NOT NULL COMMENT 'オーナーID',
+    domain_inserted TINYINT(1) NOT NULL COMMENT 'このマイグレーションでドメインとして登録したか',
+    prev_owner_id VARCHAR(255) NULL DEFAULT NULL COMMENT '登録済みのドメインの移す前の owner_id',
+    prev_is_japan TINYINT(1) NULL DEFAULT NULL COMMENT '登録済みのドメインの移す前の is_japan',
+    prev_can_view TINYINT(1) NULL DEFAULT NULL COMMENT '登録済みのドメインの移す前の can_view'
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='domainsに移したWix情報（Down用）';
+
+INSERT INTO wixes_merged (name, owner_id, domain_inserted, prev_owner_id, prev_is_japan, prev_can_view)
+SELECT w.name, w.owner_id, d.id IS NULL, d.owner_id, d.is_japan, d.can_view
+FROM wixes w
+    LEFT JOIN domains d ON d.name = w.name;
+
+-- cmd/crawl はオーナーIDが見つからなかったときに '-'・'−' を入れていたため、オーナーIDなしとして空にする
+UPDATE wixes SET owner_id = '' WHERE owner_id IN ('-', '−');
+
+-- cmd/wix・cmd/crawl で集めたWixのサイトをドメインに移す
+-- 登録済みのドメインはオーナーIDと、オーナーIDがあれば日本のサイト・閲覧可の判定を引き継ぐ
+UPDATE domains d
+    JOIN wixes w ON w.name = d.name
+SET d.platform = 'wix',
+    d.owner_id = IF(d.owner_id IN ('', '-', '−'), w.owner_id, d.owner_id),
+    d.is_japan = d.is_japan OR w.owner_id <> '',
+    d.can_view = d.can_view OR w.owner_id <> '';
+
+-- cmd/crawl でオーナーIDを取り出せたもの（日本語のWixサイト）は check_wix を済ませたとみなし、日本のサイト・閲覧可として crawl_comp_info から始める
+-- 会社情報はまだないため、GPTの解析（/api/analyze）は以前と同じく行う
+-- オーナーIDがないものは cmd/crawl がまだ調べていないため initialize から処理する
+INSERT IGNORE INTO domains (name, target, source, platform, owner_id, is_japan, can_view, raw_page, crawled_urls, status)
+SELECT name, 'WIX', 'viewdns', 'wix', owner_id, owner_id <> '', owner_id <> '', '', '', IF(owner_id = '', 'initialize', 'crawl_comp_info')
+FROM wixes;
+
+DROP TABLE IF EXISTS wixes;
+
+-- +migrate Down
+CREATE TABLE wixes (
+    name VARCHAR(255) NOT NULL UNIQUE COMMENT '名前',
+    owner_id VARCHAR(255) NOT NULL COMMENT 'オーナーID',
+
+    -- インデックス
+    INDEX idx_wixes_owner_id (owner_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Wix情報テーブル';
+
+INSERT INTO wixes (name, owner_id)
+SELECT name, owner_id FROM wixes_merged;
+
+-- Upで引き継いだオーナーIDと日本のサイト・閲覧可の判定を戻し、Upで登録したドメインを消す
+-- Up以降にWixのターゲットで見つけたドメインは wixes に戻さない
+UPDATE domains d
+    JOIN wixes_merged m ON m.name = d.name
+SET d.owner_id = m.prev_owner_id,
+    d.is_japan = m.prev_is_japan,
+    d.can_view = m.prev_can_view
+WHERE NOT m.domain_inserted;
+
+DELETE d FROM domains d
+    JOIN wixes_merged m ON m.name = d.name
+WHERE m.domain_inserted;
+
+DROP TABLE wixes_merged;
+
+ALTER TABLE domains
+    DROP INDEX idx_domains_platform,
+    DROP COLUMN platform;
+
+ALTER TABLE targets
+    DROP COLUMN platform;
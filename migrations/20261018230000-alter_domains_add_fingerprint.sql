@@ -0,0 +1,17 @@
+-- +migrate Up
+ALTER TABLE domains
+    ADD COLUMN cms VARCHAR(32) NOT NULL DEFAULT '' COMMENT 'サイトを作ったCMS・ビルダー（wordpress, wix, jimdo, shopify, studio, ameba_ownd, static, other, unknown）' AFTER platform,
+    ADD COLUMN cms_version VARCHAR(64) NOT NULL DEFAULT '' COMMENT 'CMSのバージョンの手がかり（generatorなど）' AFTER cms,
+    ADD COLUMN hosting_ip VARCHAR(45) NOT NULL DEFAULT '' COMMENT 'サイトのIPアドレス' AFTER cms_version,
+    ADD COLUMN fingerprinted_at DATETIME NULL DEFAULT NULL COMMENT 'CMSを判定した日時' AFTER hosting_ip,
+    ADD INDEX idx_domains_cms (cms),
+    ADD INDEX idx_domains_fingerprinted_at (fingerprinted_at);
+
+-- +migrate Down
+ALTER TABLE domains
+    DROP INDEX idx_domains_fingerprinted_at,
+    DROP INDEX idx_domains_cms,
+    DROP COLUMN fingerprinted_at,
+    DROP COLUMN hosting_ip,
+    DROP COLUMN cms_version,
+    DROP COLUMN cms;
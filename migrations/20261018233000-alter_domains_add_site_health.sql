@@ -0,0 +1,17 @@
+-- +migrate Up
+ALTER TABLE domains
+    ADD COLUMN ssl_issuer VARCHAR(255) NOT NULL DEFAULT '' COMMENT 'SSL証明書の発行者' AFTER is_ssl,
+    ADD COLUMN ssl_expires_at DATETIME NULL DEFAULT NULL COMMENT 'SSL証明書の有効期限' AFTER ssl_issuer,
+    ADD COLUMN redirect_url VARCHAR(2048) NOT NULL DEFAULT '' COMMENT 'ほかのサイトにリダイレクトしている場合の転送先' AFTER ssl_expires_at,
+    ADD COLUMN is_parked TINYINT(1) NOT NULL DEFAULT 0 COMMENT 'ドメインパーキングのページか' AFTER redirect_url,
+    ADD COLUMN health_checked_at DATETIME NULL DEFAULT NULL COMMENT 'サイトの死活・SSLを確認した日時' AFTER is_parked,
+    ADD INDEX idx_domains_ssl_expires_at (ssl_expires_at);
+
+-- +migrate Down
+ALTER TABLE domains
+    DROP INDEX idx_domains_ssl_expires_at,
+    DROP COLUMN health_checked_at,
+    DROP COLUMN is_parked,
+    DROP COLUMN redirect_url,
+    DROP COLUMN ssl_expires_at,
+    DROP COLUMN ssl_issuer;
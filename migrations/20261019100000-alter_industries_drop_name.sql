@@ -0,0 +1,133 @@
+-- +migrate Up
+-- 業種名は entity.IndustryMajors・entity.Industries だけで持ち、テーブルにはコードと階層だけを残す
+ALTER TABLE industries
+    DROP INDEX idx_industries_name,
+    DROP COLUMN name;
+
+-- +migrate Down
+ALTER TABLE industries
+    ADD COLUMN name VARCHAR(255) NOT NULL DEFAULT '' COMMENT '名称' AFTER code,
+    ADD INDEX idx_industries_name (name);
+
+UPDATE industries SET name = CASE code
+    WHEN 'A' THEN '農業，林業'
+    WHEN 'B' THEN '漁業'
+    WHEN 'C' THEN '鉱業，採石業，砂利採取業'
+    WHEN 'D' THEN '建設業'
+    WHEN 'E' THEN '製造業'
+    WHEN 'F' THEN '電気・ガス・熱供給・水道業'
+    WHEN 'G' THEN '情報通信業'
+    WHEN 'H' THEN '運輸業，郵便業'
+    WHEN 'I' THEN '卸売業，小売業'
+    WHEN 'J' THEN '金融業，保険業'
+    WHEN 'K' THEN '不動産業，物品賃貸業'
+    WHEN 'L' THEN '学術研究，専門・技術サービス業'
+    WHEN 'M' THEN '宿泊業，飲食サービス業'
+    WHEN 'N' THEN '生活関連サービス業，娯楽業'
+    WHEN 'O' THEN '教育，学習支援業'
+    WHEN 'P' THEN '医療，福祉'
+    WHEN 'Q' THEN '複合サービス事業'
+    WHEN 'R' THEN 'サービス業（他に分類されないもの）'
+    WHEN 'S' THEN '公務（他に分類されるものを除く）'
+    WHEN 'T' THEN '分類不能の産業'
+    WHEN '01' THEN '農業'
+    WHEN '02' THEN '林業'
+    WHEN '03' THEN '漁業（水産養殖業を除く）'
+    WHEN '04' THEN '水産養殖業'
+    WHEN '05' THEN '鉱業，採石業，砂利採取業'
+    WHEN '06' THEN '総合工事業'
+    WHEN '07' THEN '職別工事業(設備工事業を除く)'
+    WHEN '08' THEN '設備工事業'
+    WHEN '09' THEN '食料品製造業'
+    WHEN '10' THEN '飲料・たばこ・飼料製造業'
+    WHEN '11' THEN '繊維工業'
+    WHEN '12' THEN '木材・木製品製造業（家具を除く）'
+    WHEN '13' THEN '家具・装備品製造業'
+    WHEN '14' THEN 'パルプ・紙・紙加工品製造業'
+    WHEN '15' THEN '印刷・同関連業'
+    WHEN '16' THEN '化学工業'
+    WHEN '17' THEN '石油製品・石炭製品製造業'
+    WHEN '18' THEN 'プラスチック製品製造業（別掲を除く）'
+    WHEN '19' THEN 'ゴム製品製造業'
+    WHEN '20' THEN 'なめし革・同製品・毛皮製造業'
+    WHEN '21' THEN '窯業・土石製品製造業'
+    WHEN '22' THEN '鉄鋼業'
+    WHEN '23' THEN '非鉄金属製造業'
+    WHEN '24' THEN '金属製品製造業'
+    WHEN '25' THEN 'はん用機械器具製造業'
+    WHEN '26' THEN '生産用機械器具製造業'
+    WHEN '27' THEN '業務用機械器具製造業'
+    WHEN '28' THEN '電子部品・デバイス・電子回路製造業'
+    WHEN '29' THEN '電気機械器具製造業'
+    WHEN '30' THEN '情報通信機械器具製造業'
+    WHEN '31' THEN '輸送用機械器具製造業'
+    WHEN '32' THEN 'その他の製造業'
+    WHEN '33' THEN '電気業'
+    WHEN '34' THEN 'ガス業'
+    WHEN '35' THEN '熱供給業'
+    WHEN '36' THEN '水道業'
+    WHEN '37' THEN '通信業'
+    WHEN '38' THEN '放送業'
+    WHEN '39' THEN '情報サービス業'
+    WHEN '40' THEN 'インターネット附随サービス業'
+    WHEN '41' THEN '映像・音声・文字情報制作業'
+    WHEN '42' THEN '鉄道業'
+    WHEN '43' THEN '道路旅客運送業'
+    WHEN '44' THEN '道路貨物運送業'
+    WHEN '45' THEN '水運業'
+    WHEN '46' THEN '航空運輸業'
+    WHEN '47' THEN '倉庫業'
+    WHEN '48' THEN '運輸に附帯するサービス業'
+    WHEN '49' THEN '郵便業（信書便事業を含む）'
+    WHEN '50' THEN '各種商品卸売業'
+    WHEN '51' THEN '繊維・衣服等卸売業'
+    WHEN '52' THEN '飲食料品卸売業'
+    WHEN '53' THEN '建築材料，鉱物・金属材料等卸売業'
+    WHEN '54' THEN '機械器具卸売業'
+    WHEN '55' THEN 'その他の卸売業'
+    WHEN '56' THEN '各種商品小売業'
+    WHEN '57' THEN '織物・衣服・身の回り品小売業'
+    WHEN '58' THEN '飲食料品小売業'
+    WHEN '59' THEN '機械器具小売業'
+    WHEN '60' THEN 'その他の小売業'
+    WHEN '61' THEN '無店舗小売業'
+    WHEN '62' THEN '銀行業'
+    WHEN '63' THEN '協同組織金融業'
+    WHEN '64' THEN '貸金業，クレジットカード業等非預金信用機関'
+    WHEN '65' THEN '金融商品取引業，商品先物取引業'
+    WHEN '66' THEN '補助的金融業等'
+    WHEN '67' THEN '保険業（保険媒介代理業，保険サービス業を含む）'
+    WHEN '68' THEN '不動産取引業'
+    WHEN '69' THEN '不動産賃貸業・管理業'
+    WHEN '70' THEN '物品賃貸業'
+    WHEN '71' THEN '学術・開発研究機関'
+    WHEN '72' THEN '専門サービス業（他に分類されないもの）'
+    WHEN '73' THEN '広告業'
+    WHEN '74' THEN '技術サービス業（他に分類されないもの）'
+    WHEN '75' THEN '宿泊業'
+    WHEN '76' THEN '飲食店'
+    WHEN '77' THEN '持ち帰り・配達飲食サービス業'
+    WHEN '78' THEN '洗濯・理容・美容・浴場業'
+    WHEN '79' THEN 'その他の生活関連サービス業'
+    WHEN '80' THEN '娯楽業'
+    WHEN '81' THEN '学校教育'
+    WHEN '82' THEN 'その他の教育，学習支援業'
+    WHEN '83' THEN '医療業'
+    WHEN '84' THEN '保健衛生'
+    WHEN '85' THEN '社会保険・社会福祉・介護事業'
+    WHEN '86' THEN '郵便局'
+    WHEN '87' THEN '協同組合（他に分類されないもの）'
+    WHEN '88' THEN '廃棄物処理業'
+    WHEN '89' THEN '自動車整備業'
+    WHEN '90' THEN '機械等修理業（別掲を除く）'
+    WHEN '91' THEN '職業紹介・労働者派遣業'
+    WHEN '92' THEN 'その他の事業サービス業'
+    WHEN '93' THEN '政治・経済・文化団体'
+    WHEN '94' THEN '宗教'
+    WHEN '95' THEN 'その他のサービス業'
+    WHEN '96' THEN '外国公務'
+    WHEN '97' THEN '国家公務'
+    WHEN '98' THEN '地方公務'
+    WHEN '99' THEN '分類不能の産業'
+    ELSE name
+END;
@@ -0,0 +1,30 @@
+-- +migrate Up
+-- Downで消せるように、補った記録のドメインとIPを残す
+CREATE TABLE domain_sightings_backfilled (
+    domain VARCHAR(255) NOT NULL COMMENT 'ドメイン名',
+    target_ip VARCHAR(45) NOT NULL COMMENT 'ターゲットのIP',
+    PRIMARY KEY (domain, target_ip)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='domain_sightingsに補った記録（Down用）';
+
+-- domain_sightings より前に登録したドメインは、ターゲット名が1つのターゲットにしか使われていない場合に限りそのIPで見つけたことにする
+INSERT INTO domain_sightings_backfilled (domain, target_ip)
+SELECT d.name, t.ip
+FROM domains d
+JOIN targets t ON t.name = d.target
+WHERE d.target IN (
+    SELECT name FROM targets GROUP BY name HAVING COUNT(*) = 1
+)
+AND NOT EXISTS (
+    SELECT 1 FROM domain_sightings s WHERE s.domain = d.name
+);
+
+INSERT INTO domain_sightings (domain, target_ip, first_seen, last_seen)
+SELECT b.domain, b.target_ip, d.created_at, d.created_at
+FROM domain_sightings_backfilled b
+JOIN domains d ON d.name = b.domain;
+
+-- +migrate Down
+DELETE s FROM domain_sightings s
+JOIN domain_sightings_backfilled b ON b.domain = s.domain AND b.target_ip = s.target_ip;
+
+DROP TABLE IF EXISTS domain_sightings_backfilled;
@@ -1,3 +0,0 @@
-#!/bin/bash
-
-curl -X POST localhost:8050/api/growth/fetch >> /var/www/sales/batch.log 2>&1
\ No newline at end of file